@@ -0,0 +1,54 @@
+package nimbus
+
+import "net/http"
+
+// ToHTTPHandler adapts a nimbus Handler to a standard http.Handler.
+// The handler's (data, statusCode, error) result is rendered with the same response
+// logic as the router, so it can be mounted in a non-nimbus server or wrapped by
+// existing net/http middleware.
+//
+// Path values captured by a ServeMux pattern are read with ctx.Request.PathValue;
+// ctx.Param only sees parameters matched by a nimbus Router.
+//
+// Example:
+//
+//	mux := http.NewServeMux()
+//	mux.Handle("/health", nimbus.ToHTTPHandler(healthCheck))
+//	mux.Handle("GET /users/{id}", nimbus.ToHTTPHandler(getUser))
+func ToHTTPHandler(handler Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := NewContext(w, req)
+		defer ctx.Release() // Return context to pool when done
+
+		data, statusCode, err := handler(ctx)
+		writeResponse(ctx, data, statusCode, err, renderOptions{envelope: true})
+	})
+}
+
+// FromHTTPHandler adapts a standard http.Handler to a nimbus Handler.
+// The wrapped handler writes directly to ctx.Writer, so this returns (nil, 0, nil)
+// to signal the response was already written. Path parameters matched by the router
+// are available to it through Request.PathValue, as with http.ServeMux patterns.
+//
+// Example:
+//
+//	router.AddRoute(http.MethodGet, "/debug/vars", nimbus.FromHTTPHandler(expvar.Handler()))
+func FromHTTPHandler(handler http.Handler) Handler {
+	return func(ctx *Context) (any, int, error) {
+		for name, value := range ctx.PathParams {
+			ctx.Request.SetPathValue(name, value)
+		}
+		handler.ServeHTTP(ctx.Writer, ctx.Request)
+		return nil, 0, nil
+	}
+}
+
+// WrapHTTP adapts a standard http.HandlerFunc to a nimbus Handler. It is an alias for
+// FromHTTPHandler, which takes an http.HandlerFunc as it is:
+//
+//	router.GET("/users/:id", nimbus.FromHTTPHandler(http.HandlerFunc(getUser)))
+//
+// Deprecated: Use FromHTTPHandler.
+func WrapHTTP(handler http.HandlerFunc) Handler {
+	return FromHTTPHandler(handler)
+}
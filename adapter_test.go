@@ -0,0 +1,193 @@
+package nimbus
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestToHTTPHandler(t *testing.T) {
+	handler := ToHTTPHandler(func(ctx *Context) (any, int, error) {
+		return map[string]string{"name": ctx.Query("name")}, http.StatusOK, nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/hello?name=nimbus", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+
+	var response SuccessResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	data, ok := response.Data.(map[string]any)
+	if !response.Success || !ok || data["name"] != "nimbus" {
+		t.Errorf("expected success envelope with name 'nimbus', got %+v", response)
+	}
+}
+
+func TestToHTTPHandler_Error(t *testing.T) {
+	handler := ToHTTPHandler(func(ctx *Context) (any, int, error) {
+		return nil, http.StatusNotFound, NewAPIError("not_found", "user not found")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+
+	var response ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Error != "not_found" || response.Message != "user not found" {
+		t.Errorf("unexpected error response: %+v", response)
+	}
+}
+
+func TestFromHTTPHandler(t *testing.T) {
+	router := NewRouter()
+
+	router.AddRoute(http.MethodGet, "/std", FromHTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("from net/http"))
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/std", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("expected status 202, got %d", w.Code)
+	}
+
+	if w.Body.String() != "from net/http" {
+		t.Errorf("expected body 'from net/http', got %q", w.Body.String())
+	}
+}
+
+func TestHTTPHandler_RoundTrip(t *testing.T) {
+	original := func(ctx *Context) (any, int, error) {
+		return map[string]string{"status": "ok"}, http.StatusCreated, nil
+	}
+
+	// nimbus -> net/http -> nimbus
+	router := NewRouter()
+	router.AddRoute(http.MethodPost, "/items", FromHTTPHandler(ToHTTPHandler(original)))
+
+	req := httptest.NewRequest(http.MethodPost, "/items", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected status 201, got %d", w.Code)
+	}
+
+	var response SuccessResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	data, ok := response.Data.(map[string]any)
+	if !ok || data["status"] != "ok" {
+		t.Errorf("expected data {status: ok}, got %+v", response.Data)
+	}
+}
+
+func TestWrapHTTP(t *testing.T) {
+	router := NewRouter()
+
+	router.GET("/users/:id/files/*path", WrapHTTP(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("user " + r.PathValue("id") + " file " + r.PathValue("path")))
+	}), WithMiddleware(func(next Handler) Handler {
+		return func(ctx *Context) (any, int, error) {
+			ctx.Header("X-Wrapped", "true")
+			return next(ctx)
+		}
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42/files/docs/a.txt", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+	if w.Body.String() != "user 42 file docs/a.txt" {
+		t.Errorf("expected path params in body, got %q", w.Body.String())
+	}
+	if w.Header().Get("X-Wrapped") != "true" {
+		t.Error("expected route middleware to run around the wrapped handler")
+	}
+}
+
+func TestToHTTPHandler_ServeMux(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /health", ToHTTPHandler(func(ctx *Context) (any, int, error) {
+		return map[string]string{"status": "ok"}, http.StatusOK, nil
+	}))
+	mux.Handle("GET /users/{id}", ToHTTPHandler(func(ctx *Context) (any, int, error) {
+		return map[string]string{"id": ctx.Request.PathValue("id")}, http.StatusOK, nil
+	}))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		path  string
+		key   string
+		value string
+	}{
+		{"/health", "status", "ok"},
+		{"/users/42", "id", "42"},
+	}
+
+	for _, tt := range tests {
+		resp, err := http.Get(server.URL + tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var response SuccessResponse
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("%s: expected 200 application/json, got %d %q", tt.path, resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+		data, ok := response.Data.(map[string]any)
+		if !response.Success || !ok || data[tt.key] != tt.value {
+			t.Errorf("%s: expected success envelope with %s=%s, got %+v", tt.path, tt.key, tt.value, response)
+		}
+	}
+
+	// Unmatched paths are handled by the mux, not the nimbus handler
+	resp, err := http.Get(server.URL + "/missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected mux 404, got %d", resp.StatusCode)
+	}
+}
@@ -1,21 +1,59 @@
 package nimbus
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
+	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
+	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
-const (
-	ContextKeyValidatedBody   = "validated_body"
-	ContextKeyValidatedQuery  = "validated_query"
-	ContextKeyValidatedParams = "validated_params"
+// ContextKey is the type of the keys the framework uses for values it stores on a Context.
+// Because it is a distinct type, a ContextKey never collides with a plain string key set by
+// application code: ctx.Set("user", ...) and ctx.Set(ContextKeyUser, ...) are separate entries.
+// Reading a string key with nothing set under it falls back to the ContextKey of the same
+// name, so code written before the typed keys (ctx.Get("user")) keeps working.
+type ContextKey string
 
-	StatusCodeKey = "status_code"
+// Reserved context keys set by the framework and its middleware.
+const (
+	// ContextKeyValidatedBody holds the body bound by WithBodyValidation, WithVersionedBody or WithTyped.
+	ContextKeyValidatedBody ContextKey = "validated_body"
+	// ContextKeyValidatedQuery holds the query struct bound by WithQueryValidation or WithTyped.
+	ContextKeyValidatedQuery ContextKey = "validated_query"
+	// ContextKeyValidatedParams holds the path params struct bound by WithPathParams or WithTyped.
+	ContextKeyValidatedParams ContextKey = "validated_params"
+	// ContextKeyBodyFields holds the FieldSet of JSON fields present in the body bound by BindAndValidateJSON.
+	ContextKeyBodyFields ContextKey = "body_fields"
+	// ContextKeyUser holds the value returned by the token validator in middleware.Auth.
+	ContextKeyUser ContextKey = "user"
+	// ContextKeyRequestID holds the request ID set by middleware.RequestID.
+	ContextKeyRequestID ContextKey = "request_id"
+
+	// StatusCodeKey holds the status code of responses written directly (JSON, Data, Redirect).
+	StatusCodeKey ContextKey = "status_code"
 )
 
+// ErrEmptyBody is returned by BindAndValidateJSON when the request has no body (or only
+// whitespace). An empty object ({}) is not empty: it proceeds to schema validation.
+var ErrEmptyBody = NewAPIError("empty_body", "Request body is required")
+
+// ErrUnsupportedMediaType is returned by BindBody for a Content-Type it can't bind.
+// Handlers typically respond to it with 415 Unsupported Media Type.
+var ErrUnsupportedMediaType = NewAPIError("unsupported_media_type", "Content-Type must be JSON, a URL-encoded form, or multipart form data")
+
+// multipartMemory is how much of a multipart body BindBody keeps in memory; larger file
+// parts are spooled to temporary files (see http.Request.ParseMultipartForm)
+const multipartMemory = 32 << 20
+
 // A sync.Pool for Context objects to reduce allocations.
 var contextPool = sync.Pool{
 	New: func() any {
@@ -30,6 +68,12 @@ var contextPool = sync.Pool{
 	},
 }
 
+// ServerTiming is a single Server-Timing metric recorded with Context.AddServerTiming.
+type ServerTiming struct {
+	Name     string
+	Duration time.Duration
+}
+
 // Context is a wrapper around http request/response with helpers.
 // Access context.Context via c.Request.Context() for cancellation, timeouts, and tracing.
 // It is request-scoped and should be passed through the handler chain.
@@ -38,14 +82,27 @@ type Context struct {
 	Request *http.Request
 	// PathParams contains extracted path parameters from the route (e.g., :id, :name).
 	PathParams map[string]string
+	// rawPathParams holds the path params as matched, before percent-decoding.
+	// Nil unless the request path had escapes net/http can't decode in URL.Path (e.g. %2F).
+	rawPathParams map[string]string
 	// queryCache stores parsed query parameters to avoid re-parsing on each Query() call.
 	// Lazily initialized on first Query() access. Saves significant overhead for endpoints
 	// that access multiple query parameters (pagination, filtering, search, etc.).
 	queryCache url.Values
 	// values is a request-scoped key-value store for middleware communication.
 	// Used to pass data between middleware and handlers (e.g., request_id, user, validated_body).
+	// Keys are ContextKey for framework values or any comparable key (usually a string) for user values.
 	// Private to force use of the Context.Set and Context.Get methods.
-	values map[string]any
+	values map[any]any
+	// route is the matched route (nil when no route matched).
+	route *Route
+	// serverTimings holds metrics recorded with AddServerTiming (emitted by middleware.ServerTiming).
+	serverTimings []ServerTiming
+	// aborted is set by Abort; the route handler is skipped once it is true.
+	aborted bool
+	// refs counts the request plus goroutines started with Go; the last one to finish
+	// returns the context to the pool.
+	refs atomic.Int32
 }
 
 // NewContext grabs a context from the pool and initializes it.
@@ -55,6 +112,7 @@ func NewContext(w http.ResponseWriter, r *http.Request) *Context {
 	ctx := contextPool.Get().(*Context)
 	ctx.Writer = w
 	ctx.Request = r
+	ctx.refs.Store(1)
 	return ctx
 }
 
@@ -62,6 +120,10 @@ func NewContext(w http.ResponseWriter, r *http.Request) *Context {
 func (c *Context) reset() {
 	c.Writer = nil
 	c.Request = nil
+	c.route = nil
+	c.rawPathParams = nil
+	c.serverTimings = c.serverTimings[:0]
+	c.aborted = false
 
 	// Strategy: Keep maps allocated if they're small (≤8 entries = 1 bucket)
 	// Only recreate if they grew too large (to prevent memory bloat from pooling huge maps)
@@ -84,7 +146,7 @@ func (c *Context) reset() {
 	if c.values != nil {
 		if len(c.values) > 8 {
 			// Map grew too large, recreate with reasonable capacity (1 bucket)
-			c.values = make(map[string]any, 8)
+			c.values = make(map[any]any, 8)
 		} else {
 			// Map is small, just clear and reuse the allocation
 			clear(c.values)
@@ -93,12 +155,27 @@ func (c *Context) reset() {
 }
 
 // Release the context to the pool for reuse.
-// Should be called after request handling is complete.
+// Should be called after request handling is complete. If goroutines started with Go are
+// still running, the context goes back to the pool when the last of them finishes.
 func (c *Context) Release() {
+	if c.refs.Add(-1) > 0 {
+		return
+	}
 	c.reset()
 	contextPool.Put(c)
 }
 
+// Go runs fn in a new goroutine that may keep using the context after the request ends,
+// for middleware that stops waiting on the rest of the chain, such as middleware.Timeout.
+// The context isn't reset and reused by another request until fn returns.
+func (c *Context) Go(fn func()) {
+	c.refs.Add(1)
+	go func() {
+		defer c.Release()
+		fn()
+	}()
+}
+
 // Param retrieves a path parameter by name safely (handles nil PathParams).
 // Returns empty string if parameter doesn't exist.
 // Example: id := ctx.Param("id")
@@ -109,6 +186,40 @@ func (c *Context) Param(name string) string {
 	return c.PathParams[name]
 }
 
+// RawParam retrieves a path parameter exactly as the route tree matched it, before any
+// typing or validation (e.g. by WithTyped). Useful for debugging param binding failures.
+// For a path with an encoded slash, it is also the value before percent-decoding:
+// /files/a%2Fb matching /files/:name gives Param "a/b" and RawParam "a%2Fb".
+// Returns empty string if parameter doesn't exist.
+func (c *Context) RawParam(name string) string {
+	if c.rawPathParams != nil {
+		return c.rawPathParams[name]
+	}
+	if c.PathParams == nil {
+		return ""
+	}
+	return c.PathParams[name]
+}
+
+// Route returns the route matched for this request, or nil if no route matched (404).
+// Requests served by a Router.Default handler report that catch-all route.
+// Generic handlers and middleware can use it to act on the route's pattern or metadata.
+func (c *Context) Route() *Route {
+	return c.route
+}
+
+// AddServerTiming records a sub-measurement (e.g. a database query) for the Server-Timing
+// response header. Metrics are only emitted when the ServerTiming middleware is installed.
+// Name should be a token without spaces or commas, e.g. "db" or "cache".
+func (c *Context) AddServerTiming(name string, d time.Duration) {
+	c.serverTimings = append(c.serverTimings, ServerTiming{Name: name, Duration: d})
+}
+
+// ServerTimings returns the metrics recorded with AddServerTiming, in the order they were added.
+func (c *Context) ServerTimings() []ServerTiming {
+	return c.serverTimings
+}
+
 // Query retrieves a query parameter by name.
 // The parsed query parameters are cached after the first call to avoid re-parsing
 // on subsequent Query() calls. This provides significant performance benefits for
@@ -121,28 +232,259 @@ func (c *Context) Query(name string) string {
 	return c.queryCache.Get(name)
 }
 
+// QueryArray returns every value of a repeated query parameter (?tag=a&tag=b), or nil if absent.
+// Like Query, it reads from the cached parsed query string.
+func (c *Context) QueryArray(name string) []string {
+	if c.queryCache == nil {
+		c.queryCache = c.Request.URL.Query()
+	}
+	return c.queryCache[name]
+}
+
+// QueryMap collects bracketed query parameters under prefix into a map, so
+// ?filter[status]=open&filter[type]=bug returns {"status": "open", "type": "bug"} for "filter".
+// Only single-level keys are collected: empty (filter[]=x) and nested (filter[a][b]=x) keys
+// are skipped. A repeated key maps to its first value, as with Query. The result is never nil.
+func (c *Context) QueryMap(prefix string) map[string]string {
+	if c.queryCache == nil {
+		c.queryCache = c.Request.URL.Query()
+	}
+
+	result := make(map[string]string)
+	for name, values := range c.queryCache {
+		key, ok := strings.CutPrefix(name, prefix+"[")
+		if !ok || len(values) == 0 {
+			continue
+		}
+		key, ok = strings.CutSuffix(key, "]")
+		if !ok || key == "" || strings.ContainsAny(key, "[]") {
+			continue
+		}
+		result[key] = values[0]
+	}
+	return result
+}
+
 // Bind and validate query parameters using a schema to a struct.
 func (c *Context) BindAndValidateQuery(target any, schema *Schema) error {
 	return ValidateQuery(c.Request.URL.Query(), target, schema)
 }
 
+// Bind and validate a URL-encoded form body (application/x-www-form-urlencoded) using a schema to a struct.
+// Fields are matched by their form tag, falling back to the JSON name.
+func (c *Context) BindAndValidateForm(target any, schema *Schema) error {
+	if err := c.Request.ParseForm(); err != nil {
+		return err
+	}
+	return ValidateForm(c.Request.PostForm, target, schema)
+}
+
 // Bind and validate JSON using a schema to a struct.
+// The top-level fields present in the body are recorded for BodyFields.
+// A missing or empty body returns ErrEmptyBody instead of a JSON syntax error.
 func (c *Context) BindAndValidateJSON(target any, schema *Schema) error {
-	body, err := io.ReadAll(c.Request.Body)
+	body, err := c.readBody()
+	if err != nil {
+		return err
+	}
+
+	present, err := ValidateJSONWithPresence(body, target, schema)
+	if present != nil {
+		c.Set(ContextKeyBodyFields, present)
+	}
+	return err
+}
+
+// Bind and validate a partial JSON update using a schema to a struct.
+// Only the fields present in the body are validated (see Schema.ValidatePartial), and
+// they are recorded for BodyFields so the handler applies just those.
+func (c *Context) BindAndValidateJSONPartial(target any, schema *Schema) error {
+	body, err := c.readBody()
 	if err != nil {
 		return err
 	}
 
-	return ValidateJSON(body, target, schema)
+	present, err := ValidateJSONPartial(body, target, schema)
+	if present != nil {
+		c.Set(ContextKeyBodyFields, present)
+	}
+	return err
+}
+
+// BindBody binds and validates the request body according to its Content-Type, so one
+// handler can accept both the JSON an SPA sends and the form a browser posts:
+//   - application/json (or any +json type, or no Content-Type): as BindAndValidateJSON
+//   - application/x-www-form-urlencoded: as BindAndValidateForm
+//   - multipart/form-data: the form's values, as BindAndValidateForm (files stay in
+//     ctx.Request.MultipartForm)
+//
+// Other types return ErrUnsupportedMediaType.
+//
+//	if err := ctx.BindBody(&req, signupSchema); err != nil {
+//	    if err == nimbus.ErrUnsupportedMediaType {
+//	        return nil, http.StatusUnsupportedMediaType, err
+//	    }
+//	    ...
+//	}
+func (c *Context) BindBody(target any, schema *Schema) error {
+	contentType := c.Request.Header.Get("Content-Type")
+	if contentType == "" {
+		return c.BindAndValidateJSON(target, schema)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ErrUnsupportedMediaType
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return c.BindAndValidateJSON(target, schema)
+	case mediaType == "application/x-www-form-urlencoded":
+		return c.BindAndValidateForm(target, schema)
+	case mediaType == "multipart/form-data":
+		if err := c.Request.ParseMultipartForm(multipartMemory); err != nil {
+			return err
+		}
+		return ValidateForm(c.Request.PostForm, target, schema)
+	default:
+		return ErrUnsupportedMediaType
+	}
+}
+
+// BindAll binds and validates path parameters, the JSON body, and query parameters in one
+// call, for handlers that don't use WithTyped. Pass a nil target to skip a source.
+// Sources are processed in that order and the first failure is returned: ValidationErrors
+// when a schema rejects the values, or the binding error otherwise. paramsSchema may be
+// nil to bind path parameters without validating them, as WithPathParams does.
+//
+//	var params UserParams
+//	var body UpdateUserRequest
+//	var query UserFilters
+//	if err := ctx.BindAll(&params, paramsSchema, &body, bodySchema, &query, querySchema); err != nil {
+//	    ...
+//	}
+func (c *Context) BindAll(params any, paramsSchema *Schema, body any, bodySchema *Schema, query any, querySchema *Schema) error {
+	if params != nil {
+		if err := populatePathParamsWithSchema(c.PathParams, params, paramsSchema); err != nil {
+			return err
+		}
+		if paramsSchema != nil {
+			if errors := paramsSchema.Validate(params); len(errors) > 0 {
+				return errors
+			}
+		}
+	}
+
+	if body != nil {
+		if err := c.BindAndValidateJSON(body, bodySchema); err != nil {
+			return err
+		}
+	}
+
+	if query != nil {
+		if err := c.BindAndValidateQuery(query, querySchema); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readBody reads the request body for JSON binding, returning ErrEmptyBody if there is none
+func (c *Context) readBody() ([]byte, error) {
+	if c.Request.Body == nil {
+		return nil, ErrEmptyBody
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, ErrEmptyBody
+	}
+	return body, nil
+}
+
+// BodyFields returns the top-level JSON fields present in the body bound by BindAndValidateJSON
+// (or WithBodyValidation), so PATCH handlers can tell omitted fields from zero values:
+//
+//	if ctx.BodyFields().Has("age") {
+//	    user.Age = body.Age // sent, possibly as 0
+//	}
+//
+// Returns nil (on which Has reports false) if no JSON object body has been bound.
+// BindAndValidateJSONPartial records the set as well.
+func (c *Context) BodyFields() FieldSet {
+	value, _ := c.Get(ContextKeyBodyFields)
+	present, _ := value.(FieldSet)
+	return present
+}
+
+// ValidatedBody returns the body bound by WithBodyValidation, WithVersionedBody or WithTyped,
+// or false if none was bound or it isn't a *T. Middleware can read it once the handler
+// returns, e.g. to audit-log the parsed request:
+//
+//	data, status, err := next(ctx)
+//	if body, ok := nimbus.ValidatedBody[CreateUserRequest](ctx); ok {
+//	    audit.Record(ctx, body.Email)
+//	}
+func ValidatedBody[T any](c *Context) (*T, bool) {
+	return validated[T](c, ContextKeyValidatedBody)
+}
+
+// ValidatedQuery returns the query struct bound by WithQueryValidation or WithTyped,
+// or false if none was bound or it isn't a *T.
+func ValidatedQuery[T any](c *Context) (*T, bool) {
+	return validated[T](c, ContextKeyValidatedQuery)
+}
+
+// ValidatedParams returns the path params struct bound by WithPathParams or WithTyped,
+// or false if none was bound or it isn't a *T.
+func ValidatedParams[T any](c *Context) (*T, bool) {
+	return validated[T](c, ContextKeyValidatedParams)
+}
+
+func validated[T any](c *Context, key ContextKey) (*T, bool) {
+	value, _ := c.Get(key)
+	typed, ok := value.(*T)
+	return typed, ok
 }
 
 // Set writer with standardized validation error response.
+// Messages are localized for the request's Accept-Language (see RegisterMessages).
 // Returns (nil, 0, nil) to signal the handler that the response has been written.
 func (c *Context) SendValidationError(errors ValidationErrors) (any, int, error) {
-	return c.JSON(http.StatusBadRequest, map[string]any{
+	return c.sendValidationError(http.StatusBadRequest, errors)
+}
+
+// SendValidationErrorWithHeaders is SendValidationError with extra response headers, such as a
+// correlation ID or a Link to the API's documentation. Each given header replaces any value
+// already set under its name.
+//
+//	return ctx.SendValidationErrorWithHeaders(errs, http.Header{
+//	    "Link": {`<https://docs.example.com/errors/validation>; rel="help"`},
+//	})
+//
+// Handlers returning ValidationErrors as an error (sent as 422) can set headers with Header
+// before returning instead.
+func (c *Context) SendValidationErrorWithHeaders(errors ValidationErrors, headers http.Header) (any, int, error) {
+	for key, values := range headers {
+		c.Writer.Header().Del(key)
+		for _, value := range values {
+			c.Writer.Header().Add(key, value)
+		}
+	}
+	return c.sendValidationError(http.StatusBadRequest, errors)
+}
+
+// sendValidationError writes the validation error response with the given status
+func (c *Context) sendValidationError(statusCode int, errors ValidationErrors) (any, int, error) {
+	return c.JSON(statusCode, map[string]any{
 		"error":   "validation_failed",
 		"message": "Request validation failed",
-		"details": errors,
+		"details": localize(errors, c.GetHeader("Accept-Language")),
 	})
 }
 
@@ -168,6 +510,102 @@ func (c *Context) HTML(statusCode int, html string) (any, int, error) {
 	return c.Data(statusCode, "text/html; charset=utf-8", []byte(html))
 }
 
+// Set writer with CSV response, encoded with encoding/csv so fields containing commas,
+// quotes, or newlines are quoted. A non-empty filename is sent as a Content-Disposition
+// attachment so browsers download the export.
+// Returns (nil, 0, nil) to signal the handler that the response has been written.
+func (c *Context) CSV(statusCode int, filename string, rows [][]string) (any, int, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, 0, err
+	}
+
+	if filename != "" {
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	}
+	return c.Data(statusCode, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// Set writer with CSV response built from a slice of structs (or struct pointers).
+// The header row comes from each exported field's csv tag, falling back to its JSON name
+// and then the field name; fields tagged csv:"-" are skipped. Values are formatted with
+// fmt.Sprint; nil pointer fields are written as empty fields and nil elements are skipped.
+// Returns (nil, 0, nil) to signal the handler that the response has been written.
+func (c *Context) CSVStructs(statusCode int, filename string, items any) (any, int, error) {
+	rows, err := csvRows(items)
+	if err != nil {
+		return nil, 0, err
+	}
+	return c.CSV(statusCode, filename, rows)
+}
+
+// csvRows converts a slice of structs into a header row followed by one row per element
+func csvRows(items any) ([][]string, error) {
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("CSVStructs: expected a slice of structs, got %T", items)
+	}
+
+	elemType := v.Type().Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("CSVStructs: expected a slice of structs, got %T", items)
+	}
+
+	var header []string
+	var indices []int
+	for i := 0; i < elemType.NumField(); i++ {
+		field := elemType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Tag.Get("csv")
+		if name == "" {
+			name, _, _ = strings.Cut(field.Tag.Get("json"), ",")
+		}
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		header = append(header, name)
+		indices = append(indices, i)
+	}
+
+	rows := make([][]string, 0, v.Len()+1)
+	rows = append(rows, header)
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		if elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				continue
+			}
+			elem = elem.Elem()
+		}
+
+		row := make([]string, len(indices))
+		for j, index := range indices {
+			field := elem.Field(index)
+			if field.Kind() == reflect.Ptr {
+				if field.IsNil() {
+					continue
+				}
+				field = field.Elem()
+			}
+			row[j] = fmt.Sprint(field.Interface())
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
 // Set writer with raw bytes as response.
 // Returns (nil, 0, nil) to signal the handler that the response has been written.
 func (c *Context) Data(statusCode int, contentType string, data []byte) (any, int, error) {
@@ -185,7 +623,28 @@ func (c *Context) Redirect(statusCode int, location string) {
 	http.Redirect(c.Writer, c.Request, location, statusCode)
 }
 
-// Header sets a response header.
+// Abort stops the chain: the route handler will not run, even if a middleware still calls next.
+// The aborting middleware is responsible for the response, either by writing it (e.g. with ctx.JSON)
+// or by returning it. Outer middleware can check IsAborted after next returns.
+func (c *Context) Abort() {
+	c.aborted = true
+}
+
+// IsAborted reports whether Abort (or AbortWithError) was called for this request.
+func (c *Context) IsAborted() bool {
+	return c.aborted
+}
+
+// AbortWithError aborts the chain and returns the error response for the middleware to return.
+// Example: return ctx.AbortWithError(http.StatusForbidden, nimbus.NewAPIError("forbidden", "Admins only"))
+func (c *Context) AbortWithError(statusCode int, err error) (any, int, error) {
+	c.Abort()
+	return nil, statusCode, err
+}
+
+// Header sets a response header. Headers set before the response is written are sent with
+// whatever the router renders, success or error, so middleware such as CORS can set them
+// before calling next even if the handler then fails.
 func (c *Context) Header(key, value string) {
 	c.Writer.Header().Set(key, value)
 }
@@ -196,29 +655,41 @@ func (c *Context) GetHeader(key string) string {
 }
 
 // Set stores a value in the context.
+// The key may be a ContextKey, a string, or any other comparable value.
 // Lazy-initializes the values map on first use.
-func (c *Context) Set(key string, value any) {
+func (c *Context) Set(key any, value any) {
 	if c.values == nil {
-		c.values = make(map[string]any, 8)
+		c.values = make(map[any]any, 8)
 	}
 	c.values[key] = value
 }
 
 // Get retrieves a value from the context.
-func (c *Context) Get(key string) (any, bool) {
+// A string key with no value of its own falls back to the ContextKey of the same name.
+func (c *Context) Get(key any) (any, bool) {
 	if c.values == nil {
 		return nil, false
 	}
 	value, exists := c.values[key]
+	if name, isString := key.(string); isString && !exists {
+		value, exists = c.values[ContextKey(name)]
+	}
 	return value, exists
 }
 
-// GetString retrieves a string value from the context.
-func (c *Context) GetString(key string) string {
-	if c.values == nil {
-		return ""
+// MustGet retrieves a value from the context and panics if the key does not exist.
+// Use it only where a preceding middleware guarantees the value (e.g. the Auth user);
+// the panic is turned into a 500 response by the Recovery middleware.
+func (c *Context) MustGet(key any) any {
+	if value, exists := c.Get(key); exists {
+		return value
 	}
-	if value, ok := c.values[key]; ok {
+	panic(fmt.Sprintf("context key %v does not exist", key))
+}
+
+// GetString retrieves a string value from the context.
+func (c *Context) GetString(key any) string {
+	if value, ok := c.Get(key); ok {
 		if str, ok := value.(string); ok {
 			return str
 		}
@@ -227,11 +698,8 @@ func (c *Context) GetString(key string) string {
 }
 
 // GetInt retrieves an int value from the context.
-func (c *Context) GetInt(key string) int {
-	if c.values == nil {
-		return 0
-	}
-	if value, ok := c.values[key]; ok {
+func (c *Context) GetInt(key any) int {
+	if value, ok := c.Get(key); ok {
 		if i, ok := value.(int); ok {
 			return i
 		}
@@ -240,11 +708,8 @@ func (c *Context) GetInt(key string) int {
 }
 
 // GetBool retrieves a bool value from the context.
-func (c *Context) GetBool(key string) bool {
-	if c.values == nil {
-		return false
-	}
-	if value, ok := c.values[key]; ok {
+func (c *Context) GetBool(key any) bool {
+	if value, ok := c.Get(key); ok {
 		if b, ok := value.(bool); ok {
 			return b
 		}
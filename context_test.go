@@ -0,0 +1,750 @@
+package nimbus
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContext_RawParam(t *testing.T) {
+	router := NewRouter()
+
+	type IntParams struct {
+		ID int `path:"id"` // unsupported type, so binding fails
+	}
+
+	handler := func(ctx *Context, req *TypedRequest[IntParams, struct{}, struct{}]) (any, int, error) {
+		return nil, http.StatusOK, nil
+	}
+
+	var raw string
+	captureRaw := func(next Handler) Handler {
+		return func(ctx *Context) (any, int, error) {
+			data, status, err := next(ctx)
+			raw = ctx.RawParam("id")
+			return data, status, err
+		}
+	}
+
+	router.AddRoute(http.MethodGet, "/users/:id", WithTyped(handler, NewValidator(&IntParams{}), nil, nil), captureRaw)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/abc", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 from typed binding, got %d", w.Code)
+	}
+	if raw != "abc" {
+		t.Errorf("Expected raw param 'abc', got %q", raw)
+	}
+}
+
+func TestContext_RawParam_Missing(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	defer ctx.Release()
+
+	if got := ctx.RawParam("id"); got != "" {
+		t.Errorf("Expected empty string for missing param, got %q", got)
+	}
+}
+
+func TestContext_Abort(t *testing.T) {
+	router := NewRouter()
+
+	handlerCalled := false
+	var abortedAfter bool
+
+	// Outer middleware observes the short-circuit after next returns
+	router.Use(func(next Handler) Handler {
+		return func(ctx *Context) (any, int, error) {
+			data, status, err := next(ctx)
+			abortedAfter = ctx.IsAborted()
+			return data, status, err
+		}
+	})
+
+	// Aborts but still calls next, which must not reach the handler
+	aborting := func(next Handler) Handler {
+		return func(ctx *Context) (any, int, error) {
+			if ctx.IsAborted() {
+				t.Error("Context should not start aborted")
+			}
+			ctx.Abort()
+			ctx.JSON(http.StatusTeapot, map[string]string{"status": "aborted"})
+			return next(ctx)
+		}
+	}
+
+	router.GET("/abort", func(ctx *Context) (any, int, error) {
+		handlerCalled = true
+		return map[string]string{"status": "handled"}, http.StatusOK, nil
+	}, WithMiddleware(aborting))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/abort", nil))
+
+	if handlerCalled {
+		t.Error("Handler should not run after Abort")
+	}
+	if !abortedAfter {
+		t.Error("Expected IsAborted to be true after the chain was aborted")
+	}
+	if w.Code != http.StatusTeapot {
+		t.Errorf("Expected status 418, got %d", w.Code)
+	}
+}
+
+func TestContext_AbortWithError(t *testing.T) {
+	router := NewRouter()
+
+	handlerCalled := false
+	router.GET("/admin", func(ctx *Context) (any, int, error) {
+		handlerCalled = true
+		return nil, http.StatusOK, nil
+	}, WithMiddleware(func(next Handler) Handler {
+		return func(ctx *Context) (any, int, error) {
+			return ctx.AbortWithError(http.StatusForbidden, NewAPIError("forbidden", "Admins only"))
+		}
+	}))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin", nil))
+
+	if handlerCalled {
+		t.Error("Handler should not run after AbortWithError")
+	}
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", w.Code)
+	}
+	if body := w.Body.String(); !strings.Contains(body, `"forbidden"`) {
+		t.Errorf("Expected forbidden error body, got %s", body)
+	}
+}
+
+func TestContext_AbortResetOnRelease(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	ctx.Abort()
+	ctx.reset()
+
+	if ctx.IsAborted() {
+		t.Error("Expected reset to clear the aborted flag")
+	}
+}
+
+func TestContext_GoDelaysRelease(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	ctx.Set("user", "ada")
+	ctx.Abort()
+
+	proceed := make(chan struct{})
+	type seen struct {
+		user    any
+		aborted bool
+	}
+	done := make(chan seen)
+	ctx.Go(func() {
+		<-proceed
+		user, _ := ctx.Get("user")
+		done <- seen{user, ctx.IsAborted()}
+	})
+
+	// The request finishes first; the goroutine must still see its state
+	ctx.Release()
+	close(proceed)
+
+	if got := <-done; got.user != "ada" || !got.aborted {
+		t.Errorf("Expected the context untouched until the goroutine returned, got %+v", got)
+	}
+}
+
+func TestContext_GetStringKeyFallsBackToContextKey(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	defer ctx.Release()
+
+	ctx.Set(ContextKeyRequestID, "req-42")
+	if got := ctx.GetString("request_id"); got != "req-42" {
+		t.Errorf("Expected the string key to find the ContextKey value, got %q", got)
+	}
+	if value, ok := ctx.Get("request_id"); !ok || value != "req-42" {
+		t.Errorf("Expected Get to fall back too, got %v, %v", value, ok)
+	}
+
+	// A value set under the string itself takes precedence, and never leaks into the typed key
+	ctx.Set("request_id", "app-7")
+	if got := ctx.GetString("request_id"); got != "app-7" {
+		t.Errorf("Expected the string key's own value, got %q", got)
+	}
+	if got := ctx.GetString(ContextKeyRequestID); got != "req-42" {
+		t.Errorf("Expected the typed key unchanged, got %q", got)
+	}
+}
+
+func TestContext_MustGet(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	defer ctx.Release()
+
+	ctx.Set(ContextKeyUser, "alice")
+
+	if user := ctx.MustGet(ContextKeyUser); user != "alice" {
+		t.Errorf("Expected 'alice', got %v", user)
+	}
+}
+
+func TestContext_MustGet_MissingPanics(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	defer ctx.Release()
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Expected panic for missing key")
+		}
+		if msg, _ := r.(string); !strings.Contains(msg, "user") {
+			t.Errorf("Expected panic message to name the key, got %v", r)
+		}
+	}()
+
+	ctx.MustGet(ContextKeyUser)
+}
+
+func TestContext_Route(t *testing.T) {
+	router := NewRouter()
+
+	var matched *Route
+	capture := func(ctx *Context) (any, int, error) {
+		matched = ctx.Route()
+		return nil, http.StatusOK, nil
+	}
+
+	router.GET("/health", capture)
+	router.GET("/users/:id", capture)
+	router.Route(http.MethodGet, "/users/:id").WithDoc(RouteMetadata{
+		Summary: "Get user",
+		Tags:    []string{"users"},
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	if matched == nil || matched.Pattern() != "/health" || matched.Method() != http.MethodGet {
+		t.Fatalf("Expected static route GET /health, got %+v", matched)
+	}
+	if _, ok := matched.Metadata(); ok {
+		t.Error("Expected no metadata for /health")
+	}
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	if matched == nil || matched.Pattern() != "/users/:id" {
+		t.Fatalf("Expected dynamic route /users/:id, got %+v", matched)
+	}
+
+	metadata, ok := matched.Metadata()
+	if !ok || metadata.Summary != "Get user" {
+		t.Fatalf("Expected route metadata, got %+v", metadata)
+	}
+
+	// Mutating the returned copy must not affect the route
+	metadata.Tags[0] = "mutated"
+	if again, _ := matched.Metadata(); again.Tags[0] != "users" {
+		t.Errorf("Expected route metadata to be unaffected, got tags %v", again.Tags)
+	}
+}
+
+func TestContext_Route_NotFound(t *testing.T) {
+	router := NewRouter()
+
+	routeSeen := true
+	router.NotFound(func(ctx *Context) (any, int, error) {
+		routeSeen = ctx.Route() != nil
+		return nil, http.StatusNotFound, NewAPIError("not_found", "route not found")
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if routeSeen {
+		t.Error("Expected nil route when nothing matched")
+	}
+}
+
+// Test struct for form binding
+type TestSignupForm struct {
+	Username string `form:"user_name" json:"username" validate:"required,minlen=3"`
+	Email    string `json:"email" validate:"required,email"`
+	Age      int    `json:"age" validate:"min=13"`
+	Terms    bool   `form:"accept_terms" json:"terms"`
+}
+
+func TestContext_BindAndValidateForm(t *testing.T) {
+	schema := NewSchema(TestSignupForm{})
+
+	tests := []struct {
+		name        string
+		body        string
+		expectError bool
+		errorField  string
+	}{
+		{"all fields present", "user_name=jane&email=jane%40example.com&age=30&accept_terms=true", false, ""},
+		{"required field missing", "email=jane%40example.com&age=30", true, "username"},
+		{"json name is not the form key", "username=jane&email=jane%40example.com", true, "username"},
+		{"email falls back to json name", "user_name=jane&email=not-an-email", true, "email"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			ctx := NewContext(httptest.NewRecorder(), req)
+			defer ctx.Release()
+
+			var form TestSignupForm
+			err := ctx.BindAndValidateForm(&form, schema)
+
+			if !tt.expectError {
+				if err != nil {
+					t.Fatalf("Expected no error, got: %v", err)
+				}
+				if form.Username != "jane" || form.Email != "jane@example.com" || form.Age != 30 || !form.Terms {
+					t.Errorf("Expected form to be bound, got %+v", form)
+				}
+				return
+			}
+
+			validationErrors, ok := err.(ValidationErrors)
+			if !ok {
+				t.Fatalf("Expected ValidationErrors, got %T: %v", err, err)
+			}
+			found := false
+			for _, e := range validationErrors {
+				if e.Field == tt.errorField {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Expected error for field %s, got: %v", tt.errorField, validationErrors)
+			}
+		})
+	}
+}
+
+func TestContext_BindBody(t *testing.T) {
+	schema := NewSchema(TestSignupForm{})
+
+	multipartBody := func(fields map[string]string) (string, string) {
+		var buf bytes.Buffer
+		writer := multipart.NewWriter(&buf)
+		for name, value := range fields {
+			writer.WriteField(name, value)
+		}
+		writer.Close()
+		return buf.String(), writer.FormDataContentType()
+	}
+	validMultipart, multipartType := multipartBody(map[string]string{
+		"user_name": "jane", "email": "jane@example.com", "age": "30", "accept_terms": "true",
+	})
+	invalidMultipart, invalidMultipartType := multipartBody(map[string]string{"email": "not-an-email"})
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		errorField  string // Empty means the payload binds
+	}{
+		{"json", "application/json", `{"username":"jane","email":"jane@example.com","age":30,"terms":true}`, ""},
+		{"json with charset", "application/json; charset=utf-8", `{"username":"jane","email":"jane@example.com","age":30,"terms":true}`, ""},
+		{"json suffix type", "application/vnd.api+json", `{"username":"jane","email":"jane@example.com","age":30,"terms":true}`, ""},
+		{"no content type is json", "", `{"username":"jane","email":"jane@example.com","age":30,"terms":true}`, ""},
+		{"urlencoded", "application/x-www-form-urlencoded", "user_name=jane&email=jane%40example.com&age=30&accept_terms=true", ""},
+		{"multipart", multipartType, validMultipart, ""},
+		{"invalid json", "application/json", `{"username":"jane","email":"nope","age":30}`, "email"},
+		{"invalid urlencoded", "application/x-www-form-urlencoded", "user_name=jane&email=nope&age=30", "email"},
+		{"invalid multipart", invalidMultipartType, invalidMultipart, "username"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			ctx := NewContext(httptest.NewRecorder(), req)
+			defer ctx.Release()
+
+			var form TestSignupForm
+			err := ctx.BindBody(&form, schema)
+
+			if tt.errorField == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got: %v", err)
+				}
+				expected := TestSignupForm{Username: "jane", Email: "jane@example.com", Age: 30, Terms: true}
+				if form != expected {
+					t.Errorf("Expected %+v, got %+v", expected, form)
+				}
+				return
+			}
+
+			validationErrors, ok := err.(ValidationErrors)
+			if !ok {
+				t.Fatalf("Expected ValidationErrors, got %T: %v", err, err)
+			}
+			if !validationErrors.Has(tt.errorField) {
+				t.Errorf("Expected error for field %s, got: %v", tt.errorField, validationErrors)
+			}
+		})
+	}
+}
+
+func TestContext_BindBody_UnsupportedMediaType(t *testing.T) {
+	for _, contentType := range []string{"text/plain", "application/xml", "not a media type;;"} {
+		req := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader("username=jane"))
+		req.Header.Set("Content-Type", contentType)
+		ctx := NewContext(httptest.NewRecorder(), req)
+
+		var form TestSignupForm
+		if err := ctx.BindBody(&form, NewSchema(TestSignupForm{})); err != ErrUnsupportedMediaType {
+			t.Errorf("Expected ErrUnsupportedMediaType for %q, got %v", contentType, err)
+		}
+		ctx.Release()
+	}
+}
+
+func TestContext_BodyFields(t *testing.T) {
+	router := NewRouter()
+
+	var present FieldSet
+	var body *TestProfilePatch
+	router.PATCH("/profile", func(ctx *Context) (any, int, error) {
+		present = ctx.BodyFields()
+		body = ctx.MustGet(ContextKeyValidatedBody).(*TestProfilePatch)
+		return nil, http.StatusNoContent, nil
+	}, WithMiddleware(WithBodyValidation(NewValidator(&TestProfilePatch{}))))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPatch, "/profile", strings.NewReader(`{"age":0}`)))
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if body.Age != 0 || !present.Has("age") {
+		t.Errorf("Expected age present as zero, got %d (present: %v)", body.Age, present.Has("age"))
+	}
+	if present.Has("name") {
+		t.Error("Expected omitted name to be absent")
+	}
+
+	// Nothing bound yet
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	defer ctx.Release()
+	if ctx.BodyFields() != nil || ctx.BodyFields().Has("age") {
+		t.Error("Expected nil BodyFields before binding")
+	}
+}
+
+func TestContext_BindAndValidateJSONPartial(t *testing.T) {
+	schema := NewSchema(TestUser{})
+
+	req := httptest.NewRequest(http.MethodPatch, "/users/1", strings.NewReader(`{"age":30}`))
+	ctx := NewContext(httptest.NewRecorder(), req)
+	defer ctx.Release()
+
+	var user TestUser
+	if err := ctx.BindAndValidateJSONPartial(&user, schema); err != nil {
+		t.Fatalf("Expected absent required fields to be ignored, got: %v", err)
+	}
+	if !ctx.BodyFields().Has("age") || ctx.BodyFields().Has("name") {
+		t.Errorf("Expected only age to be present, got %v", ctx.BodyFields())
+	}
+}
+
+type TestUserParams struct {
+	ID string `path:"id" json:"id" validate:"required,minlen=3"`
+}
+
+func TestContext_BindAll(t *testing.T) {
+	paramsSchema := NewSchema(TestUserParams{})
+	bodySchema := NewSchema(TestUser{})
+	querySchema := NewSchema(TestSearchQuery{})
+
+	validBody := `{"name":"Ada","email":"ada@example.com","age":30,"role":"user","password":"secret123"}`
+	validQuery := "query=laptop&page=1&limit=10"
+
+	tests := []struct {
+		name        string
+		id          string
+		body        string
+		query       string
+		failedField string
+	}{
+		{"all valid", "usr_1", validBody, validQuery, ""},
+		{"invalid path param", "u1", validBody, validQuery, "id"},
+		{"invalid body", "usr_1", `{"name":"Ada","email":"not-an-email","password":"secret123","age":30}`, validQuery, "email"},
+		{"invalid query", "usr_1", validBody, "query=x&page=1&limit=10", "query"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/users/"+tt.id+"?"+tt.query, strings.NewReader(tt.body))
+			ctx := NewContext(httptest.NewRecorder(), req)
+			defer ctx.Release()
+			ctx.PathParams = map[string]string{"id": tt.id}
+
+			var params TestUserParams
+			var body TestUser
+			var query TestSearchQuery
+			err := ctx.BindAll(&params, paramsSchema, &body, bodySchema, &query, querySchema)
+
+			if tt.failedField == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if params.ID != "usr_1" || body.Email != "ada@example.com" || query.Query != "laptop" {
+					t.Errorf("Expected all sources bound, got %+v %+v %+v", params, body, query)
+				}
+				return
+			}
+
+			validationErrs, ok := err.(ValidationErrors)
+			if !ok {
+				t.Fatalf("Expected ValidationErrors, got %T: %v", err, err)
+			}
+			if len(validationErrs) != 1 || validationErrs[0].Field != tt.failedField {
+				t.Errorf("Expected a single error for %q, got %v", tt.failedField, validationErrs)
+			}
+		})
+	}
+}
+
+func TestContext_BindAll_SkipsNilTargets(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/users/usr_1?query=laptop&page=1&limit=10", nil)
+	ctx := NewContext(httptest.NewRecorder(), req)
+	defer ctx.Release()
+	ctx.PathParams = map[string]string{"id": "usr_1"}
+
+	var params TestUserParams
+	var query TestSearchQuery
+	if err := ctx.BindAll(&params, nil, nil, nil, &query, NewSchema(TestSearchQuery{})); err != nil {
+		t.Fatalf("Expected no error without a body, got %v", err)
+	}
+	if params.ID != "usr_1" || query.Query != "laptop" {
+		t.Errorf("Expected params and query bound, got %+v %+v", params, query)
+	}
+}
+
+func TestContext_BindAndValidateJSON_EmptyBody(t *testing.T) {
+	router := NewRouter()
+	router.POST("/users", func(ctx *Context) (any, int, error) {
+		return nil, http.StatusCreated, nil
+	}, WithMiddleware(WithBodyValidation(NewValidator(&TestUser{}))))
+
+	tests := []struct {
+		name      string
+		body      string
+		wantError string
+	}{
+		{"missing body", "", "empty_body"},
+		{"whitespace only", " \n\t", "empty_body"},
+		{"empty object proceeds to validation", "{}", "validation_failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body)))
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", w.Code)
+			}
+
+			var response struct {
+				Error   string            `json:"error"`
+				Details []ValidationError `json:"details"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			if response.Error != tt.wantError {
+				t.Errorf("Expected error %q, got %q", tt.wantError, response.Error)
+			}
+			if tt.wantError == "validation_failed" && len(response.Details) == 0 {
+				t.Error("Expected required-field validation details for {}")
+			}
+		})
+	}
+
+	// Direct callers can compare against the sentinel
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users", nil))
+	defer ctx.Release()
+	var user TestUser
+	if err := ctx.BindAndValidateJSON(&user, NewSchema(TestUser{})); err != ErrEmptyBody {
+		t.Errorf("Expected ErrEmptyBody, got %v", err)
+	}
+}
+
+func TestContext_SendValidationErrorWithHeaders(t *testing.T) {
+	errs := ValidationErrors{{Field: "email", Tag: "required", Message: "email is required"}}
+	docsLink := `<https://docs.example.com/errors/validation>; rel="help"`
+
+	tests := []struct {
+		name     string
+		handler  Handler
+		expected int
+	}{
+		{"sent", func(ctx *Context) (any, int, error) {
+			ctx.Header("X-Correlation-ID", "stale")
+			return ctx.SendValidationErrorWithHeaders(errs, http.Header{
+				"X-Correlation-ID": {"req-42"},
+				"Link":             {docsLink},
+			})
+		}, http.StatusBadRequest},
+		{"returned", func(ctx *Context) (any, int, error) {
+			ctx.Header("X-Correlation-ID", "req-42")
+			ctx.Header("Link", docsLink)
+			return nil, 0, errs
+		}, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter()
+			router.POST("/users", tt.handler)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users", nil))
+
+			if w.Code != tt.expected {
+				t.Fatalf("Expected status %d, got %d", tt.expected, w.Code)
+			}
+			if got := w.Header().Values("X-Correlation-ID"); len(got) != 1 || got[0] != "req-42" {
+				t.Errorf("Expected X-Correlation-ID req-42, got %v", got)
+			}
+			if got := w.Header().Get("Link"); got != docsLink {
+				t.Errorf("Expected Link %q, got %q", docsLink, got)
+			}
+			if got := w.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Expected Content-Type application/json, got %q", got)
+			}
+
+			var response struct {
+				Error   string            `json:"error"`
+				Details []ValidationError `json:"details"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			if response.Error != "validation_failed" || len(response.Details) != 1 || response.Details[0].Field != "email" {
+				t.Errorf("Expected the email validation error, got %+v", response)
+			}
+		})
+	}
+}
+
+func TestContext_CSV(t *testing.T) {
+	w := httptest.NewRecorder()
+	ctx := NewContext(w, httptest.NewRequest(http.MethodGet, "/export", nil))
+	defer ctx.Release()
+
+	rows := [][]string{
+		{"id", "name", "notes"},
+		{"1", "Widget, large", `says "hi"`},
+		{"2", "Gadget", "line one\nline two"},
+	}
+	if _, status, err := ctx.CSV(http.StatusOK, "report 2024.csv", rows); status != 0 || err != nil {
+		t.Fatalf("Expected (nil, 0, nil), got status %d, err %v", status, err)
+	}
+
+	expected := "id,name,notes\n" +
+		"1,\"Widget, large\",\"says \"\"hi\"\"\"\n" +
+		"2,Gadget,\"line one\nline two\"\n"
+	if w.Body.String() != expected {
+		t.Errorf("Expected body %q, got %q", expected, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != "text/csv; charset=utf-8" {
+		t.Errorf("Expected text/csv content type, got %q", got)
+	}
+	if got := w.Header().Get("Content-Disposition"); got != `attachment; filename="report 2024.csv"` {
+		t.Errorf("Expected attachment header, got %q", got)
+	}
+}
+
+func TestContext_CSVStructs(t *testing.T) {
+	type exportRow struct {
+		ID       int     `json:"id"`
+		Name     string  `csv:"product_name" json:"name"`
+		Price    float64 // no tags: field name
+		Discount *int    `json:"discount,omitempty"`
+		Secret   string  `csv:"-"`
+		internal string
+	}
+
+	discount := 10
+	items := []*exportRow{
+		{ID: 1, Name: "Widget, large", Price: 9.5, Discount: &discount, Secret: "x"},
+		nil,
+		{ID: 2, Name: "Gadget", Price: 3},
+	}
+
+	w := httptest.NewRecorder()
+	ctx := NewContext(w, httptest.NewRequest(http.MethodGet, "/export", nil))
+	defer ctx.Release()
+
+	if _, _, err := ctx.CSVStructs(http.StatusOK, "products.csv", items); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "id,product_name,Price,discount\n" +
+		"1,\"Widget, large\",9.5,10\n" +
+		"2,Gadget,3,\n"
+	if w.Body.String() != expected {
+		t.Errorf("Expected body %q, got %q", expected, w.Body.String())
+	}
+
+	if _, _, err := ctx.CSVStructs(http.StatusOK, "", []string{"a"}); err == nil {
+		t.Error("Expected error for a slice of non-structs")
+	}
+}
+
+func TestContext_QueryArray(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/items?tag=go&tag=http&tag=&page=2", nil)
+	ctx := NewContext(httptest.NewRecorder(), req)
+	defer ctx.Release()
+
+	if tags := ctx.QueryArray("tag"); len(tags) != 3 || tags[0] != "go" || tags[1] != "http" || tags[2] != "" {
+		t.Errorf("Expected [go http \"\"], got %q", tags)
+	}
+	if pages := ctx.QueryArray("page"); len(pages) != 1 || pages[0] != "2" {
+		t.Errorf("Expected [2], got %q", pages)
+	}
+	if missing := ctx.QueryArray("missing"); missing != nil {
+		t.Errorf("Expected nil for absent key, got %q", missing)
+	}
+}
+
+func TestContext_QueryMap(t *testing.T) {
+	query := "filter[status]=open&filter[type]=bug&filter[type]=task" +
+		"&filter[]=empty&filter[owner][name]=nested&filter=plain&filters[x]=other&filter[a%5Bb]=bracket" +
+		"&sort[created_at]=desc"
+	req := httptest.NewRequest(http.MethodGet, "/issues?"+query, nil)
+	ctx := NewContext(httptest.NewRecorder(), req)
+	defer ctx.Release()
+
+	filter := ctx.QueryMap("filter")
+	expected := map[string]string{"status": "open", "type": "bug"}
+	if len(filter) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, filter)
+	}
+	for k, v := range expected {
+		if filter[k] != v {
+			t.Errorf("Expected filter[%s]=%q, got %q", k, v, filter[k])
+		}
+	}
+
+	if sort := ctx.QueryMap("sort"); len(sort) != 1 || sort["created_at"] != "desc" {
+		t.Errorf("Expected {created_at: desc}, got %v", sort)
+	}
+	if missing := ctx.QueryMap("missing"); missing == nil || len(missing) != 0 {
+		t.Errorf("Expected empty non-nil map, got %v", missing)
+	}
+}
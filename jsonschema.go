@@ -0,0 +1,188 @@
+package nimbus
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+)
+
+// jsonSchemaDocument is the subset of a JSON Schema (draft-07) object schema ImportJSONSchema reads
+type jsonSchemaDocument struct {
+	Type       jsonSchemaType                `json:"type"`
+	Properties map[string]jsonSchemaProperty `json:"properties"`
+	Required   []string                      `json:"required"`
+}
+
+// jsonSchemaProperty is the subset of a property schema ImportJSONSchema maps to a fieldRule
+type jsonSchemaProperty struct {
+	Type      jsonSchemaType `json:"type"`
+	MinLength *int           `json:"minLength"`
+	MaxLength *int           `json:"maxLength"`
+	Minimum   *float64       `json:"minimum"`
+	Maximum   *float64       `json:"maximum"`
+	Pattern   string         `json:"pattern"`
+	Enum      []any          `json:"enum"`
+	Format    string         `json:"format"`
+}
+
+// jsonSchemaType is a schema's type keyword. Besides a single type name it accepts the
+// nullable form ["string", "null"], which keeps the non-null type.
+type jsonSchemaType string
+
+func (t *jsonSchemaType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*t = jsonSchemaType(name)
+		return nil
+	}
+
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return fmt.Errorf("type must be a string or an array of strings")
+	}
+	for _, name := range names {
+		if name == "null" {
+			continue
+		}
+		if *t != "" {
+			return fmt.Errorf("type %v: only one non-null type is supported", names)
+		}
+		*t = jsonSchemaType(name)
+	}
+	return nil
+}
+
+// ImportJSONSchema builds a Schema from a JSON Schema (draft-07) document describing an
+// object, for services that validate payloads defined in configuration rather than Go
+// structs. Validate decoded documents with the returned Schema's ValidateMap. ValidateJSON,
+// BindAndValidateJSON and WithBodyValidation validate the decoded body the same way, whatever
+// the target. The Schema has no struct type, so Validate reports a "root" error and query
+// and form binding find no fields.
+//
+// The supported keywords are type, properties, required, and per property minLength,
+// maxLength, minimum, maximum, pattern, enum (strings only) and format "email". Property
+// types (string, number, integer, boolean, array, object) are checked, including "integer"
+// rejecting fractional numbers. Unsupported keywords are ignored.
+//
+//	schema, err := nimbus.ImportJSONSchema(doc)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	errs := schema.ValidateMap(payload)
+func ImportJSONSchema(document []byte) (*Schema, error) {
+	var doc jsonSchemaDocument
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	if doc.Type != "object" {
+		return nil, fmt.Errorf("invalid JSON schema: expected type object, got %q", doc.Type)
+	}
+
+	schema := &Schema{fields: make(map[string]fieldRule, len(doc.Properties))}
+	for name, property := range doc.Properties {
+		rule, err := property.rule()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON schema: property %s: %w", name, err)
+		}
+		rule.jsonTag = name
+		rule.queryName = name
+		rule.formName = name
+		schema.fields[name] = rule
+	}
+
+	for _, name := range doc.Required {
+		rule, exists := schema.fields[name]
+		if !exists {
+			// A required property without constraints still has to be present
+			rule = fieldRule{jsonTag: name, queryName: name, formName: name, minLength: -1, maxLength: -1}
+		}
+		rule.required = true
+		schema.fields[name] = rule
+	}
+
+	return schema, nil
+}
+
+// rule maps a property schema to the equivalent validate tag rules
+func (p jsonSchemaProperty) rule() (fieldRule, error) {
+	rule := fieldRule{minLength: -1, maxLength: -1}
+
+	switch p.Type {
+	case "", "string", "number", "integer", "boolean", "array", "object":
+		rule.valueType = string(p.Type)
+	default:
+		return rule, fmt.Errorf("unsupported type %q", p.Type)
+	}
+
+	if p.MinLength != nil {
+		rule.minLength = *p.MinLength
+	}
+	if p.MaxLength != nil {
+		rule.maxLength = *p.MaxLength
+	}
+
+	rule.min, rule.max = p.Minimum, p.Maximum
+
+	if p.Pattern != "" {
+		var err error
+		if rule.pattern, err = regexp.Compile(p.Pattern); err != nil {
+			return rule, fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+
+	for _, value := range p.Enum {
+		str, ok := value.(string)
+		if !ok {
+			return rule, fmt.Errorf("enum value %v: only string values are supported", value)
+		}
+		rule.enum = append(rule.enum, str)
+	}
+
+	rule.email = p.Format == "email"
+
+	return rule, nil
+}
+
+// checkJSONType reports whether a decoded JSON value has the type an imported JSON Schema
+// property declares
+func checkJSONType(fieldName, valueType string, value any) (ValidationError, bool) {
+	var ok bool
+	var article string
+	switch valueType {
+	case "string":
+		_, ok = value.(string)
+		article = "a"
+	case "number":
+		_, ok = convertToInt(value)
+		article = "a"
+	case "integer":
+		if number, isNumber := value.(float64); isNumber {
+			ok = number == math.Trunc(number)
+		} else {
+			_, ok = convertToInt(value)
+		}
+		article = "an"
+	case "boolean":
+		_, ok = value.(bool)
+		article = "a"
+	case "array":
+		_, ok = value.([]any)
+		article = "an"
+	case "object":
+		_, ok = value.(map[string]any)
+		article = "an"
+	default:
+		return ValidationError{}, true
+	}
+
+	if ok {
+		return ValidationError{}, true
+	}
+	return ValidationError{
+		Field:   fieldName,
+		Value:   value,
+		Tag:     "type",
+		Message: fmt.Sprintf("%s must be %s %s", fieldName, article, valueType),
+	}, false
+}
@@ -0,0 +1,283 @@
+package nimbus
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const productJSONSchema = `{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"type": "object",
+	"properties": {
+		"name": {"type": "string", "minLength": 2, "maxLength": 40},
+		"sku": {"type": "string", "pattern": "^[A-Z]{3}-[0-9]{4}$"},
+		"price": {"type": "number", "minimum": 0, "maximum": 10000},
+		"quantity": {"type": "integer", "minimum": 1},
+		"status": {"type": "string", "enum": ["draft", "active", "archived"]},
+		"contact": {"type": ["string", "null"], "format": "email"},
+		"tags": {"type": "array", "items": {"type": "string"}},
+		"featured": {"type": "boolean"}
+	},
+	"required": ["name", "sku", "price", "owner"]
+}`
+
+func TestImportJSONSchema(t *testing.T) {
+	schema, err := ImportJSONSchema([]byte(productJSONSchema))
+	if err != nil {
+		t.Fatalf("ImportJSONSchema failed: %v", err)
+	}
+
+	decode := func(t *testing.T, document string) map[string]any {
+		t.Helper()
+		var data map[string]any
+		if err := json.Unmarshal([]byte(document), &data); err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	tests := []struct {
+		name     string
+		document string
+		errors   []string // "field:tag" for each expected error, in any order
+	}{
+		{
+			name:     "conforming document",
+			document: `{"name": "Lamp", "sku": "LMP-0001", "price": 49.5, "quantity": 3, "status": "active", "contact": null, "tags": ["home"], "featured": true, "owner": "ops", "extra": 1}`,
+		},
+		{
+			name:     "missing required properties",
+			document: `{"name": "Lamp"}`,
+			errors:   []string{"owner:required", "price:required", "sku:required"},
+		},
+		{
+			name:     "constraint violations",
+			document: `{"name": "L", "sku": "lamp", "price": 20000, "quantity": 0, "status": "sold", "contact": "nobody", "owner": "ops"}`,
+			errors:   []string{"contact:email", "name:minlen", "price:max", "quantity:min", "sku:pattern", "status:enum"},
+		},
+		{
+			name:     "type mismatches",
+			document: `{"name": 7, "sku": "LMP-0001", "price": "cheap", "quantity": 1.5, "tags": "home", "featured": "yes", "owner": "ops"}`,
+			errors:   []string{"featured:type", "name:type", "price:type", "quantity:type", "tags:type"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := schema.ValidateMap(decode(t, tt.document))
+
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Field+":"+e.Tag)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.errors) {
+				t.Errorf("Expected errors %v, got %v", tt.errors, errs)
+			}
+		})
+	}
+}
+
+func TestImportJSONSchema_TypeMessages(t *testing.T) {
+	schema, err := ImportJSONSchema([]byte(productJSONSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := schema.ValidateMap(map[string]any{"name": "Lamp", "sku": "LMP-0001", "price": 1.0, "owner": "ops", "quantity": 2.5, "tags": "home"})
+	if got := errs.ForField("quantity"); len(got) != 1 || got[0].Message != "quantity must be an integer" {
+		t.Errorf("Expected quantity must be an integer, got %v", got)
+	}
+	if got := errs.ForField("tags"); len(got) != 1 || got[0].Message != "tags must be an array" {
+		t.Errorf("Expected tags must be an array, got %v", got)
+	}
+}
+
+func TestImportJSONSchema_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		expected string
+	}{
+		{"not JSON", `{`, "invalid JSON schema"},
+		{"not an object schema", `{"type": "array"}`, "expected type object"},
+		{"unknown type", `{"type": "object", "properties": {"at": {"type": "date"}}}`, `unsupported type "date"`},
+		{"bad pattern", `{"type": "object", "properties": {"code": {"type": "string", "pattern": "("}}}`, "invalid pattern"},
+		{"numeric enum", `{"type": "object", "properties": {"level": {"enum": [1, 2]}}}`, "only string values"},
+		{"several types", `{"type": "object", "properties": {"id": {"type": ["string", "integer"]}}}`, "only one non-null type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ImportJSONSchema([]byte(tt.document))
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+// importedProduct is a struct bound from a body validated by an imported schema
+type importedProduct struct {
+	Name  string  `json:"name"`
+	SKU   string  `json:"sku"`
+	Price float64 `json:"price"`
+	Owner string  `json:"owner"`
+}
+
+func TestImportJSONSchema_FractionalBounds(t *testing.T) {
+	schema, err := ImportJSONSchema([]byte(`{"type": "object", "properties": {"rate": {"type": "number", "minimum": 0.01, "maximum": 0.5}}}`))
+	if err != nil {
+		t.Fatalf("ImportJSONSchema failed: %v", err)
+	}
+
+	if errs := schema.ValidateMap(map[string]any{"rate": 0.25}); len(errs) != 0 {
+		t.Errorf("Expected 0.25 to be within bounds, got %v", errs)
+	}
+	if errs := schema.ValidateMap(map[string]any{"rate": 0.75}); len(errs) != 1 || errs[0].Message != "rate must be at most 0.5" {
+		t.Errorf("Expected rate must be at most 0.5, got %v", errs)
+	}
+	if errs := schema.ValidateMap(map[string]any{"rate": 0.0}); len(errs) != 1 || errs[0].Message != "rate must be at least 0.01" {
+		t.Errorf("Expected rate must be at least 0.01, got %v", errs)
+	}
+}
+
+func TestImportJSONSchema_StructTargets(t *testing.T) {
+	schema, err := ImportJSONSchema([]byte(productJSONSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := schema.Validate(&importedProduct{Name: "Lamp"})
+	if len(errs) != 1 || errs[0].Field != "root" {
+		t.Errorf("Expected a root error from Validate, got %v", errs)
+	}
+
+	var product importedProduct
+	if err := ValidateJSON([]byte(`{"name": "Lamp", "sku": "LMP-0001", "price": 49.5, "owner": "ops"}`), &product, schema); err != nil {
+		t.Errorf("Expected a conforming body to bind, got %v", err)
+	}
+	if product.SKU != "LMP-0001" || product.Price != 49.5 {
+		t.Errorf("Expected the body bound into the struct, got %+v", product)
+	}
+
+	err = ValidateJSON([]byte(`{"name": "L", "sku": "LMP-0001", "price": 49.5}`), &product, schema)
+	if errs, ok := err.(ValidationErrors); !ok || !errs.Has("name") || !errs.Has("owner") {
+		t.Errorf("Expected name and owner errors, got %v", err)
+	}
+
+	// Partial bodies only report the fields they contain
+	_, err = ValidateJSONPartial([]byte(`{"price": 20000}`), &product, schema)
+	if errs, ok := err.(ValidationErrors); !ok || len(errs) != 1 || errs[0].Field != "price" {
+		t.Errorf("Expected only the price error, got %v", err)
+	}
+
+	var products []importedProduct
+	err = ValidateJSON([]byte(`[{"name": "Lamp", "sku": "LMP-0001", "price": 1, "owner": "ops"}, {"name": "Desk", "sku": "desk", "price": 1, "owner": "ops"}]`), &products, schema)
+	if errs, ok := err.(ValidationErrors); !ok || len(errs) != 1 || errs[0].Field != "[1].sku" {
+		t.Errorf("Expected only the [1].sku error, got %v", err)
+	}
+
+	var query importedProduct
+	if err := ValidateQuery(url.Values{"name": {"Lamp"}}, &query, schema); err == nil {
+		t.Error("Expected query binding with an imported schema to report an error")
+	}
+}
+
+func TestImportJSONSchema_WithBodyValidation(t *testing.T) {
+	schema, err := ImportJSONSchema([]byte(productJSONSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator := &Validator[importedProduct]{Schema: schema, Factory: func() *importedProduct { return new(importedProduct) }}
+
+	router := NewRouter()
+	router.POST("/products", func(ctx *Context) (any, int, error) {
+		product, _ := ValidatedBody[importedProduct](ctx)
+		return product, http.StatusCreated, nil
+	}, WithMiddleware(WithBodyValidation(validator)))
+
+	tests := []struct {
+		name     string
+		body     string
+		expected int
+	}{
+		{"conforming", `{"name": "Lamp", "sku": "LMP-0001", "price": 49.5, "owner": "ops"}`, http.StatusCreated},
+		{"violations", `{"name": "Lamp", "sku": "lamp", "price": 49.5, "owner": "ops"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(tt.body)))
+
+			if w.Code != tt.expected {
+				t.Errorf("Expected status %d, got %d: %s", tt.expected, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestImportJSONSchema_TypeScript(t *testing.T) {
+	schema, err := ImportJSONSchema([]byte(productJSONSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `export interface Product {
+  contact?: string;
+  featured?: boolean;
+  name: string;
+  owner: unknown;
+  price: number;
+  quantity?: number;
+  sku: string;
+  status?: "draft" | "active" | "archived";
+  tags?: unknown[];
+}
+`
+	if got := schema.TypeScript("Product"); got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+func TestImportJSONSchema_OpenAPI(t *testing.T) {
+	schema, err := ImportJSONSchema([]byte(productJSONSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := NewRouter()
+	router.POST("/products", func(ctx *Context) (any, int, error) {
+		return nil, http.StatusCreated, nil
+	})
+	router.Route("POST", "/products").WithDoc(RouteMetadata{RequestSchema: schema})
+
+	spec := router.GenerateOpenAPI(OpenAPIConfig{Title: "Test API", Version: "1.0.0"})
+	component := spec.Components.Schemas["Request"]
+	if component == nil {
+		t.Fatalf("Expected the imported schema under Request, got %v", spec.Components.Schemas)
+	}
+
+	if len(component.Properties) != 9 {
+		t.Errorf("Expected 9 properties, got %d", len(component.Properties))
+	}
+	if price := component.Properties["price"]; price == nil || price.Type != "number" || price.Maximum == nil || *price.Maximum != 10000 {
+		t.Errorf("Expected price as a number with maximum 10000, got %+v", price)
+	}
+	if tags := component.Properties["tags"]; tags == nil || tags.Type != "array" || tags.Items == nil {
+		t.Errorf("Expected tags as an array with items, got %+v", tags)
+	}
+	if status := component.Properties["status"]; status == nil || len(status.Enum) != 3 {
+		t.Errorf("Expected status with three enum values, got %+v", status)
+	}
+	slices.Sort(component.Required)
+	if !slices.Equal(component.Required, []string{"name", "owner", "price", "sku"}) {
+		t.Errorf("Expected required [name owner price sku], got %v", component.Required)
+	}
+}
@@ -0,0 +1,114 @@
+package nimbus
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// messageBundles holds localized validation messages by lowercase locale, then by rule tag
+var (
+	messageBundlesMu sync.RWMutex
+	messageBundles   = map[string]map[string]string{}
+)
+
+// RegisterMessages registers validation messages for a locale (e.g. "es" or "pt-BR"), keyed by
+// ValidationError.Tag. SendValidationError (and so WithTyped, WithBodyValidation and
+// WithQueryValidation) renders messages in the best locale from the request's Accept-Language;
+// tags without a message, and requests without a registered locale, keep the default English.
+//
+// Messages can use the placeholders {field}, {value} and {param} (the rule's argument,
+// e.g. 3 for minlen=3):
+//
+//	nimbus.RegisterMessages("es", map[string]string{
+//	    "required": "{field} es obligatorio",
+//	    "minlen":   "{field} debe tener al menos {param} caracteres",
+//	})
+//
+// Registering a locale again replaces its bundle. Register bundles at startup.
+func RegisterMessages(locale string, bundle map[string]string) {
+	messageBundlesMu.Lock()
+	defer messageBundlesMu.Unlock()
+	messageBundles[strings.ToLower(locale)] = bundle
+}
+
+// localize returns errs with messages in the best locale for acceptLanguage,
+// or errs itself if no registered locale matches.
+func localize(errs ValidationErrors, acceptLanguage string) ValidationErrors {
+	if acceptLanguage == "" {
+		return errs
+	}
+
+	messageBundlesMu.RLock()
+	defer messageBundlesMu.RUnlock()
+	if len(messageBundles) == 0 {
+		return errs
+	}
+
+	bundle := matchLocale(acceptLanguage)
+	if bundle == nil {
+		return errs
+	}
+
+	localized := slices.Clone(errs)
+	for i, err := range localized {
+		if message, ok := bundle[err.Tag]; ok {
+			localized[i].Message = strings.NewReplacer(
+				"{field}", err.Field,
+				"{value}", fmt.Sprint(err.Value),
+				"{param}", err.param,
+			).Replace(message)
+		}
+	}
+	return localized
+}
+
+// matchLocale returns the registered bundle for the most preferred language in an
+// Accept-Language header, trying each language's base ("es" for "es-MX") before the next.
+// The caller must hold messageBundlesMu.
+func matchLocale(acceptLanguage string) map[string]string {
+	type language struct {
+		tag     string
+		quality float64
+	}
+
+	var languages []language
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		if tag != "" && tag != "*" && quality > 0 {
+			languages = append(languages, language{strings.ToLower(tag), quality})
+		}
+	}
+
+	// Highest quality first, keeping header order for ties
+	slices.SortStableFunc(languages, func(a, b language) int {
+		switch {
+		case a.quality > b.quality:
+			return -1
+		case a.quality < b.quality:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	for _, lang := range languages {
+		if bundle, ok := messageBundles[lang.tag]; ok {
+			return bundle
+		}
+		if base, _, ok := strings.Cut(lang.tag, "-"); ok {
+			if bundle, ok := messageBundles[base]; ok {
+				return bundle
+			}
+		}
+	}
+	return nil
+}
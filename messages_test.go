@@ -0,0 +1,146 @@
+package nimbus
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// registerTestMessages registers a bundle for the duration of a test
+func registerTestMessages(t *testing.T, locale string, bundle map[string]string) {
+	t.Helper()
+	RegisterMessages(locale, bundle)
+	t.Cleanup(func() {
+		messageBundlesMu.Lock()
+		defer messageBundlesMu.Unlock()
+		delete(messageBundles, strings.ToLower(locale))
+	})
+}
+
+func TestRegisterMessages_AcceptLanguage(t *testing.T) {
+	registerTestMessages(t, "es", map[string]string{
+		"required": "{field} es obligatorio",
+		"minlen":   "{field} debe tener al menos {param} caracteres",
+	})
+	registerTestMessages(t, "fr", map[string]string{
+		"required": "{field} est obligatoire",
+	})
+
+	router := NewRouter()
+	router.POST("/users", func(ctx *Context) (any, int, error) {
+		return nil, http.StatusCreated, nil
+	}, WithMiddleware(WithBodyValidation(NewValidator(&TestBody{}))))
+
+	handler := func(ctx *Context, req *TypedRequest[TestParams, TestBody, TestQuery]) (any, int, error) {
+		return nil, http.StatusCreated, nil
+	}
+	router.POST("/typed/users", WithTyped(handler, nil, testBodyValidator, nil))
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expected       map[string]string // field -> message
+	}{
+		{"spanish", "es", map[string]string{"name": "name debe tener al menos 3 caracteres", "email": "email es obligatorio"}},
+		{"regional variant falls back to base", "es-MX,en;q=0.5", map[string]string{"name": "name debe tener al menos 3 caracteres"}},
+		{"quality order", "es;q=0.4, fr;q=0.9", map[string]string{"email": "email est obligatoire"}},
+		{"tag missing from bundle keeps english", "fr", map[string]string{"name": "name must be at least 3 characters"}},
+		{"unknown locale falls back to english", "de", map[string]string{"name": "name must be at least 3 characters", "email": "email is required"}},
+		{"no header", "", map[string]string{"email": "email is required"}},
+	}
+
+	for _, path := range []string{"/users", "/typed/users"} {
+		for _, tt := range tests {
+			t.Run(path+" "+tt.name, func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"name":"Al"}`))
+				if tt.acceptLanguage != "" {
+					req.Header.Set("Accept-Language", tt.acceptLanguage)
+				}
+				w := httptest.NewRecorder()
+
+				router.ServeHTTP(w, req)
+
+				if w.Code != http.StatusBadRequest {
+					t.Fatalf("Expected status 400, got %d: %s", w.Code, w.Body.String())
+				}
+				var body struct {
+					Details ValidationErrors `json:"details"`
+				}
+				if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+					t.Fatalf("Failed to decode body: %v", err)
+				}
+				for field, message := range tt.expected {
+					errs := body.Details.ForField(field)
+					if len(errs) != 1 || errs[0].Message != message {
+						t.Errorf("Expected %s message %q, got %v", field, message, errs)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestLocalize_DoesNotModifyErrors(t *testing.T) {
+	registerTestMessages(t, "es", map[string]string{"required": "{field} es obligatorio"})
+
+	errs := ValidationErrors{{Field: "email", Tag: "required", Message: "email is required"}}
+	localized := localize(errs, "es")
+
+	if localized[0].Message != "email es obligatorio" {
+		t.Errorf("Expected localized message, got %q", localized[0].Message)
+	}
+	if errs[0].Message != "email is required" {
+		t.Errorf("Expected original errors to be unchanged, got %q", errs[0].Message)
+	}
+}
+
+type TestRuleParams struct {
+	Code  string `json:"code" validate:"minlen=3"`
+	Nick  string `json:"nick" validate:"maxlen=5"`
+	Plan  string `json:"plan" validate:"enum=free|pro"`
+	Sort  string `json:"sort" validate:"sortfields=name|created_at"`
+	Slug  string `json:"slug" validate:"pattern=^[a-z]+$"`
+	Seats int    `json:"seats" validate:"min=1"`
+	Limit int    `json:"limit" validate:"max=100"`
+}
+
+func TestLocalize_RuleParams(t *testing.T) {
+	registerTestMessages(t, "es", map[string]string{
+		"minlen":     "{param}",
+		"maxlen":     "{param}",
+		"enum":       "{param}",
+		"sortfields": "{param}",
+		"pattern":    "{param}",
+		"min":        "{param}",
+		"max":        "{param}",
+	})
+
+	errs := NewSchema(TestRuleParams{}).Validate(&TestRuleParams{
+		Code: "ab", Nick: "toolong", Plan: "gold", Sort: "price", Slug: "Bad Slug", Seats: -1, Limit: 500,
+	})
+	localized := localize(errs, "es")
+
+	tests := []struct {
+		field string
+		param string
+	}{
+		{"code", "3"},
+		{"nick", "5"},
+		{"plan", "free, pro"},
+		{"sort", "name, created_at"},
+		{"slug", "^[a-z]+$"},
+		{"seats", "1"},
+		{"limit", "100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			fieldErrs := localized.ForField(tt.field)
+			if len(fieldErrs) != 1 || fieldErrs[0].Message != tt.param {
+				t.Errorf("Expected %s message %q, got %v", tt.field, tt.param, fieldErrs)
+			}
+		})
+	}
+}
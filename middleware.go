@@ -1,6 +1,26 @@
 package nimbus
 
 // Middleware is a function that wraps a handler
+//
+// A middleware can short-circuit the chain by returning without calling next.
+// Its (data, statusCode, error) result is rendered exactly like a handler's:
+// non-nil data with a 2xx status and nil error is wrapped in a SuccessResponse.
+// This lets cache or idempotency middleware serve responses without running the handler:
+//
+//	func Cache(store map[string]any) nimbus.Middleware {
+//	    return func(next nimbus.Handler) nimbus.Handler {
+//	        return func(ctx *nimbus.Context) (any, int, error) {
+//	            if cached, ok := store[ctx.Request.URL.Path]; ok {
+//	                return cached, http.StatusOK, nil // handler is never called
+//	            }
+//	            return next(ctx)
+//	        }
+//	    }
+//	}
+//
+// Calling ctx.Abort (or returning ctx.AbortWithError) makes the short-circuit explicit:
+// outer middleware can detect it with ctx.IsAborted, and the route handler is skipped
+// even if a later middleware still calls next.
 type Middleware func(Handler) Handler
 
 // Chain chains multiple middleware functions together
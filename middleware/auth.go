@@ -9,6 +9,7 @@ import (
 
 // Auth middleware validates authentication token
 // This is a simple example - in production, use proper JWT validation
+// The validated user is stored under nimbus.ContextKeyUser
 func Auth(validateToken func(string) (any, error)) nimbus.Middleware {
 	return func(next nimbus.Handler) nimbus.Handler {
 		return func(ctx *nimbus.Context) (any, int, error) {
@@ -33,7 +34,7 @@ func Auth(validateToken func(string) (any, error)) nimbus.Middleware {
 			}
 
 			// Store user in context
-			ctx.Set("user", user)
+			ctx.Set(nimbus.ContextKeyUser, user)
 
 			// Call next handler
 			return next(ctx)
@@ -228,3 +228,32 @@ func TestAuth_DifferentTokens(t *testing.T) {
 		})
 	}
 }
+
+func TestAuth_StoresUserUnderTypedKey(t *testing.T) {
+	validateToken := func(token string) (any, error) {
+		return "alice", nil
+	}
+
+	handler := Auth(validateToken)(func(ctx *nimbus.Context) (any, int, error) {
+		// Application code using the plain string "user" gets its own entry
+		ctx.Set("user", "shadow")
+
+		if user := ctx.GetString(nimbus.ContextKeyUser); user != "alice" {
+			t.Errorf("expected user 'alice' under nimbus.ContextKeyUser, got %q", user)
+		}
+		if user := ctx.GetString("user"); user != "shadow" {
+			t.Errorf("expected string key 'user' to be independent, got %q", user)
+		}
+
+		return nil, http.StatusOK, nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	ctx := nimbus.NewContext(w, req)
+
+	if _, _, err := handler(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
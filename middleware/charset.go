@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+// CharsetConfig defines configuration for the Charset middleware
+type CharsetConfig struct {
+	// Transcode converts bodies in a charset listed in Decoders to UTF-8 before they reach
+	// the handler. When false, any declared non-UTF-8 charset is rejected with 415.
+	Transcode bool
+
+	// Decoders maps lowercase charset names to functions converting a body to UTF-8
+	// Default: ISO-8859-1 (and its "latin1" alias); add entries backed by
+	// golang.org/x/text/encoding for other charsets
+	Decoders map[string]func([]byte) ([]byte, error)
+}
+
+// DefaultCharsetConfig returns a default Charset configuration (reject non-UTF-8 bodies)
+func DefaultCharsetConfig() CharsetConfig {
+	return CharsetConfig{
+		Transcode: false,
+		Decoders: map[string]func([]byte) ([]byte, error){
+			"iso-8859-1": decodeLatin1,
+			"latin1":     decodeLatin1,
+		},
+	}
+}
+
+// Charset is a middleware that makes sure request bodies reach binding as UTF-8.
+// The charset parameter of Content-Type is parsed with mime.ParseMediaType:
+//   - missing charset, utf-8, or us-ascii: passed through unchanged
+//   - other charsets: transcoded to UTF-8 if Transcode is set and a decoder exists,
+//     otherwise rejected with 415
+//
+// Example:
+//
+//	router.Use(middleware.Charset())
+//
+//	// Accept ISO-8859-1 clients by transcoding their bodies
+//	config := middleware.DefaultCharsetConfig()
+//	config.Transcode = true
+//	router.Use(middleware.Charset(config))
+func Charset(configs ...CharsetConfig) nimbus.Middleware {
+	config := DefaultCharsetConfig()
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+
+	return func(next nimbus.Handler) nimbus.Handler {
+		return func(ctx *nimbus.Context) (any, int, error) {
+			contentType := ctx.GetHeader("Content-Type")
+			if contentType == "" || ctx.Request.Body == nil || ctx.Request.Body == http.NoBody {
+				return next(ctx)
+			}
+
+			mediaType, params, err := mime.ParseMediaType(contentType)
+			if err != nil {
+				return nil, http.StatusUnsupportedMediaType,
+					nimbus.NewAPIError("unsupported_media_type", "Invalid Content-Type header")
+			}
+
+			charset := strings.ToLower(params["charset"])
+			switch charset {
+			case "", "utf-8", "utf8", "us-ascii":
+				// Missing charset is assumed to be UTF-8
+				return next(ctx)
+			}
+
+			decode, ok := config.Decoders[charset]
+			if !config.Transcode || !ok {
+				return nil, http.StatusUnsupportedMediaType,
+					nimbus.NewAPIError("unsupported_media_type", fmt.Sprintf("Unsupported charset: %s (use utf-8)", charset))
+			}
+
+			body, err := io.ReadAll(ctx.Request.Body)
+			if err != nil {
+				return nil, 0, err
+			}
+			ctx.Request.Body.Close()
+
+			decoded, err := decode(body)
+			if err != nil {
+				return nil, http.StatusBadRequest,
+					nimbus.NewAPIError("invalid_body", fmt.Sprintf("Request body is not valid %s", charset))
+			}
+
+			// Hand the handler a UTF-8 body with a matching Content-Type
+			params["charset"] = "utf-8"
+			ctx.Request.Header.Set("Content-Type", mime.FormatMediaType(mediaType, params))
+			ctx.Request.Body = io.NopCloser(bytes.NewReader(decoded))
+			ctx.Request.ContentLength = int64(len(decoded))
+
+			return next(ctx)
+		}
+	}
+}
+
+// decodeLatin1 converts ISO-8859-1 bytes to UTF-8; every byte maps to the code point of the same value
+func decodeLatin1(data []byte) ([]byte, error) {
+	buf := make([]byte, 0, len(data)+len(data)/4)
+	for _, b := range data {
+		buf = utf8.AppendRune(buf, rune(b))
+	}
+	return buf, nil
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+type charsetTestRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func newCharsetRouter(config CharsetConfig) *nimbus.Router {
+	router := nimbus.NewRouter()
+	router.Use(Charset(config))
+
+	schema := nimbus.NewSchema(charsetTestRequest{})
+	router.AddRoute(http.MethodPost, "/users", func(ctx *nimbus.Context) (any, int, error) {
+		var req charsetTestRequest
+		if err := ctx.BindAndValidateJSON(&req, schema); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		return map[string]string{"name": req.Name, "content_type": ctx.GetHeader("Content-Type")}, http.StatusOK, nil
+	})
+
+	return router
+}
+
+func TestCharset(t *testing.T) {
+	// "José" in ISO-8859-1: é is the single byte 0xE9
+	latin1Body := []byte("{\"name\":\"Jos\xe9\"}")
+	utf8Body := []byte(`{"name":"José"}`)
+
+	transcode := DefaultCharsetConfig()
+	transcode.Transcode = true
+
+	tests := []struct {
+		name           string
+		config         CharsetConfig
+		contentType    string
+		body           []byte
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"utf-8 declared", DefaultCharsetConfig(), "application/json; charset=utf-8", utf8Body, http.StatusOK, "José"},
+		{"charset missing", DefaultCharsetConfig(), "application/json", utf8Body, http.StatusOK, "José"},
+		{"non-utf-8 rejected", DefaultCharsetConfig(), "application/json; charset=iso-8859-1", latin1Body, http.StatusUnsupportedMediaType, "Unsupported charset: iso-8859-1"},
+		{"non-utf-8 transcoded", transcode, "application/json; charset=ISO-8859-1", latin1Body, http.StatusOK, "José"},
+		{"unknown charset rejected when transcoding", transcode, "application/json; charset=shift_jis", utf8Body, http.StatusUnsupportedMediaType, "unsupported_media_type"},
+		{"malformed content type", DefaultCharsetConfig(), "application/json; charset", utf8Body, http.StatusUnsupportedMediaType, "Invalid Content-Type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newCharsetRouter(tt.config)
+
+			req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.expectedBody) {
+				t.Errorf("expected body to contain %q, got %s", tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCharset_TranscodeRewritesContentType(t *testing.T) {
+	config := DefaultCharsetConfig()
+	config.Transcode = true
+	router := newCharsetRouter(config)
+
+	req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader([]byte("{\"name\":\"Zo\xeb\"}")))
+	req.Header.Set("Content-Type", "application/json; charset=latin1")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if !strings.Contains(w.Body.String(), `"content_type":"application/json; charset=utf-8"`) {
+		t.Errorf("expected handler to see a utf-8 Content-Type, got %s", w.Body.String())
+	}
+}
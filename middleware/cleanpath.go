@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// CleanPath canonicalizes request paths before the router sees them, collapsing doubled
+// slashes and resolving "." and ".." segments with path.Clean semantics. A trailing slash is
+// kept, so /users/ and /users still route as registered. Encoded slashes (%2F) stay inside
+// their segment.
+//
+// With redirect set, requests for an unclean path get a 301 to the cleaned path (query kept);
+// otherwise the path is rewritten in place and the request is routed as if it had been sent
+// clean. Clean paths pass through untouched.
+//
+// Router middleware runs after a route has been matched, so CleanPath wraps the router itself
+// rather than being registered with Use:
+//
+//	http.ListenAndServe(":8080", middleware.CleanPath(true)(router))
+func CleanPath(redirect bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			cleaned, changed := cleanURLPath(req.URL)
+			if !changed {
+				next.ServeHTTP(w, req)
+				return
+			}
+
+			if redirect {
+				target := cleaned.EscapedPath()
+				if req.URL.RawQuery != "" {
+					target += "?" + req.URL.RawQuery
+				}
+				http.Redirect(w, req, target, http.StatusMovedPermanently)
+				return
+			}
+
+			// Shallow copy so the caller's request is left as it was sent
+			rewritten := new(http.Request)
+			*rewritten = *req
+			rewritten.URL = cleaned
+			next.ServeHTTP(w, rewritten)
+		})
+	}
+}
+
+// cleanURLPath returns a copy of u with its path cleaned, reporting whether anything changed.
+// The escaped form is cleaned alongside and dropped if it no longer matches the cleaned path
+// (e.g. a "%2e%2e" segment that Path resolved), so the two can't disagree.
+func cleanURLPath(u *url.URL) (*url.URL, bool) {
+	cleanedPath := cleanPath(u.Path)
+	cleanedRaw := u.RawPath
+	if cleanedRaw != "" {
+		cleanedRaw = cleanPath(cleanedRaw)
+		if unescaped, err := url.PathUnescape(cleanedRaw); err != nil || unescaped != cleanedPath {
+			cleanedRaw = ""
+		}
+	}
+
+	if cleanedPath == u.Path && cleanedRaw == u.RawPath {
+		return u, false
+	}
+
+	cleaned := *u
+	cleaned.Path = cleanedPath
+	cleaned.RawPath = cleanedRaw
+	return &cleaned, true
+}
+
+// cleanPath applies path.Clean to a request path, keeping a trailing slash. Paths that
+// aren't rooted (such as the "*" of OPTIONS *) are returned unchanged.
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	if p[0] != '/' {
+		return p
+	}
+
+	cleaned := path.Clean(p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
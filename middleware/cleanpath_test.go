@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+func TestCleanPath(t *testing.T) {
+	router := nimbus.NewRouter()
+	router.GET("/users/:id", func(ctx *nimbus.Context) (any, int, error) {
+		return ctx.Param("id"), http.StatusOK, nil
+	})
+	router.GET("/files/", func(ctx *nimbus.Context) (any, int, error) {
+		return "listing", http.StatusOK, nil
+	})
+	router.GET("/admin", func(ctx *nimbus.Context) (any, int, error) {
+		return "admin", http.StatusOK, nil
+	})
+
+	tests := []struct {
+		name             string
+		target           string
+		expectedLocation string // Empty means the request isn't redirected
+		expectedBody     string
+	}{
+		{"clean path untouched", "/users/42", "", "42"},
+		{"doubled slashes", "//users//42", "/users/42", "42"},
+		{"dot segment", "/users/./42", "/users/42", "42"},
+		{"dot-dot segment", "/public/../users/42", "/users/42", "42"},
+		{"traversal past root", "/../../admin", "/admin", "admin"},
+		{"trailing slash kept", "/files//", "/files/", "listing"},
+		{"query kept", "/users//42?expand=orders", "/users/42?expand=orders", "42"},
+		{"encoded slash stays in segment", "/users//a%2Fb", "/users/a%2Fb", "a/b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"/rewrite", func(t *testing.T) {
+			w := httptest.NewRecorder()
+			CleanPath(false)(router).ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d (%s)", w.Code, w.Body.String())
+			}
+			if w.Body.String() != tt.expectedBody {
+				t.Errorf("expected body %q, got %q", tt.expectedBody, w.Body.String())
+			}
+		})
+
+		t.Run(tt.name+"/redirect", func(t *testing.T) {
+			w := httptest.NewRecorder()
+			CleanPath(true)(router).ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if tt.expectedLocation == "" {
+				if w.Code != http.StatusOK {
+					t.Errorf("expected status 200, got %d", w.Code)
+				}
+				return
+			}
+			if w.Code != http.StatusMovedPermanently {
+				t.Fatalf("expected status 301, got %d", w.Code)
+			}
+			if location := w.Header().Get("Location"); location != tt.expectedLocation {
+				t.Errorf("expected Location %q, got %q", tt.expectedLocation, location)
+			}
+		})
+	}
+}
+
+func TestCleanPath_RewriteLeavesOriginalRequest(t *testing.T) {
+	var seen string
+	handler := CleanPath(false)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		seen = req.URL.Path
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/a//b/../c", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if seen != "/a/c" {
+		t.Errorf("expected handler to see /a/c, got %q", seen)
+	}
+	if req.URL.Path != "/a//b/../c" {
+		t.Errorf("expected the original request to be unchanged, got %q", req.URL.Path)
+	}
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+// ConcurrencyLimitOption configures a ConcurrencyLimit middleware
+type ConcurrencyLimitOption func(*concurrencyLimitConfig)
+
+type concurrencyLimitConfig struct {
+	queueTimeout time.Duration // How long excess requests wait for a slot (0 = reject immediately)
+}
+
+// WithQueueTimeout makes requests beyond the limit wait up to timeout for a free slot
+// instead of being rejected immediately, smoothing out short bursts. A request that
+// doesn't get a slot in time, or whose client goes away while waiting, receives the 503.
+// Non-positive values keep the default of rejecting immediately.
+func WithQueueTimeout(timeout time.Duration) ConcurrencyLimitOption {
+	return func(c *concurrencyLimitConfig) {
+		if timeout > 0 {
+			c.queueTimeout = timeout
+		}
+	}
+}
+
+// ConcurrencyLimit is a middleware that protects a fragile backend by allowing at most max
+// handlers to run at once. Requests beyond the limit are rejected immediately with
+// 503 server_busy, or queued first with WithQueueTimeout. A slot is released when the
+// handler returns, even if it panics.
+//
+// Example:
+//
+//	router.Use(middleware.ConcurrencyLimit(100, middleware.WithQueueTimeout(500*time.Millisecond)))
+func ConcurrencyLimit(max int, opts ...ConcurrencyLimitOption) nimbus.Middleware {
+	if max <= 0 {
+		panic("ConcurrencyLimit: max must be greater than 0")
+	}
+
+	var config concurrencyLimitConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	// Each buffered element is an in-flight request
+	slots := make(chan struct{}, max)
+
+	return func(next nimbus.Handler) nimbus.Handler {
+		return func(ctx *nimbus.Context) (any, int, error) {
+			if !acquireSlot(ctx, slots, config.queueTimeout) {
+				return nil, http.StatusServiceUnavailable, nimbus.NewAPIError("server_busy", "Server is busy, please retry later")
+			}
+			defer func() { <-slots }()
+
+			return next(ctx)
+		}
+	}
+}
+
+// acquireSlot takes a slot, waiting up to timeout for one to free up.
+// It reports false if none was available in time or the request was canceled.
+func acquireSlot(ctx *nimbus.Context, slots chan struct{}, timeout time.Duration) bool {
+	// Fast path: a free slot, no timer needed
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+		if timeout <= 0 {
+			return false
+		}
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Request.Context().Done():
+		return false
+	}
+}
@@ -0,0 +1,182 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+func TestConcurrencyLimit(t *testing.T) {
+	const limit = 3
+
+	started := make(chan struct{}, limit)
+	release := make(chan struct{})
+
+	router := nimbus.NewRouter()
+	router.Use(ConcurrencyLimit(limit))
+	router.GET("/report", func(ctx *nimbus.Context) (any, int, error) {
+		started <- struct{}{}
+		<-release
+		return map[string]string{"status": "done"}, http.StatusOK, nil
+	})
+
+	// Fill every slot with a blocked request
+	var wg sync.WaitGroup
+	codes := make(chan int, limit)
+	for range limit {
+		wg.Go(func() {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/report", nil))
+			codes <- w.Code
+		})
+	}
+	for range limit {
+		<-started
+	}
+
+	// Excess requests are rejected while the slots are taken
+	for range 2 {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/report", nil))
+
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("expected status 503, got %d", w.Code)
+		}
+		if !bytes.Contains(w.Body.Bytes(), []byte(`"server_busy"`)) {
+			t.Errorf("expected server_busy error, got %s", w.Body.String())
+		}
+	}
+
+	close(release)
+	wg.Wait()
+	close(codes)
+	for code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("expected admitted requests to succeed, got %d", code)
+		}
+	}
+
+	// Slots are free again once the handlers return
+	started = make(chan struct{}, 1)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/report", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 after slots were released, got %d", w.Code)
+	}
+}
+
+func TestConcurrencyLimit_ReleasesOnPanic(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(nil)
+
+	router := nimbus.NewRouter()
+	router.Use(Recovery(), ConcurrencyLimit(1))
+	router.GET("/panic", func(ctx *nimbus.Context) (any, int, error) {
+		panic("boom")
+	})
+
+	for i := range 3 {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("request %d: expected status 500 from recovered panic, got %d", i, w.Code)
+		}
+	}
+}
+
+func TestConcurrencyLimit_InvalidMaxPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for non-positive max")
+		}
+	}()
+
+	ConcurrencyLimit(0)
+}
+
+func TestConcurrencyLimit_QueueTimeout(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+
+	router := nimbus.NewRouter()
+	router.Use(ConcurrencyLimit(1, WithQueueTimeout(200*time.Millisecond)))
+	router.GET("/report", func(ctx *nimbus.Context) (any, int, error) {
+		if ctx.Query("block") != "" {
+			started <- struct{}{}
+			<-release
+		}
+		return map[string]string{"status": "done"}, http.StatusOK, nil
+	})
+
+	serve := func(target string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w.Code
+	}
+
+	t.Run("waits for a slot freed in time", func(t *testing.T) {
+		blocked := make(chan int, 1)
+		go func() { blocked <- serve("/report?block=1") }()
+		<-started
+
+		// Free the slot while the next request is queued
+		time.AfterFunc(20*time.Millisecond, func() { release <- struct{}{} })
+
+		if code := serve("/report"); code != http.StatusOK {
+			t.Errorf("expected queued request to succeed, got %d", code)
+		}
+		if code := <-blocked; code != http.StatusOK {
+			t.Errorf("expected blocking request to succeed, got %d", code)
+		}
+	})
+
+	t.Run("times out when no slot frees", func(t *testing.T) {
+		blocked := make(chan int, 1)
+		go func() { blocked <- serve("/report?block=1") }()
+		<-started
+		defer func() {
+			release <- struct{}{}
+			<-blocked
+		}()
+
+		start := time.Now()
+		if code := serve("/report"); code != http.StatusServiceUnavailable {
+			t.Errorf("expected status 503, got %d", code)
+		}
+		if waited := time.Since(start); waited < 200*time.Millisecond {
+			t.Errorf("expected request to wait for the queue timeout, waited %v", waited)
+		}
+	})
+
+	t.Run("stops waiting when the request is canceled", func(t *testing.T) {
+		blocked := make(chan int, 1)
+		go func() { blocked <- serve("/report?block=1") }()
+		<-started
+		defer func() {
+			release <- struct{}{}
+			<-blocked
+		}()
+
+		reqCtx, cancel := context.WithCancel(context.Background())
+		cancel()
+		w := httptest.NewRecorder()
+		start := time.Now()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/report", nil).WithContext(reqCtx))
+
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected status 503, got %d", w.Code)
+		}
+		if waited := time.Since(start); waited >= 200*time.Millisecond {
+			t.Errorf("expected canceled request to stop waiting early, waited %v", waited)
+		}
+	})
+}
@@ -16,6 +16,12 @@ type CORSConfig struct {
 	ExposeHeaders    []string
 	AllowCredentials bool
 	MaxAge           int
+
+	// AllowCredentialsFunc decides per allowed origin whether to send
+	// Access-Control-Allow-Credentials, e.g. only for first-party origins.
+	// When set, it takes precedence over AllowCredentials. With AllowOrigins "*", it is the
+	// only way to allow credentials: an origin it approves is reflected in place of "*".
+	AllowCredentialsFunc func(origin string) bool
 }
 
 // DefaultCORSConfig returns a default CORS configuration
@@ -45,12 +51,20 @@ func DefaultCORSConfig() CORSConfig {
 
 // CORS returns a CORS middleware with optional custom configuration
 // If no config is provided, uses default configuration
+//
+// AllowCredentials with AllowOrigins "*" panics, since it would let any site make
+// credentialed requests; list the origins, or approve them with AllowCredentialsFunc.
 func CORS(configs ...CORSConfig) nimbus.Middleware {
 	config := DefaultCORSConfig()
 	if len(configs) > 0 {
 		config = configs[0]
 	}
 
+	wildcard := len(config.AllowOrigins) > 0 && config.AllowOrigins[0] == "*"
+	if wildcard && config.AllowCredentials && config.AllowCredentialsFunc == nil {
+		panic(`CORS: AllowCredentials can't be used with AllowOrigins "*"; list the origins or use AllowCredentialsFunc`)
+	}
+
 	return func(next nimbus.Handler) nimbus.Handler {
 		return func(ctx *nimbus.Context) (any, int, error) {
 			origin := ctx.GetHeader("Origin")
@@ -58,7 +72,7 @@ func CORS(configs ...CORSConfig) nimbus.Middleware {
 			// Check if origin is allowed
 			allowedOrigin := ""
 			if len(config.AllowOrigins) > 0 {
-				if config.AllowOrigins[0] == "*" {
+				if wildcard {
 					allowedOrigin = "*"
 				} else {
 					for _, o := range config.AllowOrigins {
@@ -70,12 +84,27 @@ func CORS(configs ...CORSConfig) nimbus.Middleware {
 				}
 			}
 
+			credentials := config.AllowCredentials
+			if config.AllowCredentialsFunc != nil {
+				credentials = allowedOrigin != "" && config.AllowCredentialsFunc(origin)
+			}
+
+			// Browsers reject "*" on credentialed requests, so reflect an origin that
+			// AllowCredentialsFunc approved instead (credentials is only set for "*" by it)
+			if credentials && allowedOrigin == "*" && origin != "" {
+				allowedOrigin = origin
+			}
+
 			// Set CORS headers
 			if allowedOrigin != "" {
 				ctx.Header("Access-Control-Allow-Origin", allowedOrigin)
+				if allowedOrigin != "*" || config.AllowCredentialsFunc != nil {
+					// The response varies by origin, so shared caches must key on it
+					ctx.Writer.Header().Add("Vary", "Origin")
+				}
 			}
 
-			if config.AllowCredentials {
+			if credentials {
 				ctx.Header("Access-Control-Allow-Credentials", "true")
 			}
 
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"bytes"
+	"log"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -410,3 +412,161 @@ func TestCORS_MaxAge_NotOnActualRequest(t *testing.T) {
 		t.Errorf("expected no Access-Control-Max-Age on non-preflight request, got '%s'", maxAgeHeader)
 	}
 }
+
+func TestCORS_AllowCredentialsFunc(t *testing.T) {
+	config := CORSConfig{
+		AllowOrigins:     []string{"https://app.example.com", "https://partner.example.org"},
+		AllowCredentials: true, // overridden by AllowCredentialsFunc
+		AllowCredentialsFunc: func(origin string) bool {
+			return strings.HasSuffix(origin, ".example.com")
+		},
+	}
+
+	testCases := []struct {
+		origin              string
+		expectedOrigin      string
+		expectedCredentials string
+	}{
+		{"https://app.example.com", "https://app.example.com", "true"},
+		{"https://partner.example.org", "https://partner.example.org", ""},
+		{"https://evil.example.com", "", ""}, // not an allowed origin
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.origin, func(t *testing.T) {
+			handler := CORS(config)(func(ctx *nimbus.Context) (any, int, error) {
+				return nil, http.StatusOK, nil
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Origin", tc.origin)
+			w := httptest.NewRecorder()
+
+			handler(nimbus.NewContext(w, req))
+
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tc.expectedOrigin {
+				t.Errorf("expected Access-Control-Allow-Origin %q, got %q", tc.expectedOrigin, got)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Credentials"); got != tc.expectedCredentials {
+				t.Errorf("expected Access-Control-Allow-Credentials %q, got %q", tc.expectedCredentials, got)
+			}
+		})
+	}
+}
+
+func TestCORS_CredentialsNeverWithWildcard(t *testing.T) {
+	config := CORSConfig{
+		AllowOrigins: []string{"*"},
+		AllowCredentialsFunc: func(origin string) bool {
+			return origin == "https://app.example.com"
+		},
+	}
+
+	testCases := []struct {
+		origin              string
+		expectedOrigin      string
+		expectedCredentials string
+	}{
+		{"https://app.example.com", "https://app.example.com", "true"},
+		{"https://other.example.org", "*", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.origin, func(t *testing.T) {
+			handler := CORS(config)(func(ctx *nimbus.Context) (any, int, error) {
+				return nil, http.StatusOK, nil
+			})
+
+			req := httptest.NewRequest(http.MethodOptions, "/test", nil)
+			req.Header.Set("Origin", tc.origin)
+			w := httptest.NewRecorder()
+
+			handler(nimbus.NewContext(w, req))
+
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tc.expectedOrigin {
+				t.Errorf("expected Access-Control-Allow-Origin %q, got %q", tc.expectedOrigin, got)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Credentials"); got != tc.expectedCredentials {
+				t.Errorf("expected Access-Control-Allow-Credentials %q, got %q", tc.expectedCredentials, got)
+			}
+			if tc.expectedOrigin != "*" && w.Header().Get("Vary") != "Origin" {
+				t.Errorf("expected Vary: Origin for a reflected origin, got %q", w.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestCORS_WildcardWithAllowCredentialsPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error(`expected CORS to panic on AllowCredentials with AllowOrigins "*"`)
+		}
+	}()
+
+	CORS(CORSConfig{AllowOrigins: []string{"*"}, AllowCredentials: true})
+}
+
+func TestCORS_WildcardNeverReflectsUnapprovedOrigin(t *testing.T) {
+	handler := CORS(CORSConfig{
+		AllowOrigins:     []string{"*"},
+		AllowCredentials: true, // overridden by AllowCredentialsFunc
+		AllowCredentialsFunc: func(origin string) bool {
+			return origin == "https://app.example.com"
+		},
+	})(func(ctx *nimbus.Context) (any, int, error) {
+		return nil, http.StatusOK, nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Origin", "https://attacker.example.net")
+	w := httptest.NewRecorder()
+
+	handler(nimbus.NewContext(w, req))
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("expected Access-Control-Allow-Origin *, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("expected no Access-Control-Allow-Credentials, got %q", got)
+	}
+}
+
+func TestCORS_HeadersKeptOnErrorResponses(t *testing.T) {
+	// Capture the panic log
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(nil)
+
+	router := nimbus.NewRouter()
+	router.Use(Recovery(), CORS(CORSConfig{
+		AllowOrigins:  []string{"https://app.example.com"},
+		AllowMethods:  []string{http.MethodGet},
+		ExposeHeaders: []string{"X-Request-Id"},
+	}))
+	router.GET("/fail", func(ctx *nimbus.Context) (any, int, error) {
+		return nil, http.StatusInternalServerError, nimbus.NewAPIError("internal_error", "database unavailable")
+	})
+	router.GET("/panic", func(ctx *nimbus.Context) (any, int, error) {
+		panic("boom")
+	})
+
+	for _, path := range []string{"/fail", "/panic", "/missing"} {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set("Origin", "https://app.example.com")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code < http.StatusBadRequest {
+				t.Fatalf("expected an error status, got %d", w.Code)
+			}
+			if origin := w.Header().Get("Access-Control-Allow-Origin"); origin != "https://app.example.com" {
+				t.Errorf("expected Access-Control-Allow-Origin on the error response, got %q", origin)
+			}
+			if exposed := w.Header().Get("Access-Control-Expose-Headers"); exposed != "X-Request-Id" {
+				t.Errorf("expected Access-Control-Expose-Headers on the error response, got %q", exposed)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+// DeadlineConfig defines configuration for the DeadlineFromHeader middleware
+type DeadlineConfig struct {
+	// Max caps the timeout a client can ask for; longer values are cut to it (default 30 seconds)
+	Max time.Duration
+}
+
+// DefaultDeadlineConfig returns a default DeadlineFromHeader configuration
+func DefaultDeadlineConfig() DeadlineConfig {
+	return DeadlineConfig{
+		Max: 30 * time.Second,
+	}
+}
+
+// DeadlineFromHeader is a middleware that lets clients set the request's deadline, for callers
+// that would rather get a fast failure than wait past their own timeout. The header holds a
+// duration (X-Request-Timeout: 5s, 1500ms) or a number of seconds (X-Request-Timeout: 5),
+// capped at the config's Max. The handler runs with the deadline on ctx.Request.Context(),
+// and if it hasn't finished by then the response is a 503 deadline_exceeded.
+//
+// Requests without the header run without a deadline, and a value that isn't a positive
+// duration is rejected with 400 invalid_timeout.
+//
+// Example:
+//
+//	router.Use(middleware.DeadlineFromHeader("X-Request-Timeout", middleware.DeadlineConfig{
+//	    Max: 10 * time.Second,
+//	}))
+func DeadlineFromHeader(header string, configs ...DeadlineConfig) nimbus.Middleware {
+	if header == "" {
+		panic("DeadlineFromHeader: header is required")
+	}
+
+	config := DefaultDeadlineConfig()
+	if len(configs) > 0 && configs[0].Max > 0 {
+		config.Max = configs[0].Max
+	}
+
+	return func(next nimbus.Handler) nimbus.Handler {
+		return func(ctx *nimbus.Context) (any, int, error) {
+			value := ctx.GetHeader(header)
+			if value == "" {
+				return next(ctx)
+			}
+
+			timeout, ok := parseRequestTimeout(value)
+			if !ok {
+				return nil, http.StatusBadRequest, nimbus.NewAPIError("invalid_timeout",
+					header+" must be a positive duration such as 5s or 1500ms")
+			}
+			if timeout > config.Max {
+				timeout = config.Max
+			}
+
+			data, status, err, timedOut := runWithTimeout(ctx, next, timeout)
+			if timedOut {
+				return nil, http.StatusServiceUnavailable, nimbus.NewAPIError("deadline_exceeded",
+					"request deadline exceeded")
+			}
+			return data, status, err
+		}
+	}
+}
+
+// parseRequestTimeout parses a timeout header value: a Go duration or a number of seconds
+func parseRequestTimeout(value string) (time.Duration, bool) {
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, parseErr := strconv.ParseFloat(value, 64)
+		if parseErr != nil {
+			return 0, false
+		}
+		timeout = time.Duration(seconds * float64(time.Second))
+	}
+	return timeout, timeout > 0
+}
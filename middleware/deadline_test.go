@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+func TestDeadlineFromHeader(t *testing.T) {
+	tests := []struct {
+		name           string
+		header         string
+		work           time.Duration
+		expectedStatus int
+		expectedError  string
+		// expectedDeadline is the deadline the handler should see from the request start, 0 for none
+		expectedDeadline time.Duration
+	}{
+		{"short deadline trips", "20ms", time.Second, http.StatusServiceUnavailable, "deadline_exceeded", 20 * time.Millisecond},
+		{"deadline met", "1s", 0, http.StatusOK, "", time.Second},
+		{"seconds without a unit", "2", 0, http.StatusOK, "", 2 * time.Second},
+		{"absent header", "", 0, http.StatusOK, "", 0},
+		{"capped at max", "1h", 0, http.StatusOK, "", 5 * time.Second},
+		{"invalid value", "soon", 0, http.StatusBadRequest, "invalid_timeout", 0},
+		{"non-positive value", "-5s", 0, http.StatusBadRequest, "invalid_timeout", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The handler outlives a tripped deadline, so it reports what it saw over a channel
+			type seenDeadline struct {
+				deadline time.Time
+				ok       bool
+			}
+			seen := make(chan seenDeadline, 1)
+
+			router := nimbus.NewRouter()
+			router.Use(DeadlineFromHeader("X-Request-Timeout", DeadlineConfig{Max: 5 * time.Second}))
+			router.GET("/reports", func(ctx *nimbus.Context) (any, int, error) {
+				reqCtx := ctx.Request.Context()
+				deadline, ok := reqCtx.Deadline()
+				seen <- seenDeadline{deadline, ok}
+
+				select {
+				case <-time.After(tt.work):
+					return map[string]string{"status": "done"}, http.StatusOK, nil
+				case <-reqCtx.Done():
+					return nil, http.StatusInternalServerError, reqCtx.Err()
+				}
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/reports", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Request-Timeout", tt.header)
+			}
+			w := httptest.NewRecorder()
+			start := time.Now()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedError != "" {
+				var response nimbus.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+					t.Fatal(err)
+				}
+				if response.Error != tt.expectedError {
+					t.Errorf("expected error %q, got %q", tt.expectedError, response.Error)
+				}
+			}
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				return
+			}
+			saw := <-seen
+			deadline, hasDeadline := saw.deadline, saw.ok
+			if tt.expectedDeadline == 0 {
+				if hasDeadline {
+					t.Errorf("expected no deadline, got one in %v", time.Until(deadline))
+				}
+				return
+			}
+			if !hasDeadline {
+				t.Fatal("expected the handler to see a deadline")
+			}
+			if got := deadline.Sub(start); got < tt.expectedDeadline || got > tt.expectedDeadline+time.Second/10 {
+				t.Errorf("expected a deadline about %v after the request started, got %v", tt.expectedDeadline, got)
+			}
+		})
+	}
+}
+
+func TestDeadlineFromHeader_DefaultMax(t *testing.T) {
+	if max := DefaultDeadlineConfig().Max; max != 30*time.Second {
+		t.Errorf("expected default max 30s, got %v", max)
+	}
+}
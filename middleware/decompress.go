@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+// ErrDecompressedBodyTooLarge is the cause of the error returned when reading a decompressed
+// request body past DecompressConfig.MaxBytes (check it with errors.Is). The error is a
+// nimbus.StatusError, so WithBodyValidation and WithTyped answer it with 413, and Decompress
+// converts it into a 413 response when a handler returns it.
+var ErrDecompressedBodyTooLarge = errors.New("decompressed request body too large")
+
+// DecompressConfig defines configuration for the Decompress middleware
+type DecompressConfig struct {
+	// MaxBytes caps the decompressed body size to guard against decompression bombs
+	// A small compressed payload can expand enormously, so BodyLimit alone is not enough
+	MaxBytes int64
+}
+
+// DefaultDecompressConfig returns a default Decompress configuration
+func DefaultDecompressConfig() DecompressConfig {
+	return DecompressConfig{
+		MaxBytes: DefaultUploadLimit,
+	}
+}
+
+// Decompress is a middleware that transparently decompresses request bodies sent with
+// Content-Encoding: gzip or deflate, so handlers and BindAndValidateJSON read plain bytes.
+// Unsupported encodings are rejected with 415, and decompressed bodies larger than
+// MaxBytes are rejected with 413.
+//
+// Example:
+//
+//	router.Use(middleware.Decompress())
+//
+//	// Custom decompressed size cap
+//	router.Use(middleware.Decompress(middleware.DecompressConfig{MaxBytes: 2 * middleware.MB}))
+func Decompress(configs ...DecompressConfig) nimbus.Middleware {
+	config := DefaultDecompressConfig()
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = DefaultUploadLimit
+	}
+
+	return func(next nimbus.Handler) nimbus.Handler {
+		return func(ctx *nimbus.Context) (any, int, error) {
+			encoding := strings.ToLower(strings.TrimSpace(ctx.GetHeader("Content-Encoding")))
+			if encoding == "" || encoding == "identity" || ctx.Request.Body == nil || ctx.Request.Body == http.NoBody {
+				return next(ctx)
+			}
+
+			var reader io.ReadCloser
+			var err error
+			switch encoding {
+			case "gzip", "x-gzip":
+				reader, err = gzip.NewReader(ctx.Request.Body)
+			case "deflate":
+				// HTTP "deflate" is zlib-wrapped DEFLATE (RFC 9110)
+				reader, err = zlib.NewReader(ctx.Request.Body)
+			default:
+				return nil, http.StatusUnsupportedMediaType,
+					nimbus.NewAPIError("unsupported_media_type", fmt.Sprintf("Unsupported Content-Encoding: %s", encoding))
+			}
+			if err != nil {
+				return nil, http.StatusBadRequest,
+					nimbus.NewAPIError("invalid_body", fmt.Sprintf("Request body is not valid %s data", encoding))
+			}
+
+			ctx.Request.Body = &decompressedBody{
+				reader:    reader,
+				original:  ctx.Request.Body,
+				remaining: config.MaxBytes,
+				max:       config.MaxBytes,
+			}
+
+			// The body is now plain; the original length no longer applies
+			ctx.Request.Header.Del("Content-Encoding")
+			ctx.Request.Header.Del("Content-Length")
+			ctx.Request.ContentLength = -1
+
+			// Call next handler
+			data, status, err := next(ctx)
+
+			if errors.Is(err, ErrDecompressedBodyTooLarge) {
+				return nil, http.StatusRequestEntityTooLarge, decompressedTooLargeError(config.MaxBytes).APIError
+			}
+
+			return data, status, err
+		}
+	}
+}
+
+// decompressedBody reads from a decompressor, failing once more than remaining bytes are produced
+type decompressedBody struct {
+	reader    io.ReadCloser
+	original  io.ReadCloser
+	remaining int64
+	max       int64
+}
+
+func (b *decompressedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		// Limit reached: probe for one more byte to tell "exactly at limit" from "over limit"
+		var probe [1]byte
+		if n, _ := b.reader.Read(probe[:]); n > 0 {
+			return 0, decompressedTooLargeError(b.max)
+		}
+		return 0, io.EOF
+	}
+
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.reader.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
+
+func (b *decompressedBody) Close() error {
+	b.reader.Close()
+	return b.original.Close()
+}
+
+// decompressedTooLargeError is the 413 for a decompressed body over max bytes
+func decompressedTooLargeError(max int64) *nimbus.StatusError {
+	return &nimbus.StatusError{
+		Status: http.StatusRequestEntityTooLarge,
+		APIError: nimbus.NewAPIError("payload_too_large",
+			fmt.Sprintf("Decompressed request body too large. Maximum size is %s", formatBytes(max))),
+		Err: ErrDecompressedBodyTooLarge,
+	}
+}
@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+type decompressTestRequest struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email" validate:"required,email"`
+}
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func newDecompressRouter(config DecompressConfig) *nimbus.Router {
+	router := nimbus.NewRouter()
+	router.Use(Decompress(config))
+
+	schema := nimbus.NewSchema(decompressTestRequest{})
+	router.AddRoute(http.MethodPost, "/users", func(ctx *nimbus.Context) (any, int, error) {
+		var req decompressTestRequest
+		if err := ctx.BindAndValidateJSON(&req, schema); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		return map[string]string{"name": req.Name, "email": req.Email}, http.StatusOK, nil
+	})
+
+	return router
+}
+
+func TestDecompress(t *testing.T) {
+	router := newDecompressRouter(DefaultDecompressConfig())
+	payload := []byte(`{"name":"Jane","email":"jane@example.com"}`)
+
+	var deflated bytes.Buffer
+	zw := zlib.NewWriter(&deflated)
+	zw.Write(payload)
+	zw.Close()
+
+	tests := []struct {
+		name           string
+		encoding       string
+		body           []byte
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"gzip", "gzip", gzipBytes(t, payload), http.StatusOK, "jane@example.com"},
+		{"deflate", "deflate", deflated.Bytes(), http.StatusOK, "jane@example.com"},
+		{"no encoding", "", payload, http.StatusOK, "jane@example.com"},
+		{"identity", "identity", payload, http.StatusOK, "jane@example.com"},
+		{"unsupported encoding", "br", payload, http.StatusUnsupportedMediaType, "unsupported_media_type"},
+		{"corrupt gzip", "gzip", payload, http.StatusBadRequest, "invalid_body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.expectedBody) {
+				t.Errorf("expected body to contain %q, got %s", tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestDecompress_SizeCap(t *testing.T) {
+	router := newDecompressRouter(DecompressConfig{MaxBytes: 1 * KB})
+
+	// Highly compressible: ~1MB of JSON shrinks to a few KB
+	large := []byte(`{"name":"` + strings.Repeat("a", 1*MB) + `","email":"jane@example.com"}`)
+	compressed := gzipBytes(t, large)
+
+	req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(compressed))
+	req.Header.Set("Content-Encoding", "gzip")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "payload_too_large") {
+		t.Errorf("expected payload_too_large error, got %s", w.Body.String())
+	}
+
+	// A body exactly at the cap is still accepted
+	exact := []byte(`{"name":"Jane","email":"jane@example.com"}`)
+	router = newDecompressRouter(DecompressConfig{MaxBytes: int64(len(exact))})
+
+	req = httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(gzipBytes(t, exact)))
+	req.Header.Set("Content-Encoding", "gzip")
+	w = httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 for body at the cap, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestDecompress_SizeCapWithBodyValidation(t *testing.T) {
+	router := nimbus.NewRouter()
+	router.Use(Decompress(DecompressConfig{MaxBytes: 1 * KB}))
+
+	validator := nimbus.NewValidator(&decompressTestRequest{})
+	router.AddRoute(http.MethodPost, "/users", func(ctx *nimbus.Context) (any, int, error) {
+		return ctx.MustGet(nimbus.ContextKeyValidatedBody), http.StatusOK, nil
+	}, nimbus.WithBodyValidation(validator))
+
+	large := []byte(`{"name":"` + strings.Repeat("a", 1*MB) + `","email":"jane@example.com"}`)
+	req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(gzipBytes(t, large)))
+	req.Header.Set("Content-Encoding", "gzip")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "payload_too_large") {
+		t.Errorf("expected payload_too_large error, got %s", w.Body.String())
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+// RequireHTTPSConfig defines configuration for the RequireHTTPS middleware
+type RequireHTTPSConfig struct {
+	// Reject responds 403 https_required instead of redirecting plain HTTP requests
+	Reject bool
+	// TrustedProxies lists the proxy addresses (IPs or CIDRs, e.g. "10.0.0.0/8") whose
+	// X-Forwarded-Proto header is honored. Empty trusts the header from any peer, which suits
+	// deployments where the proxy is the only way in and overwrites the header.
+	TrustedProxies []string
+}
+
+// DefaultRequireHTTPSConfig returns a default RequireHTTPS configuration
+func DefaultRequireHTTPSConfig() RequireHTTPSConfig {
+	return RequireHTTPSConfig{}
+}
+
+// RequireHTTPS is a middleware that keeps traffic on HTTPS behind a TLS-terminating proxy.
+// A request is HTTPS if it arrived over TLS or a trusted proxy set X-Forwarded-Proto: https.
+// Plain HTTP requests are 308-redirected to the same URL on https (308 keeps the method and
+// body), or rejected with 403 when Reject is set. HTTPS requests pass through, so the
+// redirect can't loop.
+//
+// Example:
+//
+//	router.Use(middleware.RequireHTTPS(middleware.RequireHTTPSConfig{
+//	    TrustedProxies: []string{"10.0.0.0/8"},
+//	}))
+func RequireHTTPS(configs ...RequireHTTPSConfig) nimbus.Middleware {
+	config := DefaultRequireHTTPSConfig()
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+
+	trusted := parseTrustedProxies(config.TrustedProxies)
+
+	return func(next nimbus.Handler) nimbus.Handler {
+		return func(ctx *nimbus.Context) (any, int, error) {
+			if isHTTPS(ctx.Request, len(trusted) == 0, trusted) {
+				return next(ctx)
+			}
+
+			if config.Reject {
+				return nil, http.StatusForbidden, nimbus.NewAPIError("https_required", "HTTPS is required")
+			}
+
+			ctx.Redirect(http.StatusPermanentRedirect, "https://"+ctx.Request.Host+ctx.Request.URL.RequestURI())
+			return nil, 0, nil
+		}
+	}
+}
+
+// parseTrustedProxies parses IPs and CIDRs into prefixes, panicking on invalid entries
+func parseTrustedProxies(proxies []string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		if prefix, err := netip.ParsePrefix(proxy); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(proxy)
+		if err != nil {
+			panic("RequireHTTPS: invalid trusted proxy " + proxy)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return prefixes
+}
+
+// isHTTPS reports whether a request arrived over TLS, directly or through a trusted proxy
+func isHTTPS(req *http.Request, trustAll bool, trusted []netip.Prefix) bool {
+	if req.TLS != nil {
+		return true
+	}
+
+	// With several proxies the header may be a list; the first entry is the client's scheme
+	proto, _, _ := strings.Cut(req.Header.Get("X-Forwarded-Proto"), ",")
+	if !strings.EqualFold(strings.TrimSpace(proto), "https") {
+		return false
+	}
+	return trustAll || isTrustedPeer(req.RemoteAddr, trusted)
+}
+
+// isTrustedPeer reports whether the connection's remote address is in a trusted prefix
+func isTrustedPeer(remoteAddr string, trusted []netip.Prefix) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+func TestRequireHTTPS(t *testing.T) {
+	newRouter := func(config RequireHTTPSConfig) *nimbus.Router {
+		router := nimbus.NewRouter()
+		router.Use(RequireHTTPS(config))
+		router.POST("/orders", func(ctx *nimbus.Context) (any, int, error) {
+			return map[string]string{"status": "created"}, http.StatusCreated, nil
+		})
+		return router
+	}
+
+	tests := []struct {
+		name             string
+		config           RequireHTTPSConfig
+		remoteAddr       string
+		forwardedProto   string
+		tls              bool
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{"plain http redirected", RequireHTTPSConfig{}, "203.0.113.5:1234", "", false, http.StatusPermanentRedirect, "https://example.com/orders?ref=email"},
+		{"forwarded http redirected", RequireHTTPSConfig{}, "203.0.113.5:1234", "http", false, http.StatusPermanentRedirect, "https://example.com/orders?ref=email"},
+		{"forwarded https passes", RequireHTTPSConfig{}, "203.0.113.5:1234", "https", false, http.StatusCreated, ""},
+		{"forwarded list uses first entry", RequireHTTPSConfig{}, "203.0.113.5:1234", "HTTPS, http", false, http.StatusCreated, ""},
+		{"direct tls passes", RequireHTTPSConfig{}, "203.0.113.5:1234", "", true, http.StatusCreated, ""},
+		{"trusted proxy honored", RequireHTTPSConfig{TrustedProxies: []string{"10.0.0.0/8"}}, "10.1.2.3:5678", "https", false, http.StatusCreated, ""},
+		{"trusted single ip honored", RequireHTTPSConfig{TrustedProxies: []string{"192.0.2.10"}}, "192.0.2.10:5678", "https", false, http.StatusCreated, ""},
+		{"untrusted peer header ignored", RequireHTTPSConfig{TrustedProxies: []string{"10.0.0.0/8"}}, "203.0.113.5:1234", "https", false, http.StatusPermanentRedirect, "https://example.com/orders?ref=email"},
+		{"rejected instead of redirected", RequireHTTPSConfig{Reject: true}, "203.0.113.5:1234", "http", false, http.StatusForbidden, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newRouter(tt.config)
+
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/orders?ref=email", strings.NewReader(`{}`))
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedProto != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.forwardedProto)
+			}
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if location := w.Header().Get("Location"); location != tt.expectedLocation {
+				t.Errorf("expected Location %q, got %q", tt.expectedLocation, location)
+			}
+			if tt.expectedStatus == http.StatusForbidden && !strings.Contains(w.Body.String(), `"https_required"`) {
+				t.Errorf("expected https_required error, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequireHTTPS_InvalidTrustedProxyPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for invalid trusted proxy")
+		}
+	}()
+
+	RequireHTTPS(RequireHTTPSConfig{TrustedProxies: []string{"not-an-ip"}})
+}
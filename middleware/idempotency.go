@@ -0,0 +1,350 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+// IdempotencyConfig defines configuration for the Idempotency middleware
+type IdempotencyConfig struct {
+	// Header names the request header carrying the key (default "Idempotency-Key")
+	Header string
+	// TTL is how long a response is replayed for its key (default 24 hours)
+	TTL time.Duration
+	// Optional lets requests without a key through (unprotected) instead of rejecting them
+	Optional bool
+	// MaxResponseBytes caps the size of a response body that is recorded (default 1MB).
+	// Larger responses are sent but not recorded, so retrying them runs the handler again.
+	MaxResponseBytes int64
+}
+
+// DefaultIdempotencyConfig returns a default Idempotency configuration
+func DefaultIdempotencyConfig() IdempotencyConfig {
+	return IdempotencyConfig{
+		Header:           "Idempotency-Key",
+		TTL:              24 * time.Hour,
+		MaxResponseBytes: 1 * MB,
+	}
+}
+
+// IdempotentResponse is a response recorded for an idempotency key
+type IdempotentResponse struct {
+	StatusCode int
+	// Header holds the headers the handler (and middleware inside Idempotency) set
+	Header http.Header
+	// Body is the handler's data as JSON, or the response body as sent when Raw is set
+	Body []byte
+	// Raw is set when the handler wrote the response itself or returned []byte or string data
+	Raw bool
+	// RequestHash is the hex-encoded SHA-256 of the request body the response answered
+	RequestHash string
+}
+
+// IdempotencyStore stores recorded responses by key. Implementations must be safe for
+// concurrent use; a shared store (e.g. Redis) makes keys work across instances.
+type IdempotencyStore interface {
+	// Get returns the response recorded for key, if there is one that hasn't expired
+	Get(key string) (*IdempotentResponse, bool)
+	// Set records the response for key, to be replayed until ttl has passed
+	Set(key string, response *IdempotentResponse, ttl time.Duration)
+}
+
+// MemoryIdempotencyStore is an in-process IdempotencyStore. Expired entries are dropped
+// when read and swept periodically on writes, so it needs no background goroutine.
+type MemoryIdempotencyStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryIdempotencyEntry
+	lastSweep time.Time
+}
+
+type memoryIdempotencyEntry struct {
+	response  *IdempotentResponse
+	expiresAt time.Time
+}
+
+// idempotencySweepInterval is how often Set removes expired entries from a MemoryIdempotencyStore
+const idempotencySweepInterval = time.Minute
+
+// NewMemoryIdempotencyStore creates an empty in-process IdempotencyStore
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		entries:   make(map[string]memoryIdempotencyEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+// Get returns the unexpired response recorded for key
+func (s *MemoryIdempotencyStore) Get(key string) (*IdempotentResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(s.entries, key)
+		return nil, false
+	}
+	return entry.response, true
+}
+
+// Set records the response for key until ttl has passed
+func (s *MemoryIdempotencyStore) Set(key string, response *IdempotentResponse, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= idempotencySweepInterval {
+		for k, entry := range s.entries {
+			if now.After(entry.expiresAt) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	s.entries[key] = memoryIdempotencyEntry{response: response, expiresAt: now.Add(ttl)}
+}
+
+// Idempotency is a middleware that makes retried POST (and other unsafe) requests safe: the
+// first response for an Idempotency-Key is recorded in store, and requests repeating the key
+// get that response again (with Idempotent-Replayed: true) without running the handler.
+// Keys are scoped to the method and path. Requests without a key are rejected with 400
+// idempotency_key_required unless the config is Optional, and a request whose key is still
+// being handled gets 409 idempotency_key_in_use. GET, HEAD and OPTIONS pass through.
+//
+// A hash of the request body is recorded with the response, and reusing a key with a
+// different body is rejected with 422 idempotency_key_mismatch rather than replayed.
+//
+// Only successful results are recorded: errors, 5xx responses, streamed bodies (io.Reader
+// or channel data) and bodies larger than MaxResponseBytes are not, so the client can
+// retry them.
+//
+// Example:
+//
+//	store := middleware.NewMemoryIdempotencyStore()
+//	router.POST("/payments", createPayment, nimbus.WithMiddleware(middleware.Idempotency(store)))
+func Idempotency(store IdempotencyStore, configs ...IdempotencyConfig) nimbus.Middleware {
+	if store == nil {
+		panic("Idempotency: store is required")
+	}
+
+	config := DefaultIdempotencyConfig()
+	if len(configs) > 0 {
+		config = configs[0]
+		if config.Header == "" {
+			config.Header = "Idempotency-Key"
+		}
+		if config.TTL <= 0 {
+			config.TTL = 24 * time.Hour
+		}
+		if config.MaxResponseBytes <= 0 {
+			config.MaxResponseBytes = 1 * MB
+		}
+	}
+
+	var inFlight sync.Map // scoped key -> struct{}
+
+	return func(next nimbus.Handler) nimbus.Handler {
+		return func(ctx *nimbus.Context) (any, int, error) {
+			switch ctx.Request.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				return next(ctx)
+			}
+
+			key := ctx.GetHeader(config.Header)
+			if key == "" {
+				if config.Optional {
+					return next(ctx)
+				}
+				return nil, http.StatusBadRequest, nimbus.NewAPIError("idempotency_key_required",
+					"The "+config.Header+" header is required")
+			}
+			key = ctx.Request.Method + " " + ctx.Request.URL.Path + " " + key
+
+			requestHash, err := hashRequestBody(ctx)
+			if err != nil {
+				var statusErr *nimbus.StatusError
+				if errors.As(err, &statusErr) {
+					return nil, statusErr.Status, statusErr.APIError
+				}
+				return nil, http.StatusBadRequest, nimbus.NewAPIError("invalid_body", "Request body could not be read")
+			}
+
+			if recorded, ok := store.Get(key); ok {
+				return replayIdempotent(ctx, recorded, requestHash, config.Header)
+			}
+
+			if _, busy := inFlight.LoadOrStore(key, struct{}{}); busy {
+				return nil, http.StatusConflict, nimbus.NewAPIError("idempotency_key_in_use",
+					"A request with this "+config.Header+" is still being processed")
+			}
+			defer inFlight.Delete(key)
+
+			// Another request may have finished between the lookup and the reservation
+			if recorded, ok := store.Get(key); ok {
+				return replayIdempotent(ctx, recorded, requestHash, config.Header)
+			}
+
+			before := ctx.Writer.Header().Clone()
+			recorder := &idempotencyRecorder{ResponseWriter: ctx.Writer, limit: config.MaxResponseBytes}
+			ctx.Writer = recorder
+			data, statusCode, err := next(ctx)
+			ctx.Writer = recorder.ResponseWriter
+
+			if response, ok := recordIdempotent(recorder, before, data, statusCode, err); ok {
+				response.RequestHash = requestHash
+				store.Set(key, response, config.TTL)
+			}
+			return data, statusCode, err
+		}
+	}
+}
+
+// hashRequestBody reads the request body and returns its hex-encoded SHA-256, leaving the
+// body in place for the handler to read
+func hashRequestBody(ctx *nimbus.Context) (string, error) {
+	var body []byte
+	if ctx.Request.Body != nil {
+		var err error
+		if body, err = io.ReadAll(ctx.Request.Body); err != nil {
+			return "", err
+		}
+		ctx.Request.Body.Close()
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// recordIdempotent builds the response to record for a handler's result, reporting false
+// for results that shouldn't be replayed
+func recordIdempotent(recorder *idempotencyRecorder, before http.Header, data any, statusCode int, err error) (*IdempotentResponse, bool) {
+	if err != nil {
+		return nil, false
+	}
+	if statusCode == 0 {
+		data = nil // Already written; the router ignores data returned alongside status 0
+	}
+
+	response := &IdempotentResponse{
+		StatusCode: statusCode,
+		Header:     changedHeaders(before, recorder.Header()),
+	}
+
+	switch body := data.(type) {
+	case nil:
+		if statusCode == 0 {
+			// The handler wrote the response itself
+			if recorder.overflow {
+				return nil, false
+			}
+			response.StatusCode = recorder.status
+			response.Body = recorder.body.Bytes()
+			response.Raw = true
+		}
+	case io.Reader, <-chan []byte, chan []byte:
+		return nil, false
+	case []byte:
+		response.Body = bytes.Clone(body)
+		response.Raw = true
+	case string:
+		response.Body = []byte(body)
+		response.Raw = true
+	default:
+		encoded, marshalErr := json.Marshal(body)
+		if marshalErr != nil {
+			return nil, false
+		}
+		response.Body = encoded
+	}
+
+	if response.StatusCode >= http.StatusInternalServerError || int64(len(response.Body)) > recorder.limit {
+		return nil, false
+	}
+	return response, true
+}
+
+// replayIdempotent returns a recorded response for the router to render again, or a 422 if
+// it answered a different request body
+func replayIdempotent(ctx *nimbus.Context, recorded *IdempotentResponse, requestHash, keyHeader string) (any, int, error) {
+	if recorded.RequestHash != requestHash {
+		return nil, http.StatusUnprocessableEntity, nimbus.NewAPIError("idempotency_key_mismatch",
+			"This "+keyHeader+" was already used with a different request body")
+	}
+
+	header := ctx.Writer.Header()
+	for name, values := range recorded.Header {
+		header[name] = append([]string(nil), values...)
+	}
+	header.Set("Idempotent-Replayed", "true")
+
+	switch {
+	case recorded.Raw:
+		return recorded.Body, recorded.StatusCode, nil
+	case recorded.Body == nil:
+		return nil, recorded.StatusCode, nil
+	default:
+		return json.RawMessage(recorded.Body), recorded.StatusCode, nil
+	}
+}
+
+// changedHeaders returns the headers in after that were added or changed since before
+func changedHeaders(before, after http.Header) http.Header {
+	changed := make(http.Header)
+	for name, values := range after {
+		if strings.Join(before[name], "\x00") != strings.Join(values, "\x00") {
+			changed[name] = append([]string(nil), values...)
+		}
+	}
+	return changed
+}
+
+// idempotencyRecorder passes a handler's writes through while keeping a copy of the body,
+// so responses handlers write themselves can be recorded. The copy is dropped once it
+// would exceed limit bytes.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	limit    int64
+	overflow bool
+}
+
+func (r *idempotencyRecorder) WriteHeader(statusCode int) {
+	if r.status == 0 {
+		r.status = statusCode
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *idempotencyRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	if !r.overflow {
+		if int64(r.body.Len()+len(p)) > r.limit {
+			r.overflow = true
+			r.body = bytes.Buffer{}
+		} else {
+			r.body.Write(p)
+		}
+	}
+	return r.ResponseWriter.Write(p)
+}
+
+// Unwrap returns the underlying ResponseWriter (used by http.ResponseController)
+func (r *idempotencyRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
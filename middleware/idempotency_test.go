@@ -0,0 +1,307 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+func TestIdempotency_ReplaysResponse(t *testing.T) {
+	var calls atomic.Int32
+
+	router := nimbus.NewRouter()
+	router.Use(Idempotency(NewMemoryIdempotencyStore()))
+	router.POST("/payments", func(ctx *nimbus.Context) (any, int, error) {
+		n := calls.Add(1)
+		ctx.Header("Location", "/payments/1")
+		return map[string]any{"id": 1, "charge": n}, http.StatusCreated, nil
+	})
+
+	send := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(`{"amount":100}`))
+		req.Header.Set("Idempotency-Key", key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := send("key-1")
+	second := send("key-1")
+
+	if calls.Load() != 1 {
+		t.Errorf("expected handler to run once, ran %d times", calls.Load())
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Errorf("expected replay %d %s, got %d %s", first.Code, first.Body.String(), second.Code, second.Body.String())
+	}
+	if second.Header().Get("Location") != "/payments/1" {
+		t.Errorf("expected recorded headers to be replayed, got %v", second.Header())
+	}
+	if first.Header().Get("Idempotent-Replayed") != "" || second.Header().Get("Idempotent-Replayed") != "true" {
+		t.Error("expected only the replay to be marked Idempotent-Replayed")
+	}
+
+	send("key-2")
+	if calls.Load() != 2 {
+		t.Errorf("expected a new key to run the handler, ran %d times", calls.Load())
+	}
+}
+
+func TestIdempotency_ReplaysWrittenResponse(t *testing.T) {
+	var calls atomic.Int32
+
+	router := nimbus.NewRouter()
+	router.Use(Idempotency(NewMemoryIdempotencyStore()))
+	router.POST("/receipts", func(ctx *nimbus.Context) (any, int, error) {
+		calls.Add(1)
+		return ctx.String(http.StatusAccepted, "receipt queued")
+	})
+
+	var responses []*httptest.ResponseRecorder
+	for range 2 {
+		req := httptest.NewRequest(http.MethodPost, "/receipts", nil)
+		req.Header.Set("Idempotency-Key", "abc")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		responses = append(responses, w)
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("expected handler to run once, ran %d times", calls.Load())
+	}
+	replay := responses[1]
+	if replay.Code != http.StatusAccepted || replay.Body.String() != "receipt queued" {
+		t.Errorf("expected replayed 202 receipt queued, got %d %q", replay.Code, replay.Body.String())
+	}
+	if replay.Header().Get("Content-Type") != "text/plain" {
+		t.Errorf("expected replayed Content-Type text/plain, got %q", replay.Header().Get("Content-Type"))
+	}
+}
+
+func TestIdempotency_Rules(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        IdempotencyConfig
+		method        string
+		key           string
+		handlerErr    bool
+		expectedCalls int32
+		expectedCode  int
+	}{
+		{"missing key rejected", DefaultIdempotencyConfig(), http.MethodPost, "", false, 0, http.StatusBadRequest},
+		{"missing key allowed when optional", IdempotencyConfig{Optional: true}, http.MethodPost, "", false, 2, http.StatusOK},
+		{"safe methods pass through", DefaultIdempotencyConfig(), http.MethodGet, "", false, 2, http.StatusOK},
+		{"errors are not recorded", DefaultIdempotencyConfig(), http.MethodPost, "k", true, 2, http.StatusConflict},
+		{"custom header", IdempotencyConfig{Header: "X-Request-Key"}, http.MethodPost, "k", false, 1, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			handler := func(ctx *nimbus.Context) (any, int, error) {
+				calls.Add(1)
+				if tt.handlerErr {
+					return nil, http.StatusConflict, nimbus.NewAPIError("conflict", "try again")
+				}
+				return map[string]string{"status": "ok"}, http.StatusOK, nil
+			}
+
+			router := nimbus.NewRouter()
+			router.Use(Idempotency(NewMemoryIdempotencyStore(), tt.config))
+			router.GET("/orders", handler)
+			router.POST("/orders", handler)
+
+			header := tt.config.Header
+			if header == "" {
+				header = "Idempotency-Key"
+			}
+
+			var w *httptest.ResponseRecorder
+			for range 2 {
+				req := httptest.NewRequest(tt.method, "/orders", nil)
+				if tt.key != "" {
+					req.Header.Set(header, tt.key)
+				}
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+			}
+
+			if calls.Load() != tt.expectedCalls {
+				t.Errorf("expected %d handler calls, got %d", tt.expectedCalls, calls.Load())
+			}
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+			if tt.expectedCode == http.StatusBadRequest {
+				var response nimbus.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+					t.Fatal(err)
+				}
+				if response.Error != "idempotency_key_required" {
+					t.Errorf("expected error idempotency_key_required, got %q", response.Error)
+				}
+			}
+		})
+	}
+}
+
+func TestIdempotency_KeyInUse(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	router := nimbus.NewRouter()
+	router.Use(Idempotency(NewMemoryIdempotencyStore()))
+	router.POST("/transfers", func(ctx *nimbus.Context) (any, int, error) {
+		close(started)
+		<-release
+		return map[string]string{"status": "sent"}, http.StatusOK, nil
+	})
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/transfers", nil)
+		req.Header.Set("Idempotency-Key", "transfer-1")
+		return req
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		router.ServeHTTP(httptest.NewRecorder(), newRequest())
+	}()
+	<-started
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newRequest())
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status 409 while the first request runs, got %d", w.Code)
+	}
+
+	close(release)
+	<-done
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, newRequest())
+	if w.Code != http.StatusOK || w.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("expected the finished response to be replayed, got %d", w.Code)
+	}
+}
+
+func TestIdempotency_BodyMismatch(t *testing.T) {
+	var calls atomic.Int32
+
+	router := nimbus.NewRouter()
+	router.Use(Idempotency(NewMemoryIdempotencyStore()))
+	router.POST("/payments", func(ctx *nimbus.Context) (any, int, error) {
+		calls.Add(1)
+		var payment struct {
+			Amount int `json:"amount"`
+		}
+		if err := json.NewDecoder(ctx.Request.Body).Decode(&payment); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		return payment, http.StatusCreated, nil
+	})
+
+	send := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "payment-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := send(`{"amount":100}`)
+	if first.Code != http.StatusCreated || !strings.Contains(first.Body.String(), `"amount":100`) {
+		t.Fatalf("expected the handler to read the body, got %d %s", first.Code, first.Body.String())
+	}
+
+	w := send(`{"amount":5000}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status 422 for a different body, got %d", w.Code)
+	}
+	var response nimbus.ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Error != "idempotency_key_mismatch" {
+		t.Errorf("expected error idempotency_key_mismatch, got %q", response.Error)
+	}
+
+	if w := send(`{"amount":100}`); w.Code != http.StatusCreated || w.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("expected the same body to be replayed, got %d", w.Code)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected handler to run once, ran %d times", calls.Load())
+	}
+}
+
+func TestIdempotency_MaxResponseBytes(t *testing.T) {
+	var calls atomic.Int32
+
+	router := nimbus.NewRouter()
+	router.Use(Idempotency(NewMemoryIdempotencyStore(), IdempotencyConfig{MaxResponseBytes: 64}))
+	router.POST("/small", func(ctx *nimbus.Context) (any, int, error) {
+		calls.Add(1)
+		return "ok", http.StatusOK, nil
+	})
+	router.POST("/large", func(ctx *nimbus.Context) (any, int, error) {
+		calls.Add(1)
+		return strings.Repeat("x", 65), http.StatusOK, nil
+	})
+	router.POST("/large-written", func(ctx *nimbus.Context) (any, int, error) {
+		calls.Add(1)
+		ctx.Writer.WriteHeader(http.StatusOK)
+		for range 3 {
+			ctx.Writer.Write([]byte(strings.Repeat("x", 30)))
+		}
+		return nil, 0, nil
+	})
+
+	tests := []struct {
+		path          string
+		expectedCalls int32
+	}{
+		{"/small", 1},
+		{"/large", 2},
+		{"/large-written", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			calls.Store(0)
+			for range 2 {
+				req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+				req.Header.Set("Idempotency-Key", "k")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				if w.Code != http.StatusOK {
+					t.Fatalf("expected status 200, got %d", w.Code)
+				}
+			}
+			if calls.Load() != tt.expectedCalls {
+				t.Errorf("expected %d handler calls, got %d", tt.expectedCalls, calls.Load())
+			}
+		})
+	}
+}
+
+func TestMemoryIdempotencyStore_TTL(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	store.Set("short", &IdempotentResponse{StatusCode: http.StatusOK}, time.Millisecond)
+	store.Set("long", &IdempotentResponse{StatusCode: http.StatusOK}, time.Hour)
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := store.Get("short"); ok {
+		t.Error("expected expired entry to be gone")
+	}
+	if _, ok := store.Get("long"); !ok {
+		t.Error("expected unexpired entry to be kept")
+	}
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+// JSONOnlyConfig defines configuration for the JSONOnly middleware
+type JSONOnlyConfig struct {
+	// WarnOnly logs requests whose Accept header rules out JSON and serves them anyway,
+	// instead of rejecting them with 406, to find out which clients would break first
+	WarnOnly bool
+}
+
+// DefaultJSONOnlyConfig returns a default JSONOnly configuration (reject non-JSON clients)
+func DefaultJSONOnlyConfig() JSONOnlyConfig {
+	return JSONOnlyConfig{}
+}
+
+// JSONOnly is a middleware for APIs that only speak JSON. Requests whose Accept header
+// doesn't accept application/json (directly, through a +json type, or with application/* or
+// */*) are rejected with 406 not_acceptable, so a browser asking for text/html gets an error
+// rather than a page. Requests without an Accept header accept anything and pass.
+//
+// Every response is sent as application/json: a Content-Type that isn't JSON, including one
+// set by ctx.HTML or ctx.String, is replaced, so no response can be rendered as a page.
+// JSON types such as application/problem+json are kept.
+//
+// Example:
+//
+//	router.Use(middleware.JSONOnly())
+//
+//	// Log clients that would be rejected, without rejecting them yet
+//	router.Use(middleware.JSONOnly(middleware.JSONOnlyConfig{WarnOnly: true}))
+func JSONOnly(configs ...JSONOnlyConfig) nimbus.Middleware {
+	config := DefaultJSONOnlyConfig()
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+
+	return func(next nimbus.Handler) nimbus.Handler {
+		return func(ctx *nimbus.Context) (any, int, error) {
+			// Installed first so the 406 below is sent as JSON too
+			ctx.Writer = &jsonOnlyWriter{ResponseWriter: ctx.Writer}
+
+			if accept := ctx.GetHeader("Accept"); accept != "" && !acceptsJSON(accept) {
+				if !config.WarnOnly {
+					return nil, http.StatusNotAcceptable, nimbus.NewAPIError("not_acceptable",
+						"This API only serves application/json")
+				}
+				log.Printf("JSONOnly: %s %s accepts %q, which excludes application/json",
+					ctx.Request.Method, ctx.Request.URL.Path, accept)
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// acceptsJSON reports whether an Accept header admits an application/json response
+func acceptsJSON(accept string) bool {
+	for _, entry := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(entry))
+		if err != nil {
+			continue
+		}
+		// q=0 marks a type the client refuses
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q == 0 {
+			continue
+		}
+		if mediaType == "*/*" || mediaType == "application/*" || isJSONMediaType(mediaType) {
+			return true
+		}
+	}
+	return false
+}
+
+// isJSONMediaType reports whether a media type is application/json or a +json type
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// jsonOnlyWriter makes the response's Content-Type JSON on the first WriteHeader or Write
+type jsonOnlyWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *jsonOnlyWriter) WriteHeader(statusCode int) {
+	// Bodiless responses get no Content-Type to correct
+	if statusCode != http.StatusNoContent && statusCode != http.StatusNotModified {
+		w.setContentType()
+	}
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *jsonOnlyWriter) Write(b []byte) (int, error) {
+	w.setContentType()
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *jsonOnlyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *jsonOnlyWriter) setContentType() {
+	if w.wroteHeader {
+		return
+	}
+	if mediaType, _, err := mime.ParseMediaType(w.Header().Get("Content-Type")); err == nil && isJSONMediaType(mediaType) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+}
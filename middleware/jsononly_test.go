@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+func TestJSONOnly(t *testing.T) {
+	router := nimbus.NewRouter()
+	router.Use(JSONOnly())
+	router.GET("/users", func(ctx *nimbus.Context) (any, int, error) {
+		return []string{"ada", "grace"}, http.StatusOK, nil
+	})
+
+	tests := []struct {
+		name           string
+		accept         string
+		expectedStatus int
+	}{
+		{"json", "application/json", http.StatusOK},
+		{"no accept header", "", http.StatusOK},
+		{"any type", "*/*", http.StatusOK},
+		{"any application type", "application/*", http.StatusOK},
+		{"json among others", "text/html, application/json;q=0.9", http.StatusOK},
+		{"json suffix type", "application/problem+json", http.StatusOK},
+		{"html", "text/html", http.StatusNotAcceptable},
+		{"browser", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif", http.StatusNotAcceptable},
+		{"json refused", "application/json;q=0, text/html", http.StatusNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("expected Content-Type application/json, got %q", contentType)
+			}
+			if tt.expectedStatus == http.StatusNotAcceptable {
+				var response nimbus.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+					t.Fatal(err)
+				}
+				if response.Error != "not_acceptable" {
+					t.Errorf("expected error not_acceptable, got %q", response.Error)
+				}
+			}
+		})
+	}
+}
+
+func TestJSONOnly_WarnOnly(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(nil)
+
+	router := nimbus.NewRouter()
+	router.Use(JSONOnly(JSONOnlyConfig{WarnOnly: true}))
+	router.GET("/users", func(ctx *nimbus.Context) (any, int, error) {
+		return []string{"ada"}, http.StatusOK, nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("Accept", "text/html")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+	if !strings.Contains(buf.String(), `GET /users accepts "text/html"`) {
+		t.Errorf("expected a warning for the text/html request, got %q", buf.String())
+	}
+}
+
+func TestJSONOnly_ResponseContentType(t *testing.T) {
+	router := nimbus.NewRouter()
+	router.Use(JSONOnly())
+	router.GET("/html", func(ctx *nimbus.Context) (any, int, error) {
+		return ctx.HTML(http.StatusOK, "<script>alert(1)</script>")
+	})
+	router.GET("/text", func(ctx *nimbus.Context) (any, int, error) {
+		return "plain", http.StatusOK, nil
+	})
+	router.GET("/problem", func(ctx *nimbus.Context) (any, int, error) {
+		return ctx.Data(http.StatusBadRequest, "application/problem+json", []byte(`{"title":"bad"}`))
+	})
+	router.DELETE("/users/:id", func(ctx *nimbus.Context) (any, int, error) {
+		return nil, http.StatusNoContent, nil
+	})
+
+	tests := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{http.MethodGet, "/html", "application/json"},
+		{http.MethodGet, "/text", "application/json"},
+		{http.MethodGet, "/problem", "application/problem+json"},
+		{http.MethodGet, "/missing", "application/json"},
+		{http.MethodDelete, "/users/42", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if contentType := w.Header().Get("Content-Type"); contentType != tt.expected {
+				t.Errorf("expected Content-Type %q, got %q", tt.expected, contentType)
+			}
+		})
+	}
+}
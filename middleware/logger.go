@@ -113,7 +113,8 @@ func Logger(config LoggerConfig) nimbus.Middleware {
 				Dur("duration", duration).
 				Int("status", statusCode)
 
-			// Add request ID if available (automatically added by RequestID middleware)
+			// Add request ID if available (automatically added by RequestID middleware under
+			// nimbus.ContextKeyRequestID, which the string key falls back to)
 			if requestID := ctx.GetString("request_id"); requestID != "" {
 				event = event.Str("request_id", requestID)
 			}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+// LimitQueryParams is a middleware that guards against query-parameter pollution by
+// rejecting requests with more than max query parameters with 400 too_many_params.
+//
+// Every key=value pair counts, so repeated keys count once per occurrence:
+// ?tag=a&tag=b&page=1 has 3 parameters. Empty segments (?a=1&&b=2) are ignored, as they
+// are when the query is parsed. Parameters are counted without parsing the query, so an
+// oversized query is rejected before it allocates.
+//
+// Example:
+//
+//	router.Use(middleware.LimitQueryParams(50))
+func LimitQueryParams(max int) nimbus.Middleware {
+	return func(next nimbus.Handler) nimbus.Handler {
+		return func(ctx *nimbus.Context) (any, int, error) {
+			if count := countQueryParams(ctx.Request.URL.RawQuery); count > max {
+				return nil, http.StatusBadRequest, nimbus.NewAPIError("too_many_params",
+					fmt.Sprintf("Too many query parameters: %d (maximum is %d)", count, max))
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// countQueryParams counts the non-empty &-separated segments of a raw query string
+func countQueryParams(rawQuery string) int {
+	count := 0
+	for rawQuery != "" {
+		var segment string
+		segment, rawQuery, _ = strings.Cut(rawQuery, "&")
+		if segment != "" {
+			count++
+		}
+	}
+	return count
+}
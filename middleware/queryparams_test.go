@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+func TestLimitQueryParams(t *testing.T) {
+	router := nimbus.NewRouter()
+	router.Use(LimitQueryParams(3))
+	router.GET("/search", func(ctx *nimbus.Context) (any, int, error) {
+		return map[string]string{"q": ctx.Query("q")}, http.StatusOK, nil
+	})
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{"no query", "", http.StatusOK},
+		{"under the limit", "q=shoes&page=2", http.StatusOK},
+		{"at the limit", "q=shoes&page=2&limit=10", http.StatusOK},
+		{"empty segments ignored", "q=shoes&&page=2&", http.StatusOK},
+		{"over the limit", "q=shoes&page=2&limit=10&sort=price", http.StatusBadRequest},
+		{"repeated keys count per occurrence", "tag=a&tag=b&tag=c&tag=d", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/search?"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				var response nimbus.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+					t.Fatal(err)
+				}
+				if response.Error != "too_many_params" {
+					t.Errorf("expected error too_many_params, got %q", response.Error)
+				}
+			}
+		})
+	}
+}
@@ -1,7 +1,13 @@
 package middleware
 
 import (
+	"container/list"
+	"fmt"
+	"math"
+	"math/rand/v2"
 	"net/http"
+	"slices"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -15,8 +21,12 @@ import (
 type RateLimiter struct {
 	buckets   sync.Map      // key (string) -> *bucket (lock-free map)
 	rate      int           // tokens per second
+	perSecond float64       // refill rate for limits over other windows (0 = rate)
 	capacity  int           // maximum burst size
 	cleanup   time.Duration // how often to remove stale buckets
+	expiry    time.Duration // how long a bucket may sit idle before removal (0 = cleanup)
+	lru       *bucketLRU    // recency order when the bucket count is capped (nil = unbounded)
+	jitter    time.Duration // maximum random delay added to Retry-After
 	done      chan struct{} // signal to stop cleanup goroutine
 	closeOnce sync.Once     // ensures Close() is called only once
 }
@@ -24,8 +34,122 @@ type RateLimiter struct {
 // bucket represents a lock-free token bucket using atomic operations.
 // All fields are accessed atomically to avoid lock contention.
 type bucket struct {
-	tokens   atomic.Int64 // current token count (atomic for lock-free updates)
-	lastSeen atomic.Int64 // last access time in Unix nanoseconds (atomic for lock-free updates)
+	tokens   atomic.Uint64 // current token count as float64 bits (atomic for lock-free updates)
+	lastSeen atomic.Int64  // last access time in Unix nanoseconds (atomic for lock-free updates)
+	elem     *list.Element // position in the LRU list (guarded by bucketLRU.mu, nil if untracked)
+}
+
+// tokenCount returns the current (fractional) token count
+func (b *bucket) tokenCount() float64 {
+	return math.Float64frombits(b.tokens.Load())
+}
+
+// storeTokens sets the token count
+func (b *bucket) storeTokens(tokens float64) {
+	b.tokens.Store(math.Float64bits(tokens))
+}
+
+// bucketLRU tracks bucket recency so the least-recently-seen bucket can be evicted
+// once the limiter holds maxEntries buckets. Unlike the buckets map it takes a lock,
+// so it is only allocated when WithMaxEntries is used.
+type bucketLRU struct {
+	mu         sync.Mutex
+	order      *list.List // front = most recently seen; values are lruEntry
+	maxEntries int
+}
+
+// lruEntry identifies a tracked bucket
+type lruEntry struct {
+	key    string
+	bucket *bucket
+}
+
+// add records a new bucket as most recently seen and returns the buckets evicted to stay within the cap
+func (l *bucketLRU) add(key string, b *bucket) []lruEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b.elem = l.order.PushFront(lruEntry{key: key, bucket: b})
+
+	var evicted []lruEntry
+	for l.order.Len() > l.maxEntries {
+		oldest := l.order.Remove(l.order.Back()).(lruEntry)
+		oldest.bucket.elem = nil
+		evicted = append(evicted, oldest)
+	}
+	return evicted
+}
+
+// touch marks a bucket as most recently seen
+func (l *bucketLRU) touch(b *bucket) {
+	l.mu.Lock()
+	if b.elem != nil {
+		l.order.MoveToFront(b.elem)
+	}
+	l.mu.Unlock()
+}
+
+// remove stops tracking a bucket deleted by cleanup
+func (l *bucketLRU) remove(b *bucket) {
+	l.mu.Lock()
+	if b.elem != nil {
+		l.order.Remove(b.elem)
+		b.elem = nil
+	}
+	l.mu.Unlock()
+}
+
+// RateLimiterOption configures a RateLimiter
+type RateLimiterOption func(*RateLimiter)
+
+// WithCleanupInterval sets how often the cleanup goroutine scans for idle buckets.
+// Default: 5 minutes. Non-positive values are ignored.
+func WithCleanupInterval(interval time.Duration) RateLimiterOption {
+	return func(rl *RateLimiter) {
+		if interval > 0 {
+			rl.cleanup = interval
+		}
+	}
+}
+
+// WithIdleExpiry sets how long a bucket may go unused before cleanup removes it.
+// Default: 5 minutes. Non-positive values are ignored.
+//
+// A short expiry reclaims memory quickly in high-churn deployments; a removed bucket
+// starts full again, so the expiry should be at least capacity/rate to avoid resetting
+// clients that are still being limited.
+func WithIdleExpiry(expiry time.Duration) RateLimiterOption {
+	return func(rl *RateLimiter) {
+		if expiry > 0 {
+			rl.expiry = expiry
+		}
+	}
+}
+
+// WithMaxEntries caps the number of buckets the limiter keeps. When a new key would
+// exceed the cap, the least-recently-seen bucket is evicted, so a flood of spoofed or
+// high-cardinality keys cannot exhaust memory before cleanup runs. An evicted client
+// starts over with a full bucket. Default: 0 (unbounded). Non-positive values are ignored.
+//
+// Tracking recency takes a lock on every request, which costs some throughput under
+// heavy concurrency compared to the default lock-free limiter.
+func WithMaxEntries(maxEntries int) RateLimiterOption {
+	return func(rl *RateLimiter) {
+		if maxEntries > 0 {
+			rl.lru = &bucketLRU{order: list.New(), maxEntries: maxEntries}
+		}
+	}
+}
+
+// WithRetryAfterJitter adds a random delay in [0, maxJitter) to the Retry-After header of
+// 429 responses, so clients limited at the same moment don't all retry at the same moment.
+// Default: 0 (no jitter). Non-positive values are ignored.
+func WithRetryAfterJitter(maxJitter time.Duration) RateLimiterOption {
+	return func(rl *RateLimiter) {
+		if maxJitter > 0 {
+			rl.jitter = maxJitter
+		}
+	}
 }
 
 // NewRateLimiter creates a new lock-free rate limiter using atomic operations.
@@ -33,18 +157,25 @@ type bucket struct {
 // Parameters:
 //   - rate: tokens added per second (e.g., 10 = 10 requests per second)
 //   - capacity: maximum burst size (e.g., 20 = allow bursts of 20 requests)
+//   - opts: optional settings such as WithCleanupInterval, WithIdleExpiry, WithMaxEntries,
+//     and WithRetryAfterJitter
 //
 // The rate limiter uses sync.Map for lock-free concurrent access and atomic operations
 // for token updates, providing excellent performance under high concurrency.
-func NewRateLimiter(rate, capacity int) *RateLimiter {
+func NewRateLimiter(rate, capacity int, opts ...RateLimiterOption) *RateLimiter {
 	rl := &RateLimiter{
 		buckets:  sync.Map{}, // lock-free map
 		rate:     rate,
 		capacity: capacity,
 		cleanup:  time.Minute * 5,
+		expiry:   time.Minute * 5,
 		done:     make(chan struct{}),
 	}
 
+	for _, opt := range opts {
+		opt(rl)
+	}
+
 	// Start cleanup goroutine (runs lock-free)
 	go rl.cleanupLoop()
 
@@ -73,7 +204,7 @@ func (rl *RateLimiter) cleanupLoop() {
 		case <-ticker.C:
 			// Lock-free cleanup: iterate and delete stale entries
 			now := time.Now().UnixNano()
-			cleanupThreshold := now - int64(rl.cleanup)
+			cleanupThreshold := now - int64(rl.idleExpiry())
 
 			// Range over sync.Map (lock-free iteration)
 			rl.buckets.Range(func(key, value any) bool {
@@ -83,6 +214,9 @@ func (rl *RateLimiter) cleanupLoop() {
 				// Delete buckets that haven't been accessed recently
 				if lastSeen < cleanupThreshold {
 					rl.buckets.Delete(key)
+					if rl.lru != nil {
+						rl.lru.remove(b)
+					}
 				}
 
 				return true // continue iteration
@@ -95,17 +229,36 @@ func (rl *RateLimiter) cleanupLoop() {
 	}
 }
 
+// idleExpiry returns how long a bucket may sit idle, defaulting to the cleanup interval
+func (rl *RateLimiter) idleExpiry() time.Duration {
+	if rl.expiry > 0 {
+		return rl.expiry
+	}
+	return rl.cleanup
+}
+
 // allow checks if a request should be allowed using lock-free atomic operations.
+func (rl *RateLimiter) allow(key string) bool {
+	allowed, _ := rl.take(key)
+	return allowed
+}
+
+// take consumes a token for key if one is available. When the request is rate limited it
+// also returns how long until a token will be available (plus jitter, if configured).
 // Implements the token bucket algorithm with compare-and-swap (CAS) for thread safety.
 // 
 // Algorithm:
 // 1. Load or create bucket atomically
-// 2. Calculate token refill based on time elapsed
-// 3. Try to consume a token with atomic CAS
+// 2. Claim the refill for the time elapsed since lastSeen by advancing lastSeen with CAS
+// 3. Add the (fractional) refill and try to consume a token with atomic CAS
 // 4. If CAS fails (race condition), retry
 //
+// Tokens are fractional, so at low rates a refill smaller than one token is carried over
+// instead of truncated away. Since every elapsed interval is claimed by exactly one
+// request, frequent polling cannot stall the refill.
+//
 // This approach provides true lock-free performance with no contention.
-func (rl *RateLimiter) allow(key string) bool {
+func (rl *RateLimiter) take(key string) (bool, time.Duration) {
 	now := time.Now().UnixNano()
 
 	// Load or create bucket atomically (lock-free)
@@ -114,43 +267,50 @@ func (rl *RateLimiter) allow(key string) bool {
 
 	// If this is a new bucket, initialize it
 	if !loaded {
-		b.tokens.Store(int64(rl.capacity - 1))
+		b.storeTokens(float64(rl.capacity - 1))
 		b.lastSeen.Store(now)
-		return true // first request always allowed
+		if rl.lru != nil {
+			for _, evicted := range rl.lru.add(key, b) {
+				// Only delete the evicted bucket itself, not a newer bucket stored under the same key
+				rl.buckets.CompareAndDelete(evicted.key, evicted.bucket)
+			}
+		}
+		return true, 0 // first request always allowed
+	}
+
+	if rl.lru != nil {
+		rl.lru.touch(b)
+	}
+
+	// Claim the refill for the time since lastSeen; a concurrent request that advanced
+	// lastSeen first has already claimed it, so this one adds nothing
+	var refill float64
+	if lastSeen := b.lastSeen.Load(); now > lastSeen && b.lastSeen.CompareAndSwap(lastSeen, now) {
+		elapsedSeconds := float64(now-lastSeen) / float64(time.Second)
+		refill = elapsedSeconds * rl.refillRate()
 	}
 
 	// Token bucket algorithm with atomic compare-and-swap (CAS)
 	// Loop until we successfully update or determine we're rate limited
 	for {
 		// Load current state atomically
-		currentTokens := b.tokens.Load()
-		lastSeen := b.lastSeen.Load()
-
-		// Calculate elapsed time and token refill
-		elapsedNanos := now - lastSeen
-		elapsedSeconds := float64(elapsedNanos) / float64(time.Second)
-		refill := int64(elapsedSeconds * float64(rl.rate))
+		currentBits := b.tokens.Load()
+		currentTokens := math.Float64frombits(currentBits)
 
 		// Calculate new token count (capped at capacity)
-		newTokens := currentTokens + refill
-		if newTokens > int64(rl.capacity) {
-			newTokens = int64(rl.capacity)
-		}
+		newTokens := math.Min(currentTokens+refill, float64(rl.capacity))
 
-		// Check if we have tokens available
-		if newTokens <= 0 {
-			// Rate limited - no tokens available
-			// Try to update lastSeen to prevent stale timestamp
-			b.lastSeen.CompareAndSwap(lastSeen, now)
-			return false
+		// Rate limited - store the refill so it is carried over, and report the wait
+		if newTokens < 1 {
+			if b.tokens.CompareAndSwap(currentBits, math.Float64bits(newTokens)) {
+				return false, rl.retryAfter(newTokens)
+			}
+			continue
 		}
 
 		// Try to consume a token atomically (CAS loop)
-		if b.tokens.CompareAndSwap(currentTokens, newTokens-1) {
-			// Successfully consumed a token
-			// Update lastSeen timestamp (best effort, not critical if it fails)
-			b.lastSeen.CompareAndSwap(lastSeen, now)
-			return true
+		if b.tokens.CompareAndSwap(currentBits, math.Float64bits(newTokens-1)) {
+			return true, 0
 		}
 
 		// CAS failed due to race condition, retry
@@ -159,6 +319,60 @@ func (rl *RateLimiter) allow(key string) bool {
 	}
 }
 
+// refillRate returns the tokens added per second
+func (rl *RateLimiter) refillRate() float64 {
+	if rl.perSecond > 0 {
+		return rl.perSecond
+	}
+	return float64(rl.rate)
+}
+
+// refund returns a token consumed by take, e.g. when another tier rejected the request
+func (rl *RateLimiter) refund(key string) {
+	value, ok := rl.buckets.Load(key)
+	if !ok {
+		return
+	}
+	b := value.(*bucket)
+
+	for {
+		currentBits := b.tokens.Load()
+		refunded := math.Min(math.Float64frombits(currentBits)+1, float64(rl.capacity))
+		if b.tokens.CompareAndSwap(currentBits, math.Float64bits(refunded)) {
+			return
+		}
+	}
+}
+
+// retryAfter returns how long until a bucket holding tokens has a whole token, plus jitter
+func (rl *RateLimiter) retryAfter(tokens float64) time.Duration {
+	rate := rl.refillRate()
+	if rate <= 0 {
+		return 0
+	}
+
+	wait := time.Duration((1 - tokens) / rate * float64(time.Second))
+	if rl.jitter > 0 {
+		wait += rand.N(rl.jitter)
+	}
+	return wait
+}
+
+// rateLimitExceeded renders the 429 response, advertising when to retry
+func rateLimitExceeded(ctx *nimbus.Context, retryAfter time.Duration) (any, int, error) {
+	return rateLimitExceededWithMessage(ctx, retryAfter, "Too many requests, please try again later")
+}
+
+// rateLimitExceededWithMessage is rateLimitExceeded with a custom error message
+func rateLimitExceededWithMessage(ctx *nimbus.Context, retryAfter time.Duration, message string) (any, int, error) {
+	if retryAfter > 0 {
+		// Retry-After is in whole seconds; round up so clients never retry too early
+		seconds := int64((retryAfter + time.Second - 1) / time.Second)
+		ctx.Header("Retry-After", strconv.FormatInt(seconds, 10))
+	}
+	return nil, http.StatusTooManyRequests, nimbus.NewAPIError("rate_limit_exceeded", message)
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -205,8 +419,15 @@ func ShutdownAllRateLimiters() {
 // Limits requests per IP address.
 // The rate limiter's cleanup goroutine will be automatically stopped when router.Shutdown() is called.
 // This is the recommended way to use rate limiting.
-func RateLimitWithRouter(router interface{ RegisterCleanup(func()) }, requestsPerSecond, burst int) nimbus.Middleware {
-	limiter := NewRateLimiter(requestsPerSecond, burst)
+//
+// Example:
+//
+//	// Reclaim buckets of clients idle for more than a minute
+//	router.Use(middleware.RateLimitWithRouter(router, 10, 20,
+//		middleware.WithCleanupInterval(30*time.Second),
+//		middleware.WithIdleExpiry(time.Minute)))
+func RateLimitWithRouter(router interface{ RegisterCleanup(func()) }, requestsPerSecond, burst int, opts ...RateLimiterOption) nimbus.Middleware {
+	limiter := NewRateLimiter(requestsPerSecond, burst, opts...)
 	router.RegisterCleanup(limiter.Close)
 
 	return func(next nimbus.Handler) nimbus.Handler {
@@ -214,8 +435,8 @@ func RateLimitWithRouter(router interface{ RegisterCleanup(func()) }, requestsPe
 			// Use IP address as key
 			key := ctx.Request.RemoteAddr
 
-			if !limiter.allow(key) {
-				return nil, http.StatusTooManyRequests, nimbus.NewAPIError("rate_limit_exceeded", "Too many requests, please try again later")
+			if allowed, retryAfter := limiter.take(key); !allowed {
+				return rateLimitExceeded(ctx, retryAfter)
 			}
 
 			return next(ctx)
@@ -228,8 +449,8 @@ func RateLimitWithRouter(router interface{ RegisterCleanup(func()) }, requestsPe
 // DEPRECATED: Use RateLimitWithRouter instead for automatic cleanup.
 // Note: The rate limiter's cleanup goroutine will run until the application exits
 // or ShutdownAllRateLimiters() is called
-func RateLimit(requestsPerSecond, burst int) nimbus.Middleware {
-	limiter := NewRateLimiter(requestsPerSecond, burst)
+func RateLimit(requestsPerSecond, burst int, opts ...RateLimiterOption) nimbus.Middleware {
+	limiter := NewRateLimiter(requestsPerSecond, burst, opts...)
 	registerLimiter(limiter)
 
 	return func(next nimbus.Handler) nimbus.Handler {
@@ -237,8 +458,8 @@ func RateLimit(requestsPerSecond, burst int) nimbus.Middleware {
 			// Use IP address as key
 			key := ctx.Request.RemoteAddr
 
-			if !limiter.allow(key) {
-				return nil, http.StatusTooManyRequests, nimbus.NewAPIError("rate_limit_exceeded", "Too many requests, please try again later")
+			if allowed, retryAfter := limiter.take(key); !allowed {
+				return rateLimitExceeded(ctx, retryAfter)
 			}
 
 			return next(ctx)
@@ -251,8 +472,8 @@ func RateLimit(requestsPerSecond, burst int) nimbus.Middleware {
 // Useful for API key based rate limiting.
 // The rate limiter's cleanup goroutine will be automatically stopped when router.Shutdown() is called.
 // This is the recommended way to use rate limiting.
-func RateLimitByHeaderWithRouter(router interface{ RegisterCleanup(func()) }, header string, requestsPerSecond, burst int) nimbus.Middleware {
-	limiter := NewRateLimiter(requestsPerSecond, burst)
+func RateLimitByHeaderWithRouter(router interface{ RegisterCleanup(func()) }, header string, requestsPerSecond, burst int, opts ...RateLimiterOption) nimbus.Middleware {
+	limiter := NewRateLimiter(requestsPerSecond, burst, opts...)
 	router.RegisterCleanup(limiter.Close)
 
 	return func(next nimbus.Handler) nimbus.Handler {
@@ -262,8 +483,8 @@ func RateLimitByHeaderWithRouter(router interface{ RegisterCleanup(func()) }, he
 				key = ctx.Request.RemoteAddr
 			}
 
-			if !limiter.allow(key) {
-				return nil, http.StatusTooManyRequests, nimbus.NewAPIError("rate_limit_exceeded", "Too many requests, please try again later")
+			if allowed, retryAfter := limiter.take(key); !allowed {
+				return rateLimitExceeded(ctx, retryAfter)
 			}
 
 			return next(ctx)
@@ -276,8 +497,8 @@ func RateLimitByHeaderWithRouter(router interface{ RegisterCleanup(func()) }, he
 // DEPRECATED: Use RateLimitByHeaderWithRouter instead for automatic cleanup.
 // Note: The rate limiter's cleanup goroutine will run until the application exits
 // or ShutdownAllRateLimiters() is called
-func RateLimitByHeader(header string, requestsPerSecond, burst int) nimbus.Middleware {
-	limiter := NewRateLimiter(requestsPerSecond, burst)
+func RateLimitByHeader(header string, requestsPerSecond, burst int, opts ...RateLimiterOption) nimbus.Middleware {
+	limiter := NewRateLimiter(requestsPerSecond, burst, opts...)
 	registerLimiter(limiter)
 
 	return func(next nimbus.Handler) nimbus.Handler {
@@ -287,8 +508,66 @@ func RateLimitByHeader(header string, requestsPerSecond, burst int) nimbus.Middl
 				key = ctx.Request.RemoteAddr
 			}
 
-			if !limiter.allow(key) {
-				return nil, http.StatusTooManyRequests, nimbus.NewAPIError("rate_limit_exceeded", "Too many requests, please try again later")
+			if allowed, retryAfter := limiter.take(key); !allowed {
+				return rateLimitExceeded(ctx, retryAfter)
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// RateLimitTier is one limit enforced by TieredRateLimitWithRouter: at most Limit requests
+// per Window, with bursts of up to Limit requests.
+type RateLimitTier struct {
+	Name   string        // identifies the tier in 429 responses, e.g. "burst" or "hourly"
+	Limit  int           // requests allowed per window (also the burst size)
+	Window time.Duration // window the limit applies to, e.g. time.Second or time.Hour
+}
+
+// TieredRateLimitWithRouter returns a rate limiting middleware that enforces several limits
+// at once per IP address, such as a per-second burst limit and an hourly sustained limit.
+// A request must pass every tier; the 429 response names the tier that tripped, and tokens
+// taken from the other tiers are returned so a rejected request does not count against them.
+// The limiters' cleanup goroutines are stopped when router.Shutdown() is called.
+//
+// Example:
+//
+//	router.Use(middleware.TieredRateLimitWithRouter(router,
+//		middleware.RateLimitTier{Name: "burst", Limit: 10, Window: time.Second},
+//		middleware.RateLimitTier{Name: "hourly", Limit: 1000, Window: time.Hour},
+//	))
+func TieredRateLimitWithRouter(router interface{ RegisterCleanup(func()) }, tiers ...RateLimitTier) nimbus.Middleware {
+	tiers = slices.Clone(tiers)
+	limiters := make([]*RateLimiter, len(tiers))
+	for i, tier := range tiers {
+		if tier.Limit <= 0 || tier.Window <= 0 {
+			panic(fmt.Sprintf("rate limit tier %q must have a positive Limit and Window", tier.Name))
+		}
+		if tier.Name == "" {
+			tiers[i].Name = fmt.Sprintf("%d per %s", tier.Limit, tier.Window)
+		}
+
+		// Idle buckets can be dropped once a full window has refilled them
+		expiry := max(tier.Window, time.Minute*5)
+		limiters[i] = NewRateLimiter(tier.Limit, tier.Limit, WithIdleExpiry(expiry))
+		limiters[i].perSecond = float64(tier.Limit) / tier.Window.Seconds()
+		router.RegisterCleanup(limiters[i].Close)
+	}
+
+	return func(next nimbus.Handler) nimbus.Handler {
+		return func(ctx *nimbus.Context) (any, int, error) {
+			// Use IP address as key
+			key := ctx.Request.RemoteAddr
+
+			for i, limiter := range limiters {
+				if allowed, retryAfter := limiter.take(key); !allowed {
+					for _, passed := range limiters[:i] {
+						passed.refund(key)
+					}
+					return rateLimitExceededWithMessage(ctx, retryAfter,
+						fmt.Sprintf("Too many requests (%s limit exceeded), please try again later", tiers[i].Name))
+				}
 			}
 
 			return next(ctx)
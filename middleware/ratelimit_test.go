@@ -1,8 +1,11 @@
 package middleware
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -54,10 +57,10 @@ func TestRateLimiter_Allow_FirstRequest(t *testing.T) {
 	bucket := value.(*bucket)
 
 	// Should have capacity - 1 tokens left
-	expectedTokens := int64(19)
-	actualTokens := bucket.tokens.Load()
+	expectedTokens := float64(19)
+	actualTokens := bucket.tokenCount()
 	if actualTokens != expectedTokens {
-		t.Errorf("expected %d tokens, got %d", expectedTokens, actualTokens)
+		t.Errorf("expected %v tokens, got %v", expectedTokens, actualTokens)
 	}
 }
 
@@ -458,3 +461,340 @@ func TestRateLimiter_CleanupGoroutineStopped(t *testing.T) {
 		t.Error("Cleanup goroutine did not stop after Close()")
 	}
 }
+
+func TestRateLimiter_CleanupOptions(t *testing.T) {
+	limiter := NewRateLimiter(10, 20, WithCleanupInterval(10*time.Millisecond), WithIdleExpiry(100*time.Millisecond))
+	defer limiter.Close()
+
+	if limiter.cleanup != 10*time.Millisecond {
+		t.Errorf("expected cleanup interval %v, got %v", 10*time.Millisecond, limiter.cleanup)
+	}
+	if limiter.expiry != 100*time.Millisecond {
+		t.Errorf("expected idle expiry %v, got %v", 100*time.Millisecond, limiter.expiry)
+	}
+
+	limiter.allow("idle")
+	limiter.allow("active")
+
+	// Several cleanup passes run before the expiry, so the idle bucket must survive them
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := limiter.buckets.Load("idle"); !ok {
+		t.Error("expected idle bucket to survive until the expiry")
+	}
+
+	// Keep one bucket active while the other passes its expiry
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		limiter.allow("active")
+		if _, ok := limiter.buckets.Load("idle"); !ok {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, ok := limiter.buckets.Load("idle"); ok {
+		t.Error("expected idle bucket to be purged after the expiry")
+	}
+	if _, ok := limiter.buckets.Load("active"); !ok {
+		t.Error("expected active bucket to be kept")
+	}
+}
+
+func TestRateLimiter_CleanupOptions_IgnoreNonPositive(t *testing.T) {
+	limiter := NewRateLimiter(10, 20, WithCleanupInterval(0), WithIdleExpiry(-time.Second))
+	defer limiter.Close()
+
+	if limiter.cleanup != time.Minute*5 || limiter.expiry != time.Minute*5 {
+		t.Errorf("expected 5 minute defaults, got interval %v expiry %v", limiter.cleanup, limiter.expiry)
+	}
+}
+
+func TestRateLimiter_MaxEntries(t *testing.T) {
+	limiter := NewRateLimiter(10, 20, WithMaxEntries(3))
+	defer limiter.Close()
+
+	for _, key := range []string{"a", "b", "c"} {
+		limiter.allow(key)
+	}
+
+	// Seeing "a" again makes "b" the least recently seen bucket
+	limiter.allow("a")
+	limiter.allow("d")
+
+	if _, ok := limiter.buckets.Load("b"); ok {
+		t.Error("expected least recently seen bucket to be evicted")
+	}
+	for _, key := range []string{"a", "c", "d"} {
+		if _, ok := limiter.buckets.Load(key); !ok {
+			t.Errorf("expected bucket %q to be kept", key)
+		}
+	}
+
+	// Flood with far more keys than the cap
+	for i := 0; i < 1000; i++ {
+		limiter.allow(fmt.Sprintf("spoofed-%d", i))
+	}
+
+	if count := countBuckets(limiter); count != 3 {
+		t.Errorf("expected 3 buckets, got %d", count)
+	}
+	if limiter.lru.order.Len() != 3 {
+		t.Errorf("expected 3 tracked buckets, got %d", limiter.lru.order.Len())
+	}
+	for _, key := range []string{"spoofed-997", "spoofed-998", "spoofed-999"} {
+		if _, ok := limiter.buckets.Load(key); !ok {
+			t.Errorf("expected newest bucket %q to be kept", key)
+		}
+	}
+}
+
+func TestRateLimiter_MaxEntries_Concurrent(t *testing.T) {
+	limiter := NewRateLimiter(10, 20, WithMaxEntries(50))
+	defer limiter.Close()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				limiter.allow(fmt.Sprintf("client-%d-%d", g, i%100))
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if count := countBuckets(limiter); count > 50 {
+		t.Errorf("expected at most 50 buckets, got %d", count)
+	}
+}
+
+func TestRateLimiter_MaxEntries_CleanupUntracks(t *testing.T) {
+	limiter := NewRateLimiter(10, 20,
+		WithMaxEntries(10), WithCleanupInterval(10*time.Millisecond), WithIdleExpiry(20*time.Millisecond))
+	defer limiter.Close()
+
+	limiter.allow("idle")
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) && countBuckets(limiter) > 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	limiter.lru.mu.Lock()
+	tracked := limiter.lru.order.Len()
+	limiter.lru.mu.Unlock()
+
+	if countBuckets(limiter) != 0 || tracked != 0 {
+		t.Errorf("expected cleanup to remove the bucket and its LRU entry, got %d buckets, %d tracked", countBuckets(limiter), tracked)
+	}
+}
+
+func countBuckets(limiter *RateLimiter) int {
+	count := 0
+	limiter.buckets.Range(func(_, _ any) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+func TestRateLimiter_FractionalRefill_LowRate(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	defer limiter.Close()
+	key := "test-key"
+
+	if !limiter.allow(key) {
+		t.Fatal("first request should be allowed")
+	}
+
+	// Half a token: still limited, but the partial refill must be carried over
+	time.Sleep(500 * time.Millisecond)
+	if limiter.allow(key) {
+		t.Error("request should be denied before a whole token has refilled")
+	}
+
+	// The second half completes the token ~1s after it was consumed
+	time.Sleep(600 * time.Millisecond)
+	if !limiter.allow(key) {
+		t.Error("request should be allowed once a token has refilled at 1 rps")
+	}
+}
+
+func TestRateLimit_RetryAfterHeader(t *testing.T) {
+	router := nimbus.NewRouter()
+	router.Use(RateLimit(1, 1))
+	router.GET("/test", func(ctx *nimbus.Context) (any, int, error) {
+		return map[string]string{"status": "ok"}, http.StatusOK, nil
+	})
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = "192.168.1.1:1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := send(); w.Code != http.StatusOK || w.Header().Get("Retry-After") != "" {
+		t.Errorf("expected 200 without Retry-After, got %d %q", w.Code, w.Header().Get("Retry-After"))
+	}
+
+	w := send()
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("expected Retry-After 1, got %q", got)
+	}
+}
+
+func TestRateLimiter_RetryAfterJitter(t *testing.T) {
+	limiter := NewRateLimiter(1, 1, WithRetryAfterJitter(5*time.Second))
+	defer limiter.Close()
+
+	limiter.allow("test-key")
+
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 20; i++ {
+		allowed, retryAfter := limiter.take("test-key")
+		if allowed {
+			t.Fatal("request should be denied")
+		}
+		if retryAfter <= 0 || retryAfter >= 6*time.Second {
+			t.Errorf("expected Retry-After in (0, 6s), got %v", retryAfter)
+		}
+		seen[retryAfter.Round(100*time.Millisecond)] = true
+	}
+
+	if len(seen) < 2 {
+		t.Error("expected jitter to spread Retry-After values")
+	}
+}
+
+// TestRateLimiter_NoStarvationUnderPolling guards against rejected requests resetting the
+// refill window: a client polling faster than the refill rate must still get a token.
+func TestRateLimiter_NoStarvationUnderPolling(t *testing.T) {
+	limiter := NewRateLimiter(1, 5)
+	defer limiter.Close()
+	key := "test-key"
+
+	for i := 0; i < 5; i++ {
+		limiter.allow(key)
+	}
+
+	start := time.Now()
+	deadline := start.Add(3 * time.Second)
+	allowed := 0
+	for time.Now().Before(deadline) && allowed < 2 {
+		if limiter.allow(key) {
+			allowed++
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if allowed < 2 {
+		t.Fatalf("expected tokens to refill while polling every 10ms, got %d in %v", allowed, time.Since(start))
+	}
+
+	// Two tokens at 1 rps take ~2s; polling must not slow that down
+	if elapsed := time.Since(start); elapsed > 2500*time.Millisecond {
+		t.Errorf("expected 2 tokens after ~2s, took %v", elapsed)
+	}
+}
+
+func TestTieredRateLimit_SustainedTierTrips(t *testing.T) {
+	router := nimbus.NewRouter()
+	defer router.Shutdown()
+
+	router.Use(TieredRateLimitWithRouter(router,
+		RateLimitTier{Name: "burst", Limit: 10, Window: time.Second},
+		RateLimitTier{Name: "hourly", Limit: 5, Window: time.Hour},
+	))
+	router.GET("/test", func(ctx *nimbus.Context) (any, int, error) {
+		return map[string]string{"status": "ok"}, http.StatusOK, nil
+	})
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = "192.168.1.1:1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < 5; i++ {
+		if w := send(); w.Code != http.StatusOK {
+			t.Fatalf("request %d: expected status 200, got %d", i+1, w.Code)
+		}
+	}
+
+	// Well within the burst tier, but over the hourly tier
+	w := send()
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429, got %d", w.Code)
+	}
+
+	var response nimbus.ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Error != "rate_limit_exceeded" || !strings.Contains(response.Message, "hourly") {
+		t.Errorf("expected error naming the hourly tier, got %+v", response)
+	}
+
+	// 1 token per 720s at 5/hour
+	if got := w.Header().Get("Retry-After"); got != "720" {
+		t.Errorf("expected Retry-After 720, got %q", got)
+	}
+}
+
+func TestTieredRateLimit_BurstTierTrips(t *testing.T) {
+	router := nimbus.NewRouter()
+	defer router.Shutdown()
+
+	router.Use(TieredRateLimitWithRouter(router,
+		RateLimitTier{Limit: 2, Window: time.Second},
+		RateLimitTier{Name: "hourly", Limit: 100, Window: time.Hour},
+	))
+	router.GET("/test", func(ctx *nimbus.Context) (any, int, error) {
+		return nil, http.StatusOK, nil
+	})
+
+	codes := make([]int, 0, 3)
+	var last *httptest.ResponseRecorder
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = "192.168.1.1:1234"
+		last = httptest.NewRecorder()
+		router.ServeHTTP(last, req)
+		codes = append(codes, last.Code)
+	}
+
+	if codes[2] != http.StatusTooManyRequests {
+		t.Fatalf("expected third request to trip the burst tier, got %v", codes)
+	}
+	if !strings.Contains(last.Body.String(), "2 per 1s limit exceeded") {
+		t.Errorf("expected unnamed tier to be described by its limit, got %s", last.Body.String())
+	}
+}
+
+func TestTieredRateLimit_RejectedRequestsRefunded(t *testing.T) {
+	burst := NewRateLimiter(10, 10)
+	defer burst.Close()
+
+	burst.allow("client")
+	burst.refund("client")
+
+	value, _ := burst.buckets.Load("client")
+	if tokens := value.(*bucket).tokenCount(); tokens != 10 {
+		t.Errorf("expected refund to restore 10 tokens, got %v", tokens)
+	}
+
+	// Refunds never exceed capacity, and unknown keys are ignored
+	burst.refund("client")
+	burst.refund("unknown")
+	if tokens := value.(*bucket).tokenCount(); tokens != 10 {
+		t.Errorf("expected tokens capped at 10, got %v", tokens)
+	}
+}
@@ -9,42 +9,97 @@ import (
 	"github.com/DylanHalstead/nimbus"
 )
 
-// Recovery is a middleware that recovers from panics
-func Recovery() nimbus.Middleware {
-	return func(next nimbus.Handler) nimbus.Handler {
-		return func(ctx *nimbus.Context) (data any, statusCode int, err error) {
-			defer func() {
-				if r := recover(); r != nil {
-					// Log the error and stack trace
-					log.Printf("PANIC: %v\n%s", r, debug.Stack())
+// RecoveryConfig defines configuration for the Recovery middleware
+type RecoveryConfig struct {
+	// PanicMapper converts known panic values into a regular error response.
+	// Return handled=false to fall back to the default 500 response.
+	// Mapped panics are not logged, since they represent expected conditions.
+	PanicMapper func(recovered any) (status int, code, message string, handled bool)
 
-					// Return error response
-					data = nil
-					statusCode = http.StatusInternalServerError
-					err = nimbus.NewAPIError("internal_server_error", "An unexpected error occurred")
-				}
-			}()
+	// Formatter builds the error code and message of the 500 response for panics
+	// the PanicMapper doesn't handle, so apps decide what each panic type exposes.
+	// Defaults to a generic message (Recovery) or the %v of the panic value (DetailedRecovery).
+	Formatter func(recovered any) (code, message string)
+}
 
-			// Call next handler
-			return next(ctx)
-		}
+// DefaultRecoveryConfig returns a default Recovery configuration
+func DefaultRecoveryConfig() RecoveryConfig {
+	return RecoveryConfig{}
+}
+
+// Recovery is a middleware that recovers from panics
+// Unmapped panics are logged with a stack trace and become a 500 response built by the Formatter
+//
+// Example mapping an ORM's not-found panic to 404:
+//
+//	router.Use(middleware.Recovery(middleware.RecoveryConfig{
+//	    PanicMapper: func(recovered any) (int, string, string, bool) {
+//	        if err, ok := recovered.(error); ok && errors.Is(err, orm.ErrNotFound) {
+//	            return http.StatusNotFound, "not_found", "Resource not found", true
+//	        }
+//	        return 0, "", "", false
+//	    },
+//	}))
+func Recovery(configs ...RecoveryConfig) nimbus.Middleware {
+	config := DefaultRecoveryConfig()
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+	if config.Formatter == nil {
+		config.Formatter = genericPanicFormatter
 	}
+
+	return recovery(config)
 }
 
 // DetailedRecovery returns a recovery middleware that includes error details in the response
-func DetailedRecovery() nimbus.Middleware {
+// Pass a RecoveryConfig with a Formatter to control which details each panic type exposes
+func DetailedRecovery(configs ...RecoveryConfig) nimbus.Middleware {
+	config := DefaultRecoveryConfig()
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+	if config.Formatter == nil {
+		config.Formatter = detailedPanicFormatter
+	}
+
+	return recovery(config)
+}
+
+// genericPanicFormatter hides the panic value behind a generic message
+func genericPanicFormatter(recovered any) (string, string) {
+	return "internal_server_error", "An unexpected error occurred"
+}
+
+// detailedPanicFormatter exposes the panic value in the message
+func detailedPanicFormatter(recovered any) (string, string) {
+	return "internal_server_error", fmt.Sprintf("Panic recovered: %v", recovered)
+}
+
+// recovery builds the middleware shared by Recovery and DetailedRecovery
+func recovery(config RecoveryConfig) nimbus.Middleware {
 	return func(next nimbus.Handler) nimbus.Handler {
 		return func(ctx *nimbus.Context) (data any, statusCode int, err error) {
 			defer func() {
 				if r := recover(); r != nil {
+					// Known panics become the mapped response
+					if config.PanicMapper != nil {
+						if status, code, message, handled := config.PanicMapper(r); handled {
+							data = nil
+							statusCode = status
+							err = nimbus.NewAPIError(code, message)
+							return
+						}
+					}
+
 					// Log the error and stack trace
 					log.Printf("PANIC: %v\n%s", r, debug.Stack())
 
-					// Return detailed error response
-					message := fmt.Sprintf("Panic recovered: %v", r)
+					// Return error response
+					code, message := config.Formatter(r)
 					data = nil
 					statusCode = http.StatusInternalServerError
-					err = nimbus.NewAPIError("internal_server_error", message)
+					err = nimbus.NewAPIError(code, message)
 				}
 			}()
 
@@ -364,3 +364,153 @@ func TestRecovery_ChainWithOtherMiddleware(t *testing.T) {
 		t.Error("expected error after panic, got nil")
 	}
 }
+
+// errRecordNotFound mimics a sentinel panic value thrown by a data access library
+type errRecordNotFound struct {
+	table string
+}
+
+func (e errRecordNotFound) Error() string {
+	return e.table + " record not found"
+}
+
+func TestRecovery_PanicMapper(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(nil)
+
+	middleware := Recovery(RecoveryConfig{
+		PanicMapper: func(recovered any) (int, string, string, bool) {
+			if err, ok := recovered.(errRecordNotFound); ok {
+				return http.StatusNotFound, "not_found", err.Error(), true
+			}
+			return 0, "", "", false
+		},
+	})
+
+	tests := []struct {
+		name           string
+		panicValue     any
+		expectedStatus int
+		expectedCode   string
+		expectLog      bool
+	}{
+		{"mapped type", errRecordNotFound{table: "users"}, http.StatusNotFound, "not_found", false},
+		{"unmapped error", nimbus.NewAPIError("custom_error", "boom"), http.StatusInternalServerError, "internal_server_error", true},
+		{"unmapped string", "something went wrong!", http.StatusInternalServerError, "internal_server_error", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+
+			handler := middleware(func(ctx *nimbus.Context) (any, int, error) {
+				panic(tt.panicValue)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			w := httptest.NewRecorder()
+			ctx := nimbus.NewContext(w, req)
+
+			data, statusCode, err := handler(ctx)
+
+			if data != nil {
+				t.Errorf("expected nil data after panic, got %v", data)
+			}
+
+			if statusCode != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, statusCode)
+			}
+
+			apiErr, ok := err.(*nimbus.APIError)
+			if !ok {
+				t.Fatalf("expected *nimbus.APIError, got %T", err)
+			}
+			if apiErr.Code != tt.expectedCode {
+				t.Errorf("expected code %q, got %q", tt.expectedCode, apiErr.Code)
+			}
+
+			if logged := strings.Contains(buf.String(), "PANIC"); logged != tt.expectLog {
+				t.Errorf("expected logged=%v, got %v", tt.expectLog, logged)
+			}
+		})
+	}
+}
+
+type paymentState struct {
+	AccountID string
+	CardToken string
+}
+
+func TestRecovery_Formatter(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(nil)
+
+	formatter := func(recovered any) (string, string) {
+		if state, ok := recovered.(paymentState); ok {
+			return "payment_failed", "Payment for account " + state.AccountID + " failed"
+		}
+		return "internal_server_error", "An unexpected error occurred"
+	}
+
+	tests := []struct {
+		name       string
+		middleware nimbus.Middleware
+	}{
+		{"Recovery", Recovery(RecoveryConfig{Formatter: formatter})},
+		{"DetailedRecovery", DetailedRecovery(RecoveryConfig{Formatter: formatter})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := tt.middleware(func(ctx *nimbus.Context) (any, int, error) {
+				panic(paymentState{AccountID: "acct_1", CardToken: "tok_secret"})
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			ctx := nimbus.NewContext(httptest.NewRecorder(), req)
+
+			_, statusCode, err := handler(ctx)
+
+			if statusCode != http.StatusInternalServerError {
+				t.Errorf("expected status %d, got %d", http.StatusInternalServerError, statusCode)
+			}
+
+			apiErr, ok := err.(*nimbus.APIError)
+			if !ok {
+				t.Fatalf("expected *nimbus.APIError, got %T", err)
+			}
+			if apiErr.Code != "payment_failed" {
+				t.Errorf("expected code %q, got %q", "payment_failed", apiErr.Code)
+			}
+			if apiErr.Message != "Payment for account acct_1 failed" {
+				t.Errorf("expected formatter message, got %q", apiErr.Message)
+			}
+			if strings.Contains(apiErr.Message, "tok_secret") {
+				t.Error("expected panic internals to stay out of the response")
+			}
+		})
+	}
+}
+
+func TestDetailedRecovery_DefaultFormatterKeepsDetails(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(nil)
+
+	handler := DetailedRecovery()(func(ctx *nimbus.Context) (any, int, error) {
+		panic(paymentState{AccountID: "acct_1"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	_, _, err := handler(nimbus.NewContext(httptest.NewRecorder(), req))
+
+	apiErr, ok := err.(*nimbus.APIError)
+	if !ok {
+		t.Fatalf("expected *nimbus.APIError, got %T", err)
+	}
+	if !strings.Contains(apiErr.Message, "acct_1") {
+		t.Errorf("expected %%v of the panic value in message, got %q", apiErr.Message)
+	}
+}
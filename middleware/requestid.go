@@ -14,7 +14,7 @@ const (
 	// RequestIDHeader is the header name for request ID
 	RequestIDHeader = "X-Request-ID"
 	// RequestIDKey is the context key for storing request ID
-	RequestIDKey = "request_id"
+	RequestIDKey = nimbus.ContextKeyRequestID
 )
 
 var (
@@ -29,8 +29,8 @@ type RequestIDConfig struct {
 	HeaderName string
 	// Generator is a function to generate new request IDs
 	Generator func() string
-	// ContextKey is the key used to store the request ID in context
-	ContextKey string
+	// ContextKey is the key used to store the request ID in context (default: nimbus.ContextKeyRequestID)
+	ContextKey any
 }
 
 // DefaultRequestIDConfig returns a default RequestID configuration
@@ -58,7 +58,7 @@ func RequestID(configs ...RequestIDConfig) nimbus.Middleware {
 	if config.Generator == nil {
 		config.Generator = generateRequestID
 	}
-	if config.ContextKey == "" {
+	if config.ContextKey == nil {
 		config.ContextKey = RequestIDKey
 	}
 
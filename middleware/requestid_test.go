@@ -1,8 +1,14 @@
 package middleware
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"regexp"
+	"strings"
 	"testing"
+
+	"github.com/DylanHalstead/nimbus"
 )
 
 func TestGenerateRequestID_UUIDFormat(t *testing.T) {
@@ -89,3 +95,52 @@ func TestGenerateULID_Uniqueness(t *testing.T) {
 		t.Errorf("Expected %d unique ULIDs, got %d", iterations, len(seen))
 	}
 }
+
+func TestRequestID_EchoedInBody(t *testing.T) {
+	newRouter := func(opts ...nimbus.RouterOption) *nimbus.Router {
+		router := nimbus.NewRouter(opts...)
+		router.Use(RequestID())
+		router.GET("/ok", func(ctx *nimbus.Context) (any, int, error) {
+			return map[string]string{"status": "ok"}, http.StatusOK, nil
+		})
+		router.GET("/fail", func(ctx *nimbus.Context) (any, int, error) {
+			return nil, http.StatusConflict, nimbus.NewAPIError("conflict", "Already exists")
+		})
+		return router
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		router := newRouter(nimbus.WithRequestIDInBody())
+
+		for _, path := range []string{"/ok", "/fail", "/missing"} {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set(RequestIDHeader, "req-123")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			var body struct {
+				Meta *nimbus.ResponseMeta `json:"meta"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Meta == nil || body.Meta.RequestID != "req-123" {
+				t.Errorf("expected meta.request_id req-123 for %s, got %+v", path, body.Meta)
+			}
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		router := newRouter()
+
+		for _, path := range []string{"/ok", "/fail"} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+			if strings.Contains(w.Body.String(), "meta") {
+				t.Errorf("expected no meta for %s, got %s", path, w.Body.String())
+			}
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+// WithResource is a middleware that ties a per-request resource, such as a database
+// transaction, to the handler's outcome. open runs before the handler and returns the
+// resource, stored in the context under key, and a cleanup function. Cleanup runs after
+// the handler with the error it returned, so it can commit or roll back. A handler that
+// returns no error but responds with a 4xx or 5xx status, itself or by writing the response
+// as ctx.SendValidationError does, passes cleanup an error naming the status; cleanup gets
+// nil only for successful responses.
+// A panic in the handler reaches cleanup as an error before it propagates, so place
+// Recovery outside WithResource to turn it into a 500 response.
+//
+// If open fails, the handler is skipped and the error becomes a 500 response.
+//
+// Example:
+//
+//	router.Use(middleware.Recovery())
+//	router.POST("/orders", createOrder, nimbus.WithMiddleware(middleware.WithResource(
+//	    func(ctx *nimbus.Context) (any, func(error), error) {
+//	        tx, err := db.BeginTx(ctx.Request.Context(), nil)
+//	        if err != nil {
+//	            return nil, nil, err
+//	        }
+//	        return tx, func(err error) {
+//	            if err != nil {
+//	                tx.Rollback()
+//	                return
+//	            }
+//	            tx.Commit()
+//	        }, nil
+//	    },
+//	    "tx",
+//	)))
+//
+//	func createOrder(ctx *nimbus.Context) (any, int, error) {
+//	    tx := ctx.MustGet("tx").(*sql.Tx)
+//	    ...
+//	}
+func WithResource(open func(ctx *nimbus.Context) (any, func(err error), error), key string) nimbus.Middleware {
+	if open == nil {
+		panic("WithResource: open is required")
+	}
+
+	return func(next nimbus.Handler) nimbus.Handler {
+		return func(ctx *nimbus.Context) (data any, statusCode int, err error) {
+			resource, cleanup, err := open(ctx)
+			if err != nil {
+				return nil, http.StatusInternalServerError, err
+			}
+			ctx.Set(key, resource)
+
+			if cleanup != nil {
+				defer func() {
+					if r := recover(); r != nil {
+						cleanup(fmt.Errorf("panic: %v", r))
+						panic(r)
+					}
+					if err == nil {
+						cleanup(errorStatus(ctx, statusCode))
+						return
+					}
+					cleanup(err)
+				}()
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// errorStatus returns an error if the response is a failure (4xx or 5xx) although the
+// handler returned no error. A status of 0 means the handler wrote the response itself,
+// so the status recorded by ctx.JSON, ctx.Data and the like is checked instead.
+func errorStatus(ctx *nimbus.Context, statusCode int) error {
+	if statusCode == 0 {
+		statusCode = ctx.GetInt(nimbus.StatusCodeKey)
+	}
+	if statusCode >= http.StatusBadRequest {
+		return fmt.Errorf("handler responded with status %d", statusCode)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+// fakeTx records how a WithResource cleanup finished it
+type fakeTx struct {
+	committed  bool
+	rolledBack bool
+	cause      error
+}
+
+func openFakeTx(tx *fakeTx) func(*nimbus.Context) (any, func(error), error) {
+	return func(ctx *nimbus.Context) (any, func(error), error) {
+		return tx, func(err error) {
+			if err != nil {
+				tx.rolledBack = true
+				tx.cause = err
+				return
+			}
+			tx.committed = true
+		}, nil
+	}
+}
+
+func TestWithResource(t *testing.T) {
+	tests := []struct {
+		name           string
+		handler        nimbus.Handler
+		expectedCommit bool
+		expectedCause  string
+		expectedStatus int
+	}{
+		{
+			name: "commit on success",
+			handler: func(ctx *nimbus.Context) (any, int, error) {
+				return map[string]string{"status": "created"}, http.StatusCreated, nil
+			},
+			expectedCommit: true,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "rollback on returned error",
+			handler: func(ctx *nimbus.Context) (any, int, error) {
+				return nil, http.StatusConflict, nimbus.NewAPIError("conflict", "order already exists")
+			},
+			expectedCause:  "order already exists",
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "rollback on error status",
+			handler: func(ctx *nimbus.Context) (any, int, error) {
+				return map[string]string{"status": "out of stock"}, http.StatusConflict, nil
+			},
+			expectedCause:  "status 409",
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "rollback on written validation error",
+			handler: func(ctx *nimbus.Context) (any, int, error) {
+				return ctx.SendValidationError(nimbus.ValidationErrors{
+					{Field: "quantity", Tag: "min", Message: "quantity must be at least 1"},
+				})
+			},
+			expectedCause:  "status 400",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "rollback on panic",
+			handler: func(ctx *nimbus.Context) (any, int, error) {
+				panic("inventory service down")
+			},
+			expectedCause:  "panic: inventory service down",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log.SetOutput(&buf)
+			defer log.SetOutput(nil)
+
+			tx := &fakeTx{}
+			var stored any
+
+			router := nimbus.NewRouter()
+			router.Use(Recovery())
+			router.POST("/orders", func(ctx *nimbus.Context) (any, int, error) {
+				stored, _ = ctx.Get("tx")
+				return tt.handler(ctx)
+			}, nimbus.WithMiddleware(WithResource(openFakeTx(tx), "tx")))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/orders", nil))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if stored != tx {
+				t.Errorf("expected the resource stored under tx, got %v", stored)
+			}
+			if tx.committed != tt.expectedCommit || tx.rolledBack == tt.expectedCommit {
+				t.Errorf("expected committed=%v, got committed=%v rolledBack=%v", tt.expectedCommit, tx.committed, tx.rolledBack)
+			}
+			if tt.expectedCause != "" && (tx.cause == nil || !strings.Contains(tx.cause.Error(), tt.expectedCause)) {
+				t.Errorf("expected rollback cause %q, got %v", tt.expectedCause, tx.cause)
+			}
+		})
+	}
+}
+
+func TestWithResource_OpenError(t *testing.T) {
+	called := false
+
+	router := nimbus.NewRouter()
+	router.POST("/orders", func(ctx *nimbus.Context) (any, int, error) {
+		called = true
+		return nil, http.StatusOK, nil
+	}, nimbus.WithMiddleware(WithResource(func(ctx *nimbus.Context) (any, func(error), error) {
+		return nil, nil, errors.New("connection refused")
+	}, "tx")))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/orders", nil))
+
+	if called {
+		t.Error("expected the handler to be skipped when open fails")
+	}
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", w.Code)
+	}
+}
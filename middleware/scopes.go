@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+// EnforceRouteScopes is a middleware that enforces the scopes each route declares with
+// nimbus.WithScopes, so authorization rules live next to route definitions instead of in
+// per-route middleware. extractor returns the scopes granted to the current principal
+// (typically read from the user stored by Auth). A route passes only if the principal has
+// every scope it declares; routes without scopes are not checked.
+//
+// Example:
+//
+//	router.Use(middleware.Auth(validateToken))
+//	router.Use(middleware.EnforceRouteScopes(func(ctx *nimbus.Context) []string {
+//	    return ctx.MustGet(nimbus.ContextKeyUser).(*User).Scopes
+//	}))
+//
+//	router.GET("/users", listUsers, nimbus.WithScopes("users:read"))
+//	router.DELETE("/users/:id", deleteUser, nimbus.WithScopes("users:write"))
+func EnforceRouteScopes(extractor func(*nimbus.Context) []string) nimbus.Middleware {
+	return func(next nimbus.Handler) nimbus.Handler {
+		return func(ctx *nimbus.Context) (any, int, error) {
+			route := ctx.Route()
+			if route == nil {
+				return next(ctx)
+			}
+
+			required := route.Scopes()
+			if len(required) == 0 {
+				return next(ctx)
+			}
+
+			granted := extractor(ctx)
+			for _, scope := range required {
+				if !slices.Contains(granted, scope) {
+					return nil, http.StatusForbidden,
+						nimbus.NewAPIError("forbidden", fmt.Sprintf("Missing required scope: %s", scope))
+				}
+			}
+
+			return next(ctx)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+func TestEnforceRouteScopes(t *testing.T) {
+	router := nimbus.NewRouter()
+
+	// Principal scopes come from a header to keep the test focused on enforcement
+	router.Use(EnforceRouteScopes(func(ctx *nimbus.Context) []string {
+		if header := ctx.GetHeader("X-Scopes"); header != "" {
+			return strings.Split(header, ",")
+		}
+		return nil
+	}))
+
+	ok := func(ctx *nimbus.Context) (any, int, error) {
+		return map[string]string{"status": "ok"}, http.StatusOK, nil
+	}
+	router.GET("/public", ok)
+	router.GET("/users", ok, nimbus.WithScopes("users:read"))
+	router.DELETE("/users/:id", ok, nimbus.WithScopes("users:read", "users:write"))
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		scopes         string
+		expectedStatus int
+	}{
+		{"no scopes declared", http.MethodGet, "/public", "", http.StatusOK},
+		{"read granted", http.MethodGet, "/users", "users:read", http.StatusOK},
+		{"read missing", http.MethodGet, "/users", "orders:read", http.StatusForbidden},
+		{"no principal scopes", http.MethodGet, "/users", "", http.StatusForbidden},
+		{"all declared scopes granted", http.MethodDelete, "/users/1", "users:read,users:write", http.StatusOK},
+		{"one declared scope missing", http.MethodDelete, "/users/1", "users:read", http.StatusForbidden},
+		{"unmatched route", http.MethodGet, "/missing", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.scopes != "" {
+				req.Header.Set("X-Scopes", tt.scopes)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus == http.StatusForbidden && !strings.Contains(w.Body.String(), "forbidden") {
+				t.Errorf("expected forbidden error, got %s", w.Body.String())
+			}
+		})
+	}
+}
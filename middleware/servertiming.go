@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+// ServerTimingHeader is the response header carrying timing metrics
+const ServerTimingHeader = "Server-Timing"
+
+// ServerTiming is a middleware that emits a Server-Timing header (shown in browser devtools)
+// with the total application time as "app", plus any metrics handlers record with
+// ctx.AddServerTiming:
+//
+//	Server-Timing: db;dur=12.5, app;dur=30.02
+//
+// The header is added just before the response headers are written, so it covers both
+// returned results and responses written directly (ctx.JSON, ctx.HTML, ...).
+//
+// Example:
+//
+//	router.Use(middleware.ServerTiming())
+//
+//	func listUsers(ctx *nimbus.Context) (any, int, error) {
+//	    start := time.Now()
+//	    users := db.ListUsers()
+//	    ctx.AddServerTiming("db", time.Since(start))
+//	    return users, http.StatusOK, nil
+//	}
+func ServerTiming() nimbus.Middleware {
+	return func(next nimbus.Handler) nimbus.Handler {
+		return func(ctx *nimbus.Context) (any, int, error) {
+			// Stays installed after next returns: the router renders returned results afterwards
+			ctx.Writer = &serverTimingWriter{
+				ResponseWriter: ctx.Writer,
+				ctx:            ctx,
+				start:          time.Now(),
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// serverTimingWriter sets the Server-Timing header on the first WriteHeader or Write
+type serverTimingWriter struct {
+	http.ResponseWriter
+	ctx         *nimbus.Context
+	start       time.Time
+	wroteHeader bool
+}
+
+func (w *serverTimingWriter) WriteHeader(statusCode int) {
+	w.setHeader()
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *serverTimingWriter) Write(b []byte) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sets the header, since flushing sends it, and flushes the underlying writer for
+// streaming handlers that assert http.Flusher rather than using http.ResponseController
+func (w *serverTimingWriter) Flush() {
+	w.setHeader()
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *serverTimingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *serverTimingWriter) setHeader() {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	var b strings.Builder
+	for _, metric := range w.ctx.ServerTimings() {
+		writeServerTimingMetric(&b, metric.Name, metric.Duration)
+		b.WriteString(", ")
+	}
+	writeServerTimingMetric(&b, "app", time.Since(w.start))
+
+	w.Header().Set(ServerTimingHeader, b.String())
+}
+
+// writeServerTimingMetric formats a metric as name;dur=<milliseconds>
+func writeServerTimingMetric(b *strings.Builder, name string, d time.Duration) {
+	b.WriteString(name)
+	b.WriteString(";dur=")
+	b.WriteString(strconv.FormatFloat(float64(d.Round(time.Microsecond))/float64(time.Millisecond), 'f', -1, 64))
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DylanHalstead/nimbus"
+)
+
+func TestServerTiming(t *testing.T) {
+	router := nimbus.NewRouter()
+	router.Use(ServerTiming())
+
+	router.GET("/plain", func(ctx *nimbus.Context) (any, int, error) {
+		return map[string]string{"status": "ok"}, http.StatusOK, nil
+	})
+	router.GET("/metrics", func(ctx *nimbus.Context) (any, int, error) {
+		ctx.AddServerTiming("db", 12500*time.Microsecond)
+		ctx.AddServerTiming("cache", 2*time.Millisecond)
+		return map[string]string{"status": "ok"}, http.StatusOK, nil
+	})
+	router.GET("/html", func(ctx *nimbus.Context) (any, int, error) {
+		ctx.AddServerTiming("render", time.Millisecond)
+		return ctx.HTML(http.StatusOK, "<h1>Hello</h1>")
+	})
+
+	appMetric := `app;dur=\d+(\.\d+)?`
+
+	tests := []struct {
+		path    string
+		pattern string
+	}{
+		{"/plain", `^` + appMetric + `$`},
+		{"/metrics", `^db;dur=12\.5, cache;dur=2, ` + appMetric + `$`},
+		{"/html", `^render;dur=1, ` + appMetric + `$`},
+		{"/missing", `^` + appMetric + `$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			header := w.Header().Get(ServerTimingHeader)
+			if !regexp.MustCompile(tt.pattern).MatchString(header) {
+				t.Errorf("expected Server-Timing matching %s, got %q", tt.pattern, header)
+			}
+		})
+	}
+}
+
+func TestServerTiming_MetricsNotLeakedAcrossRequests(t *testing.T) {
+	router := nimbus.NewRouter()
+	router.Use(ServerTiming())
+
+	router.GET("/db", func(ctx *nimbus.Context) (any, int, error) {
+		ctx.AddServerTiming("db", time.Millisecond)
+		return map[string]string{"status": "ok"}, http.StatusOK, nil
+	})
+	router.GET("/plain", func(ctx *nimbus.Context) (any, int, error) {
+		return map[string]string{"status": "ok"}, http.StatusOK, nil
+	})
+
+	for i := 0; i < 10; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/db", nil))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/plain", nil))
+		if header := w.Header().Get(ServerTimingHeader); strings.Contains(header, "db") {
+			t.Fatalf("expected pooled context to drop previous metrics, got %q", header)
+		}
+	}
+}
+
+func TestServerTiming_Flush(t *testing.T) {
+	router := nimbus.NewRouter()
+	router.Use(ServerTiming())
+
+	router.GET("/stream", func(ctx *nimbus.Context) (any, int, error) {
+		flusher, ok := ctx.Writer.(http.Flusher)
+		if !ok {
+			t.Fatal("expected the ServerTiming writer to implement http.Flusher")
+		}
+		ctx.Writer.Write([]byte("event: ping\n\n"))
+		flusher.Flush()
+		return nil, 0, nil
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream", nil))
+
+	if !w.Flushed {
+		t.Error("expected Flush to reach the underlying writer")
+	}
+	if w.Header().Get(ServerTimingHeader) == "" {
+		t.Error("expected the Server-Timing header on a flushed response")
+	}
+}
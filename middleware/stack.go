@@ -0,0 +1,50 @@
+package middleware
+
+import "github.com/DylanHalstead/nimbus"
+
+// StackConfig configures the middleware in RecommendedStack
+type StackConfig struct {
+	Recovery  RecoveryConfig
+	RequestID RequestIDConfig
+	Logger    LoggerConfig
+}
+
+// DefaultStackConfig returns the defaults of each middleware, with production logging
+func DefaultStackConfig() StackConfig {
+	return StackConfig{
+		Recovery:  DefaultRecoveryConfig(),
+		RequestID: DefaultRequestIDConfig(),
+		Logger:    ProductionLoggerConfig(),
+	}
+}
+
+// RecommendedStack returns the baseline middleware every router should run, in the order
+// they must be registered: Recovery outermost so it catches panics from everything after it,
+// then RequestID so the Logger can include the ID. Append your own middleware to customize it.
+//
+// Example:
+//
+//	config := middleware.DefaultStackConfig()
+//	config.Logger = middleware.DevelopmentLoggerConfig()
+//	router.Use(middleware.RecommendedStack(config)...)
+func RecommendedStack(config StackConfig) []nimbus.Middleware {
+	return []nimbus.Middleware{
+		Recovery(config.Recovery),
+		RequestID(config.RequestID),
+		Logger(config.Logger),
+	}
+}
+
+// NewRouterWithDefaults returns a router with RecommendedStack(DefaultStackConfig())
+// already registered. It lives in this package rather than nimbus because nimbus
+// cannot import its middleware.
+//
+// Example:
+//
+//	router := middleware.NewRouterWithDefaults()
+//	router.GET("/users/:id", getUser)
+func NewRouterWithDefaults(opts ...nimbus.RouterOption) *nimbus.Router {
+	router := nimbus.NewRouter(opts...)
+	router.Use(RecommendedStack(DefaultStackConfig())...)
+	return router
+}
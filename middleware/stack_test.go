@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DylanHalstead/nimbus"
+	"github.com/rs/zerolog"
+)
+
+func TestNewRouterWithDefaults(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(nil)
+
+	router := NewRouterWithDefaults()
+	router.GET("/panic", func(ctx *nimbus.Context) (any, int, error) {
+		panic("boom")
+	})
+	router.GET("/ok", func(ctx *nimbus.Context) (any, int, error) {
+		return map[string]string{"request_id": ctx.GetString(nimbus.ContextKeyRequestID)}, http.StatusOK, nil
+	})
+
+	// Panics are recovered without registering Recovery
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", w.Code)
+	}
+	if w.Header().Get(RequestIDHeader) == "" {
+		t.Error("expected request ID header on the recovered response")
+	}
+
+	// Request IDs are generated and visible to handlers
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ok", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var body struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if id := w.Header().Get(RequestIDHeader); id == "" || body.Data["request_id"] != id {
+		t.Errorf("expected handler to see request ID %q, got %q", id, body.Data["request_id"])
+	}
+}
+
+func TestRecommendedStack_Order(t *testing.T) {
+	var logs bytes.Buffer
+	logger := zerolog.New(&logs)
+
+	config := DefaultStackConfig()
+	config.Logger = LoggerConfig{Logger: &logger}
+	stack := RecommendedStack(config)
+
+	if len(stack) != 3 {
+		t.Fatalf("expected 3 middleware, got %d", len(stack))
+	}
+
+	router := nimbus.NewRouter()
+	router.Use(stack...)
+	router.GET("/users", func(ctx *nimbus.Context) (any, int, error) {
+		return []string{}, http.StatusOK, nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set(RequestIDHeader, "req-123")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// RequestID runs before Logger, so the log line carries the ID
+	if !bytes.Contains(logs.Bytes(), []byte(`"request_id":"req-123"`)) {
+		t.Errorf("expected request ID in log line, got %s", logs.String())
+	}
+}
@@ -18,35 +18,11 @@ import (
 func Timeout(timeout time.Duration) nimbus.Middleware {
 	return func(next nimbus.Handler) nimbus.Handler {
 		return func(ctx *nimbus.Context) (any, int, error) {
-			// Create timeout context from request's context
-			timeoutCtx, cancel := context.WithTimeout(ctx.Request.Context(), timeout)
-			defer cancel()
-
-			// Replace request's context with timeout version
-			ctx.Request = ctx.Request.WithContext(timeoutCtx)
-
-			// Channel to receive handler result
-			type result struct {
-				data   any
-				status int
-				err    error
-			}
-			resultChan := make(chan result, 1)
-
-			// Run handler in goroutine
-			go func() {
-				data, status, err := next(ctx)
-				resultChan <- result{data, status, err}
-			}()
-
-			// Wait for either completion or timeout
-			select {
-			case res := <-resultChan:
-				return res.data, res.status, res.err
-			case <-timeoutCtx.Done():
-				// Timeout occurred
+			data, status, err, timedOut := runWithTimeout(ctx, next, timeout)
+			if timedOut {
 				return nil, 504, nimbus.NewAPIError("timeout", "request timeout exceeded")
 			}
+			return data, status, err
 		}
 	}
 }
@@ -71,31 +47,44 @@ func TimeoutWithSkip(timeout time.Duration, skipPaths ...string) nimbus.Middlewa
 				return next(ctx)
 			}
 
-			// Apply timeout to request's context
-			timeoutCtx, cancel := context.WithTimeout(ctx.Request.Context(), timeout)
-			defer cancel()
-
-			ctx.Request = ctx.Request.WithContext(timeoutCtx)
-
-			type result struct {
-				data   any
-				status int
-				err    error
-			}
-			resultChan := make(chan result, 1)
-
-			go func() {
-				data, status, err := next(ctx)
-				resultChan <- result{data, status, err}
-			}()
-
-			select {
-			case res := <-resultChan:
-				return res.data, res.status, res.err
-			case <-timeoutCtx.Done():
+			data, status, err, timedOut := runWithTimeout(ctx, next, timeout)
+			if timedOut {
 				return nil, 504, nimbus.NewAPIError("timeout", "request timeout exceeded")
 			}
+			return data, status, err
 		}
 	}
 }
 
+// runWithTimeout runs next with a deadline on the request's context, returning its result,
+// or timedOut once the deadline passes without waiting for next to finish
+func runWithTimeout(ctx *nimbus.Context, next nimbus.Handler, timeout time.Duration) (data any, status int, err error, timedOut bool) {
+	// Create timeout context from request's context
+	timeoutCtx, cancel := context.WithTimeout(ctx.Request.Context(), timeout)
+	defer cancel()
+
+	// Replace request's context with timeout version
+	ctx.Request = ctx.Request.WithContext(timeoutCtx)
+
+	// Channel to receive handler result
+	type result struct {
+		data   any
+		status int
+		err    error
+	}
+	resultChan := make(chan result, 1)
+
+	// Run handler in goroutine; ctx.Go keeps the context out of the pool until it returns
+	ctx.Go(func() {
+		data, status, err := next(ctx)
+		resultChan <- result{data, status, err}
+	})
+
+	// Wait for either completion or timeout
+	select {
+	case res := <-resultChan:
+		return res.data, res.status, res.err, false
+	case <-timeoutCtx.Done():
+		return nil, 0, nil, true
+	}
+}
@@ -0,0 +1,79 @@
+package nimbus
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddleware_ShortCircuitWithData(t *testing.T) {
+	router := NewRouter()
+
+	handlerCalled := false
+	cache := func(next Handler) Handler {
+		return func(ctx *Context) (any, int, error) {
+			return map[string]string{"source": "cache"}, http.StatusOK, nil
+		}
+	}
+
+	router.AddRoute(http.MethodGet, "/items", func(ctx *Context) (any, int, error) {
+		handlerCalled = true
+		return map[string]string{"source": "handler"}, http.StatusOK, nil
+	}, cache)
+
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if handlerCalled {
+		t.Error("Handler should not run when middleware short-circuits")
+	}
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+
+	var response SuccessResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	data, ok := response.Data.(map[string]any)
+	if !response.Success || !ok || data["source"] != "cache" {
+		t.Errorf("Expected success envelope with cached data, got %+v", response)
+	}
+}
+
+// TestMiddleware_ShortCircuitMatchesHandler verifies a middleware short-circuit renders
+// byte-for-byte the same response as a handler returning the same values.
+func TestMiddleware_ShortCircuitMatchesHandler(t *testing.T) {
+	result := func(ctx *Context) (any, int, error) {
+		return map[string]any{"id": 1, "name": "widget"}, http.StatusCreated, nil
+	}
+
+	router := NewRouter()
+	router.AddRoute(http.MethodPost, "/handler", result)
+	router.AddRoute(http.MethodPost, "/middleware", func(ctx *Context) (any, int, error) {
+		t.Error("Handler should not run when middleware short-circuits")
+		return nil, http.StatusInternalServerError, nil
+	}, func(next Handler) Handler {
+		return result
+	})
+
+	handlerW := httptest.NewRecorder()
+	router.ServeHTTP(handlerW, httptest.NewRequest(http.MethodPost, "/handler", nil))
+
+	middlewareW := httptest.NewRecorder()
+	router.ServeHTTP(middlewareW, httptest.NewRequest(http.MethodPost, "/middleware", nil))
+
+	if handlerW.Code != middlewareW.Code {
+		t.Errorf("Status mismatch: handler %d, middleware %d", handlerW.Code, middlewareW.Code)
+	}
+	if handlerW.Body.String() != middlewareW.Body.String() {
+		t.Errorf("Body mismatch: handler %q, middleware %q", handlerW.Body.String(), middlewareW.Body.String())
+	}
+	if middlewareW.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Expected JSON content type, got %q", middlewareW.Header().Get("Content-Type"))
+	}
+}
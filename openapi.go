@@ -3,6 +3,7 @@ package nimbus
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net/http"
 	"os"
 	"reflect"
@@ -205,7 +206,7 @@ func (r *Router) generatePathsFromRoutes(spec *OpenAPISpec) {
 	for method, pathMap := range allRoutes {
 		for _, route := range pathMap {
 			// Convert path parameters from :param to {param}
-			openAPIPath := convertPathParams(route.pattern)
+			openAPIPath := convertPathParams(r.withBasePath(route.pattern))
 
 			// Get or create path item
 			pathItem, exists := spec.Paths[openAPIPath]
@@ -274,32 +275,47 @@ func (r *Router) createOperation(route *Route, metadata *RouteMetadata, spec *Op
 		operation.Parameters = append(operation.Parameters, queryParams...)
 	}
 
+	// Route-level examples (WithRequestExample) take precedence over metadata examples
+	requestExample := metadata.RequestBody
+	if route.requestExample != nil {
+		requestExample = route.requestExample
+	}
+
 	// Add request body for POST/PUT/PATCH
-	if (route.method == "POST" || route.method == "PUT" || route.method == "PATCH") && metadata.RequestSchema != nil {
-		schemaName := getSchemaName(metadata.RequestSchema)
-		schemaRef := fmt.Sprintf("#/components/schemas/%s", schemaName)
+	if (route.method == "POST" || route.method == "PUT" || route.method == "PATCH") && (metadata.RequestSchema != nil || requestExample != nil) {
+		mediaType := OpenAPIMediaType{Example: marshalExample(requestExample)}
 
-		// Add schema to components if not already present
-		if _, exists := spec.Components.Schemas[schemaName]; !exists {
-			spec.Components.Schemas[schemaName] = schemaToOpenAPISchema(metadata.RequestSchema)
+		if metadata.RequestSchema != nil {
+			schemaName := getSchemaName(metadata.RequestSchema)
+			schemaRef := fmt.Sprintf("#/components/schemas/%s", schemaName)
+
+			// Add schema to components if not already present
+			if _, exists := spec.Components.Schemas[schemaName]; !exists {
+				spec.Components.Schemas[schemaName] = schemaToOpenAPISchema(metadata.RequestSchema)
+			}
+			mediaType.Schema = &OpenAPISchema{Ref: schemaRef}
 		}
 
 		operation.RequestBody = &OpenAPIRequestBody{
 			Required: true,
 			Content: map[string]OpenAPIMediaType{
-				"application/json": {
-					Schema: &OpenAPISchema{
-						Ref: schemaRef,
-					},
-					Example: metadata.RequestBody,
-				},
+				"application/json": mediaType,
 			},
 		}
 	}
 
+	responseExamples := metadata.ResponseSchema
+	if len(route.responseExamples) > 0 {
+		responseExamples = maps.Clone(responseExamples)
+		if responseExamples == nil {
+			responseExamples = make(map[int]any, len(route.responseExamples))
+		}
+		maps.Copy(responseExamples, route.responseExamples)
+	}
+
 	// Add responses
-	if len(metadata.ResponseSchema) > 0 {
-		for statusCode, example := range metadata.ResponseSchema {
+	if len(responseExamples) > 0 {
+		for statusCode, example := range responseExamples {
 			operation.Responses[fmt.Sprintf("%d", statusCode)] = OpenAPIResponse{
 				Description: getStatusDescription(statusCode),
 				Content: map[string]OpenAPIMediaType{
@@ -307,7 +323,7 @@ func (r *Router) createOperation(route *Route, metadata *RouteMetadata, spec *Op
 						Schema: &OpenAPISchema{
 							Type: "object",
 						},
-						Example: example,
+						Example: marshalExample(example),
 					},
 				},
 			}
@@ -345,6 +361,20 @@ func (r *Router) createOperation(route *Route, metadata *RouteMetadata, spec *Op
 	return operation
 }
 
+// marshalExample encodes an example as JSON when the spec is generated, so the document
+// carries the example as it is now rather than a live reference to the value. Examples that
+// can't be marshaled are left out, as is a nil example.
+func marshalExample(example any) any {
+	if example == nil {
+		return nil
+	}
+	data, err := json.Marshal(example)
+	if err != nil {
+		return nil
+	}
+	return json.RawMessage(data)
+}
+
 // schemaToOpenAPISchema converts a validation Schema to OpenAPI schema
 func schemaToOpenAPISchema(schema *Schema) *OpenAPISchema {
 	openAPISchema := &OpenAPISchema{
@@ -356,26 +386,32 @@ func schemaToOpenAPISchema(schema *Schema) *OpenAPISchema {
 	for fieldName, rule := range schema.fields {
 		propSchema := &OpenAPISchema{}
 
-		// Get field type from struct
-		structField, ok := schema.structType.FieldByName(getStructFieldName(schema.structType, fieldName))
-		if !ok {
+		// Get field type from struct, or from the JSON Schema the schema was imported from
+		structField, ok := schema.structField(rule)
+		switch {
+		case !ok && schema.structType == nil:
+			propSchema.Type = rule.valueType
+			if rule.valueType == "array" {
+				propSchema.Items = &OpenAPISchema{} // Item schemas aren't imported
+			}
+		case !ok:
 			continue
-		}
-
-		// Determine type
-		switch structField.Type.Kind() {
-		case reflect.String:
-			propSchema.Type = "string"
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			propSchema.Type = "integer"
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			propSchema.Type = "integer"
-		case reflect.Float32, reflect.Float64:
-			propSchema.Type = "number"
-		case reflect.Bool:
-			propSchema.Type = "boolean"
 		default:
-			propSchema.Type = "string"
+			// Determine type
+			switch structField.Type.Kind() {
+			case reflect.String:
+				propSchema.Type = "string"
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				propSchema.Type = "integer"
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				propSchema.Type = "integer"
+			case reflect.Float32, reflect.Float64:
+				propSchema.Type = "number"
+			case reflect.Bool:
+				propSchema.Type = "boolean"
+			default:
+				propSchema.Type = "string"
+			}
 		}
 
 		// Add validation constraints
@@ -388,11 +424,11 @@ func schemaToOpenAPISchema(schema *Schema) *OpenAPISchema {
 			propSchema.MaxLength = &maxLen
 		}
 		if rule.min != nil {
-			minFloat := float64(*rule.min)
+			minFloat := *rule.min
 			propSchema.Minimum = &minFloat
 		}
 		if rule.max != nil {
-			maxFloat := float64(*rule.max)
+			maxFloat := *rule.max
 			propSchema.Maximum = &maxFloat
 		}
 		if rule.pattern != nil {
@@ -423,7 +459,7 @@ func schemaToQueryParameters(schema *Schema) []OpenAPIParameter {
 	params := []OpenAPIParameter{}
 
 	for fieldName, rule := range schema.fields {
-		structField, ok := schema.structType.FieldByName(getStructFieldName(schema.structType, fieldName))
+		structField, ok := schema.structField(rule)
 		if !ok {
 			continue
 		}
@@ -457,11 +493,11 @@ func schemaToQueryParameters(schema *Schema) []OpenAPIParameter {
 			param.Schema.MaxLength = &maxLen
 		}
 		if rule.min != nil {
-			minFloat := float64(*rule.min)
+			minFloat := *rule.min
 			param.Schema.Minimum = &minFloat
 		}
 		if rule.max != nil {
-			maxFloat := float64(*rule.max)
+			maxFloat := *rule.max
 			param.Schema.Maximum = &maxFloat
 		}
 		if len(rule.enum) > 0 {
@@ -486,10 +522,10 @@ func schemaToQueryParameters(schema *Schema) []OpenAPIParameter {
 // Helper functions
 
 func convertPathParams(path string) string {
-	// Convert :param to {param}
+	// Convert :param and *wildcard to {param}
 	parts := strings.Split(path, "/")
 	for i, part := range parts {
-		if strings.HasPrefix(part, ":") {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
 			parts[i] = "{" + part[1:] + "}"
 		}
 	}
@@ -500,7 +536,7 @@ func extractPathParams(pattern string) []string {
 	params := []string{}
 	parts := strings.Split(pattern, "/")
 	for _, part := range parts {
-		if strings.HasPrefix(part, ":") {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
 			params = append(params, part[1:])
 		}
 	}
@@ -541,8 +577,11 @@ func capitalize(s string) string {
 }
 
 func getSchemaName(schema *Schema) string {
-	// Get the name of the struct type
-	typeName := schema.structType.Name()
+	// Get the name of the struct type (imported schemas have none)
+	var typeName string
+	if schema.structType != nil {
+		typeName = schema.structType.Name()
+	}
 	if typeName == "" {
 		typeName = "Request"
 	}
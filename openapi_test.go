@@ -1,6 +1,7 @@
 package nimbus
 
 import (
+	"encoding/json"
 	"net/http"
 	"testing"
 )
@@ -146,6 +147,7 @@ func TestConvertPathParams(t *testing.T) {
 		{"/posts/:postId/comments/:commentId", "/posts/{postId}/comments/{commentId}"},
 		{"/users", "/users"},
 		{"/", "/"},
+		{"/files/*path", "/files/{path}"},
 	}
 
 	for _, tt := range tests {
@@ -165,6 +167,7 @@ func TestExtractPathParams(t *testing.T) {
 		{"/posts/:postId/comments/:commentId", []string{"postId", "commentId"}},
 		{"/users", []string{}},
 		{"/api/v1/:resource/:id", []string{"resource", "id"}},
+		{"/files/*path", []string{"path"}},
 	}
 
 	for _, tt := range tests {
@@ -405,3 +408,73 @@ func TestStaticAndDynamicRoutes(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateOpenAPI_RouteExamples(t *testing.T) {
+	router := NewRouter()
+	handler := func(ctx *Context) (any, int, error) { return nil, http.StatusOK, nil }
+
+	router.POST("/users", handler,
+		WithRequestExample(TestAPIUser{Name: "Ada", Email: "ada@example.com", Age: 36}),
+		WithResponseExample(http.StatusCreated, map[string]string{"id": "42"}),
+		WithResponseExample(http.StatusConflict, NewErrorResponse(http.StatusConflict, "email_taken", "Email already registered")),
+	)
+	router.Route("POST", "/users").WithDoc(RouteMetadata{
+		Summary:        "Create user",
+		RequestSchema:  NewSchema(TestAPIUser{}),
+		RequestBody:    TestAPIUser{Name: "replaced"},
+		ResponseSchema: map[int]any{http.StatusCreated: "replaced", http.StatusNotFound: map[string]string{"error": "not_found"}},
+	})
+	router.GET("/users/:id", handler,
+		WithResponseExample(http.StatusOK, map[string]any{"id": "42", "name": "Ada"}),
+		WithResponseExample(http.StatusTeapot, make(chan int)), // Can't be marshaled
+	)
+
+	data, err := json.Marshal(router.GenerateOpenAPI(OpenAPIConfig{Title: "Test API", Version: "1.0.0"}))
+	if err != nil {
+		t.Fatalf("Failed to marshal spec: %v", err)
+	}
+	var spec map[string]any
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatal(err)
+	}
+
+	// example returns the JSON example at a path/method/location in the spec, as JSON text
+	example := func(path, method string, location ...string) string {
+		node := spec["paths"].(map[string]any)[path].(map[string]any)[method]
+		for _, key := range append(location, "content", "application/json", "example") {
+			object, ok := node.(map[string]any)
+			if !ok {
+				return ""
+			}
+			node = object[key]
+		}
+		if node == nil {
+			return ""
+		}
+		encoded, _ := json.Marshal(node)
+		return string(encoded)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		method   string
+		location []string
+		expected string
+	}{
+		{"request example", "/users", "post", []string{"requestBody"}, `{"age":36,"email":"ada@example.com","name":"Ada"}`},
+		{"response example replaces metadata", "/users", "post", []string{"responses", "201"}, `{"id":"42"}`},
+		{"error response example", "/users", "post", []string{"responses", "409"}, `{"code":409,"error":"email_taken","message":"Email already registered"}`},
+		{"metadata example kept", "/users", "post", []string{"responses", "404"}, `{"error":"not_found"}`},
+		{"get response example", "/users/{id}", "get", []string{"responses", "200"}, `{"id":"42","name":"Ada"}`},
+		{"unmarshalable example omitted", "/users/{id}", "get", []string{"responses", "418"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := example(tt.path, tt.method, tt.location...); got != tt.expected {
+				t.Errorf("Expected example %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
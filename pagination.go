@@ -0,0 +1,106 @@
+package nimbus
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// PageQuery holds the pagination parameters of a list request, bound by BindPagination.
+// It can also be embedded in a query struct bound with WithTyped or BindAndValidateQuery;
+// the validate tags then enforce the lower bounds, without BindPagination's defaults.
+type PageQuery struct {
+	Page  int `json:"page" validate:"min=1"`
+	Limit int `json:"limit" validate:"min=1"`
+}
+
+// Offset returns the number of items before the page, for SQL OFFSET and similar
+func (p PageQuery) Offset() int {
+	return (p.Page - 1) * p.Limit
+}
+
+// PaginationConfig configures BindPagination
+type PaginationConfig struct {
+	// DefaultLimit is the limit used when the request doesn't give one (default 20)
+	DefaultLimit int
+	// MaxLimit is the largest limit a request may ask for (default 100)
+	MaxLimit int
+}
+
+// DefaultPaginationConfig returns a default pagination configuration
+func DefaultPaginationConfig() PaginationConfig {
+	return PaginationConfig{
+		DefaultLimit: 20,
+		MaxLimit:     100,
+	}
+}
+
+// BindPagination binds the page and limit query parameters, so list endpoints don't each
+// parse them. Omitted (or empty) parameters default to page 1 and the config's DefaultLimit.
+// Values that aren't numbers, a page or limit below 1, and a limit above MaxLimit return
+// ValidationErrors, which render as a 422 when returned from the handler.
+//
+//	page, err := ctx.BindPagination(nimbus.PaginationConfig{MaxLimit: 50})
+//	if err != nil {
+//	    return nil, 0, err
+//	}
+//	items := store.List(page.Offset(), page.Limit)
+func (c *Context) BindPagination(configs ...PaginationConfig) (PageQuery, error) {
+	config := DefaultPaginationConfig()
+	if len(configs) > 0 {
+		if configs[0].DefaultLimit > 0 {
+			config.DefaultLimit = configs[0].DefaultLimit
+		}
+		if configs[0].MaxLimit > 0 {
+			config.MaxLimit = configs[0].MaxLimit
+		}
+	}
+
+	var errors ValidationErrors
+	page := c.pageParam("page", 1, &errors)
+	limit := c.pageParam("limit", config.DefaultLimit, &errors)
+
+	if !errors.Has("limit") && limit > config.MaxLimit {
+		errors = append(errors, ValidationError{
+			Field:   "limit",
+			Value:   limit,
+			Tag:     "max",
+			param:   strconv.Itoa(config.MaxLimit),
+			Message: fmt.Sprintf("limit must be at most %d", config.MaxLimit),
+		})
+	}
+
+	if len(errors) > 0 {
+		return PageQuery{}, errors
+	}
+	return PageQuery{Page: page, Limit: limit}, nil
+}
+
+// pageParam parses a positive integer query parameter, recording a ValidationError if it
+// isn't one. An omitted or empty parameter returns def.
+func (c *Context) pageParam(name string, def int, errors *ValidationErrors) int {
+	raw := c.Query(name)
+	if raw == "" {
+		return def
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		*errors = append(*errors, ValidationError{
+			Field:   name,
+			Value:   raw,
+			Tag:     "type",
+			Message: fmt.Sprintf("%s must be a number", name),
+		})
+		return 0
+	}
+	if value < 1 {
+		*errors = append(*errors, ValidationError{
+			Field:   name,
+			Value:   value,
+			Tag:     "min",
+			param:   "1",
+			Message: fmt.Sprintf("%s must be at least 1", name),
+		})
+	}
+	return value
+}
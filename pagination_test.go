@@ -0,0 +1,66 @@
+package nimbus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestContext_BindPagination(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		config   []PaginationConfig
+		expected PageQuery
+		errors   []string // "field:tag" for each expected error
+	}{
+		{"defaults when omitted", "", nil, PageQuery{Page: 1, Limit: 20}, nil},
+		{"defaults when empty", "page=&limit=", nil, PageQuery{Page: 1, Limit: 20}, nil},
+		{"explicit values", "page=3&limit=50", nil, PageQuery{Page: 3, Limit: 50}, nil},
+		{"at max limit", "limit=100", nil, PageQuery{Page: 1, Limit: 100}, nil},
+		{"over max limit", "limit=101", nil, PageQuery{}, []string{"limit:max"}},
+		{"configured default limit", "page=2", []PaginationConfig{{DefaultLimit: 10}}, PageQuery{Page: 2, Limit: 10}, nil},
+		{"configured max limit", "limit=30", []PaginationConfig{{MaxLimit: 25}}, PageQuery{}, []string{"limit:max"}},
+		{"zero page", "page=0", nil, PageQuery{}, []string{"page:min"}},
+		{"negative limit", "limit=-5", nil, PageQuery{}, []string{"limit:min"}},
+		{"not numbers", "page=two&limit=ten", nil, PageQuery{}, []string{"page:type", "limit:type"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items?"+tt.query, nil))
+			defer ctx.Release()
+
+			page, err := ctx.BindPagination(tt.config...)
+
+			if tt.errors == nil {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if page != tt.expected {
+					t.Errorf("Expected %+v, got %+v", tt.expected, page)
+				}
+				return
+			}
+
+			validationErrors, ok := err.(ValidationErrors)
+			if !ok {
+				t.Fatalf("Expected ValidationErrors, got %T: %v", err, err)
+			}
+			var got []string
+			for _, e := range validationErrors {
+				got = append(got, e.Field+":"+e.Tag)
+			}
+			if !slices.Equal(got, tt.errors) {
+				t.Errorf("Expected errors %v, got %v", tt.errors, validationErrors)
+			}
+		})
+	}
+}
+
+func TestPageQuery_Offset(t *testing.T) {
+	if offset := (PageQuery{Page: 3, Limit: 25}).Offset(); offset != 50 {
+		t.Errorf("Expected offset 50, got %d", offset)
+	}
+}
@@ -16,18 +16,45 @@ func NewAPIError(code, message string) *APIError {
 	return &APIError{Code: code, Message: message}
 }
 
+// StatusError is an error that carries the status and APIError it should be answered with.
+// Request body readers return it for failures that aren't malformed input, such as
+// middleware.Decompress's size cap, so the body binders (WithBodyValidation, WithTyped)
+// respond with Status instead of a 400 invalid_request. Err is the underlying cause.
+type StatusError struct {
+	Status   int
+	APIError *APIError
+	Err      error
+}
+
+// Error implements the error interface
+func (e *StatusError) Error() string {
+	return e.APIError.Error()
+}
+
+// Unwrap returns the underlying cause, for errors.Is
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
 // ErrorResponse represents a standard error response
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message,omitempty"`
-	Code    int    `json:"code"`
+	Error   string        `json:"error"`
+	Message string        `json:"message,omitempty"`
+	Code    int           `json:"code"`
+	Meta    *ResponseMeta `json:"meta,omitempty"`
 }
 
 // SuccessResponse represents a standard success response
 type SuccessResponse struct {
-	Success bool   `json:"success"`
-	Data    any    `json:"data,omitempty"`
-	Message string `json:"message,omitempty"`
+	Success bool          `json:"success"`
+	Data    any           `json:"data,omitempty"`
+	Message string        `json:"message,omitempty"`
+	Meta    *ResponseMeta `json:"meta,omitempty"`
+}
+
+// ResponseMeta carries request metadata in response envelopes (see WithRequestIDInBody)
+type ResponseMeta struct {
+	RequestID string `json:"request_id,omitempty"`
 }
 
 // NewErrorResponse creates a new error response
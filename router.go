@@ -1,7 +1,18 @@
 package nimbus
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"maps"
 	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"unique"
@@ -56,6 +67,9 @@ func getMethodHandle(method string) unique.Handle[string] {
 // - statusCode: HTTP status code (0 means use default based on error)
 // - error: if present, an error response will be sent
 //
+// A ValidationErrors error (e.g. from business rules checked in the handler) is rendered like
+// SendValidationError, with status 422 unless the handler returns another 4xx status.
+//
 // To send custom responses (HTML, plain text, etc.), use Context response methods:
 //
 //	return ctx.HTML(200, "<h1>Hello</h1>")
@@ -63,6 +77,15 @@ func getMethodHandle(method string) unique.Handle[string] {
 //	return ctx.Data(200, "text/plain", []byte("Hello"))
 //
 // These methods return (nil, 0, nil) to signal the response was already written.
+//
+// Returning a []byte, string, or io.Reader as data also writes it verbatim, without the
+// success envelope, which suits pre-rendered JSON, CSV, or binary content. Set the
+// Content-Type with ctx.Header to override the default:
+//
+//	ctx.Header("Content-Type", "application/json")
+//	return cachedJSON, 200, nil // []byte written as-is
+//
+// json.RawMessage is not treated as raw content; it is embedded in the envelope.
 type Handler func(*Context) (any, int, error)
 
 // TypedRequest holds typed request parameters, body, and query data.
@@ -98,6 +121,8 @@ type routingTable struct {
 	middlewares   []Middleware                                // Middleware stack for the router; reads last-in first-out (LIFO)
 	gen           uint64                                      // Generation counter for cache invalidation
 	notFoundRoute *Route                                      // Special synthetic route for 404 handler (also in chains map)
+	defaultRoute  *Route                                      // Catch-all route for unmatched requests set by Default, nil if none (also in chains map)
+	notAllowed    *Route                                      // Synthetic route for 405 responses with WithMethodNotAllowed (also in chains map)
 	chains        map[*Route]Handler                          // Pre-built middleware chains (route -> compiled handler)
 }
 
@@ -109,6 +134,172 @@ type Router struct {
 	table        atomic.Pointer[routingTable] // Immutable routing table (lock-free, type-safe reads)
 	mu           sync.Mutex                   // Only protects writes (route registration, middleware changes)
 	cleanupFuncs []func()                     // Functions to call on Shutdown (e.g., rate limiter cleanup)
+	config       routerConfig                 // Behavior settings (set once by NewRouter options, read-only afterwards)
+	stats        *routerStats                 // Request statistics, nil unless enabled with WithStats
+}
+
+// routerConfig holds router-wide behavior settings configured via RouterOption.
+type routerConfig struct {
+	wildcardLeadingSlash bool   // Keep the leading slash on captured wildcard tails ("/a/b" instead of "a/b")
+	rejectEmptyWildcard  bool   // Respond 404 when a wildcard captures an empty tail (e.g. /files/)
+	braceParams          bool   // Accept OpenAPI-style {name} and {*path} segments in route templates
+	requestIDInBody      bool   // Echo the request ID as meta.request_id in enveloped responses
+	noEnvelope           bool   // Render every route's success data as the JSON root (router-wide WithoutEnvelope)
+	basePath             string // Prefix stripped from request paths before matching, "" if none (see SetBasePath)
+	methodNotAllowed     bool   // Respond 405 with an Allow header when the path matches only other methods
+
+	errorFormatter ErrorFormatter // Builds error response bodies, nil for the default ErrorResponse
+}
+
+// RouterOption configures optional router behavior in NewRouter.
+type RouterOption func(*Router)
+
+// WithWildcardLeadingSlash keeps the leading slash on captured wildcard values.
+// By default, /files/a/b matching /files/*path captures "a/b"; with this option it captures "/a/b".
+func WithWildcardLeadingSlash() RouterOption {
+	return func(r *Router) {
+		r.config.wildcardLeadingSlash = true
+	}
+}
+
+// WithoutEmptyWildcard makes wildcard routes require a non-empty tail.
+// By default, /files/ matches /files/*path with an empty path; with this option it responds 404.
+// This is useful when the captured value is used to build filesystem paths.
+func WithoutEmptyWildcard() RouterOption {
+	return func(r *Router) {
+		r.config.rejectEmptyWildcard = true
+	}
+}
+
+// WithBraceParams accepts OpenAPI-style {name} and {*path} segments in route templates,
+// in addition to :name and *path. Brace segments are normalized when the route is
+// registered, so /users/{id} and /users/:id are the same route.
+// A brace segment must span the whole segment; /files/{name}.json panics.
+func WithBraceParams() RouterOption {
+	return func(r *Router) {
+		r.config.braceParams = true
+	}
+}
+
+// WithRequestIDInBody echoes the request ID set by the RequestID middleware in response
+// bodies as meta.request_id, for both SuccessResponse and ErrorResponse, so clients can quote
+// it when reporting problems. Responses are unchanged when no request ID was set, and routes
+// registered WithoutEnvelope never get it.
+func WithRequestIDInBody() RouterOption {
+	return func(r *Router) {
+		r.config.requestIDInBody = true
+	}
+}
+
+// WithoutSuccessEnvelope renders successful results of every route as the bare JSON value,
+// as if each route were registered WithoutEnvelope. Error responses keep the standard
+// ErrorResponse shape. By default, success data is wrapped in a SuccessResponse.
+//
+//	router := nimbus.NewRouter(nimbus.WithoutSuccessEnvelope())
+func WithoutSuccessEnvelope() RouterOption {
+	return func(r *Router) {
+		r.config.noEnvelope = true
+	}
+}
+
+// WithMethodNotAllowed responds 405 method_not_allowed, with an Allow header listing the
+// registered methods, when a request's path matches a route for other methods only. By default
+// such requests get the NotFound handler's 404. The 405 runs through the global middleware, as
+// a 404 does, and is checked before a Default route.
+func WithMethodNotAllowed() RouterOption {
+	return func(r *Router) {
+		r.config.methodNotAllowed = true
+	}
+}
+
+// ErrorFormatter builds the body of an error response from the status code and the error a
+// handler returned. err is an *APIError for framework errors such as ErrRouteNotFound and
+// ErrMethodNotAllowed; the request ID set by the RequestID middleware is available from ctx.
+// The body is marshaled as JSON with Content-Type application/json, unless the formatter sets
+// a JSON Content-Type of its own (e.g. application/problem+json) with ctx.Header.
+type ErrorFormatter func(ctx *Context, statusCode int, err error) any
+
+// WithErrorFormatter renders every error response, including 404 and 405 responses for
+// unmatched requests, with formatter instead of the default ErrorResponse, so all errors share
+// one shape:
+//
+//	router := nimbus.NewRouter(nimbus.WithErrorFormatter(func(ctx *nimbus.Context, status int, err error) any {
+//	    ctx.Header("Content-Type", "application/problem+json")
+//	    return map[string]any{"status": status, "title": http.StatusText(status), "detail": err.Error()}
+//	}))
+//
+// Validation errors, whether sent with SendValidationError or returned, keep their own shape.
+func WithErrorFormatter(formatter ErrorFormatter) RouterOption {
+	return func(r *Router) {
+		r.config.errorFormatter = formatter
+	}
+}
+
+// SetBasePath serves every route under prefix, for deployments behind a reverse proxy that
+// forwards a sub-path such as /api. Routes are registered without the prefix: with
+// SetBasePath("/api"), /api/users/5 matches /users/:id. The prefix is stripped only for
+// matching (handlers still see the full Request.URL.Path), requests outside it match no
+// route, and URL and the OpenAPI spec include it. Call it before serving requests.
+func (r *Router) SetBasePath(prefix string) {
+	prefix = strings.TrimRight(prefix, "/")
+	if prefix != "" && prefix[0] != '/' {
+		prefix = "/" + prefix
+	}
+	r.config.basePath = prefix
+}
+
+// stripBasePath returns the part of a request path that routes match against,
+// or false if the path is outside the base path.
+func (r *Router) stripBasePath(path string) (string, bool) {
+	base := r.config.basePath
+	switch {
+	case base == "":
+		return path, true
+	case path == base:
+		return "/", true
+	case strings.HasPrefix(path, base) && path[len(base)] == '/':
+		return path[len(base):], true
+	default:
+		return "", false
+	}
+}
+
+// withBasePath prefixes a route pattern with the base path
+func (r *Router) withBasePath(pattern string) string {
+	if pattern == "/" && r.config.basePath != "" {
+		return r.config.basePath
+	}
+	return r.config.basePath + pattern
+}
+
+// URL builds the path for a route pattern, filling in its parameters and prefixing the base
+// path. Parameter values are escaped; a wildcard value is used as is, so it may contain slashes.
+//
+//	router.URL("/users/:id", map[string]string{"id": "5"}) // "/api/users/5" with SetBasePath("/api")
+func (r *Router) URL(pattern string, params map[string]string) (string, error) {
+	if r.config.braceParams {
+		pattern = normalizeBraceParams(pattern)
+	}
+
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		if segment == "" || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+
+		value, ok := params[segment[1:]]
+		if !ok {
+			return "", fmt.Errorf("missing value for parameter %q in route %s", segment[1:], pattern)
+		}
+		if segment[0] == ':' {
+			value = url.PathEscape(value)
+		} else {
+			value = strings.TrimPrefix(value, "/")
+		}
+		segments[i] = value
+	}
+
+	return r.withBasePath(strings.Join(segments, "/")), nil
 }
 
 // Route represents a single route with its middleware chain.
@@ -119,16 +310,142 @@ type Route struct {
 	metadata    *RouteMetadata
 	method      string
 	pattern     string
+	wildcardKey string   // Name of the trailing catch-all parameter (e.g. "path" for /files/*path), empty if none
+	noEnvelope  bool     // Render success data as the JSON root instead of wrapping it in SuccessResponse
+	scopes      []string // Scopes a principal needs to call the route (enforced by middleware such as EnforceRouteScopes)
+	compiled    Handler  // Chain frozen at registration by AddCompiledRoute, nil for regular routes
+	priority    int      // Precedence over other routes matching the same path, set by WithPriority
+	paramCount  int      // Number of path params, set when inserted into a tree to size the params map
+
+	requestExample   any         // Example request body for OpenAPI, set by WithRequestExample
+	responseExamples map[int]any // Status code -> example response for OpenAPI, set by WithResponseExample
+}
+
+// RouteOption configures a single route when it is registered with Handle or the
+// method shortcuts (GET, POST, PUT, PATCH, DELETE).
+type RouteOption func(*Route)
+
+// WithMiddleware adds route-specific middleware, applied inside any global middleware.
+// It can be given more than once; middleware runs in the order it was added.
+func WithMiddleware(middleware ...Middleware) RouteOption {
+	return func(route *Route) {
+		route.middlewares = append(route.middlewares, middleware...)
+	}
+}
+
+// Method returns the HTTP method the route was registered for.
+func (route *Route) Method() string {
+	return route.method
+}
+
+// Pattern returns the route template (e.g. "/users/:id").
+func (route *Route) Pattern() string {
+	return route.pattern
+}
+
+// Metadata returns a copy of the route's documentation metadata and whether any was attached.
+// The copy keeps handlers from mutating metadata shared by every request to the route.
+func (route *Route) Metadata() (RouteMetadata, bool) {
+	if route.metadata == nil {
+		return RouteMetadata{}, false
+	}
+	metadata := *route.metadata
+	metadata.Tags = slices.Clone(metadata.Tags)
+	metadata.ResponseSchema = maps.Clone(metadata.ResponseSchema)
+	return metadata, true
+}
+
+// Middlewares returns a copy of the route's own middleware: group middleware followed by
+// middleware given with WithMiddleware, in the order they run. Global middleware added with
+// Router.Use is not included, since it applies to every route.
+func (route *Route) Middlewares() []Middleware {
+	return slices.Clone(route.middlewares)
+}
+
+// Scopes returns a copy of the scopes declared with WithScopes.
+func (route *Route) Scopes() []string {
+	return slices.Clone(route.scopes)
+}
+
+// WithScopes declares the scopes a principal needs to call the route.
+// The router only records them; enforce them with a middleware that reads ctx.Route(),
+// such as middleware.EnforceRouteScopes.
+//
+//	router.DELETE("/users/:id", deleteUser, nimbus.WithScopes("users:write"))
+func WithScopes(scopes ...string) RouteOption {
+	return func(route *Route) {
+		route.scopes = append(route.scopes, scopes...)
+	}
+}
+
+// WithoutEnvelope renders the route's successful results as the bare JSON value
+// ({"id": 1}) instead of wrapping them in a SuccessResponse ({"success": true, "data": {...}}).
+// Error responses keep the standard ErrorResponse shape.
+func WithoutEnvelope() RouteOption {
+	return func(route *Route) {
+		route.noEnvelope = true
+	}
+}
+
+// WithPriority gives the route precedence over other routes of the same method that match
+// the same request path: the matching route with the highest priority is served, regardless
+// of the usual static > param > wildcard order. Routes default to priority 0, and among
+// equal priorities the usual order applies. Negative priorities yield to unprioritized routes.
+//
+//	router.GET("/users/new", newUserForm)
+//	router.GET("/users/:id", getUser, nimbus.WithPriority(10)) // Also serves /users/new
+//
+// Once a method has a prioritized route, its requests compare every matching route instead
+// of stopping at the first, and static paths skip the exact-match fast path. Reserve it for
+// the overlaps the usual order gets wrong.
+func WithPriority(priority int) RouteOption {
+	return func(route *Route) {
+		route.priority = priority
+	}
+}
+
+// WithRequestExample documents an example request body for the route in the generated
+// OpenAPI spec. It takes precedence over RouteMetadata.RequestBody.
+//
+//	router.POST("/users", createUser, nimbus.WithRequestExample(CreateUserRequest{Name: "Ada"}))
+func WithRequestExample(example any) RouteOption {
+	return func(route *Route) {
+		route.requestExample = example
+	}
+}
+
+// WithResponseExample documents an example response body for a status code in the generated
+// OpenAPI spec. It can be given once per status; an example for a status also listed in
+// RouteMetadata.ResponseSchema replaces that one.
+//
+//	router.GET("/users/:id", getUser,
+//	    nimbus.WithResponseExample(http.StatusOK, User{ID: "42", Name: "Ada"}),
+//	    nimbus.WithResponseExample(http.StatusNotFound, nimbus.NewErrorResponse(404, "not_found", "User not found")),
+//	)
+func WithResponseExample(statusCode int, example any) RouteOption {
+	return func(route *Route) {
+		if route.responseExamples == nil {
+			route.responseExamples = make(map[int]any)
+		}
+		route.responseExamples[statusCode] = example
+	}
 }
 
 // NewRouter creates a new router instance with atomic.Pointer for lock-free, type-safe reads
 // HTTP method handles are pre-interned at package level for optimal performance
-func NewRouter() *Router {
+//
+// Optional RouterOptions adjust router-wide behavior:
+//
+//	router := nimbus.NewRouter(nimbus.WithoutEmptyWildcard())
+func NewRouter(opts ...RouterOption) *Router {
 	r := &Router{}
+	for _, opt := range opts {
+		opt(r)
+	}
 
 	// Default 404 handler
 	defaultNotFound := func(ctx *Context) (any, int, error) {
-		return nil, http.StatusNotFound, &APIError{Code: "not_found", Message: "route not found"}
+		return nil, http.StatusNotFound, ErrRouteNotFound
 	}
 
 	// Create synthetic route for 404 handler
@@ -139,9 +456,17 @@ func NewRouter() *Router {
 		pattern:     "",
 	}
 
-	// Initialize chains map with 404 handler
+	// Create synthetic route for 405 responses (only served with WithMethodNotAllowed)
+	notAllowedRoute := &Route{
+		handler: func(ctx *Context) (any, int, error) {
+			return nil, http.StatusMethodNotAllowed, ErrMethodNotAllowed
+		},
+	}
+
+	// Initialize chains map with 404 and 405 handlers
 	chains := make(map[*Route]Handler)
 	chains[notFoundRoute] = defaultNotFound // No middleware initially
+	chains[notAllowedRoute] = notAllowedRoute.handler
 
 	// Initialize with empty immutable routing table
 	// Method handles (methodGET, methodPOST, etc.) are package-level constants
@@ -151,6 +476,7 @@ func NewRouter() *Router {
 		middlewares:   nil,
 		gen:           0,
 		notFoundRoute: notFoundRoute,
+		notAllowed:    notAllowedRoute,
 		chains:        chains,
 	})
 
@@ -179,6 +505,10 @@ func (r *Router) Use(middleware ...Middleware) {
 	// Build and add notFound chain to the chains map
 	notFoundChain := buildNotFoundChain(old.notFoundRoute.handler, newMiddlewares)
 	newChains[old.notFoundRoute] = notFoundChain
+	newChains[old.notAllowed] = buildNotFoundChain(old.notAllowed.handler, newMiddlewares)
+	if old.defaultRoute != nil {
+		newChains[old.defaultRoute] = buildChain(old.defaultRoute, newMiddlewares)
+	}
 
 	new := &routingTable{
 		exactRoutes:   old.exactRoutes, // Share (routes are immutable after registration)
@@ -186,7 +516,9 @@ func (r *Router) Use(middleware ...Middleware) {
 		middlewares:   newMiddlewares,
 		gen:           old.gen + 1,       // Increment generation
 		notFoundRoute: old.notFoundRoute, // Share synthetic 404 route
-		chains:        newChains,         // Pre-built chains including 404
+		defaultRoute:  old.defaultRoute,  // Share catch-all route
+		notAllowed:    old.notAllowed,    // Share synthetic 405 route
+		chains:        newChains,         // Pre-built chains including 404 and catch-all
 	}
 
 	// Atomic swap - readers get new table immediately, no locks needed
@@ -198,22 +530,50 @@ func (r *Router) Use(middleware ...Middleware) {
 //
 //	router.AddRoute(http.MethodPost, "/users", handleCreateUser, authMiddleware)
 func (r *Router) AddRoute(method, path string, handler Handler, middleware ...Middleware) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	r.Handle(method, path, handler, WithMiddleware(middleware...))
+}
 
-	// Load current table (type-safe, no assertion needed)
-	old := r.table.Load()
+// Handle registers a route with the given HTTP method, path, handler, and optional RouteOptions
+// Example: router.Handle(http.MethodGet, "/users", handleUsers, nimbus.WithMiddleware(authMiddleware))
+func (r *Router) Handle(method, path string, handler Handler, opts ...RouteOption) {
+	r.handle(method, path, handler, false, opts)
+}
 
-	methodHandle := getMethodHandle(method)
+// AddCompiledRoute is Handle for routes whose middleware is fixed: the global middleware
+// registered so far, the route's own middleware and the handler are compiled once into a
+// single Handler that is served directly, without the per-request chain lookup.
+//
+// The tradeoff is that the chain is frozen: middleware added later with Use does not apply
+// to a compiled route. Register global middleware before compiled routes.
+func (r *Router) AddCompiledRoute(method, path string, handler Handler, opts ...RouteOption) {
+	r.handle(method, path, handler, true, opts)
+}
+
+// handle registers a route, freezing its chain at registration if compile is set
+func (r *Router) handle(method, path string, handler Handler, compile bool, opts []RouteOption) {
+	if r.config.braceParams {
+		path = normalizeBraceParams(path)
+	}
 
 	// Create route object
 	route := &Route{
 		handler:     handler,
-		middlewares: middleware,
 		method:      method,
 		pattern:     path,
+		wildcardKey: wildcardKey(path),
+	}
+	for _, opt := range opts {
+		opt(route)
 	}
 
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Load current table (type-safe, no assertion needed)
+	old := r.table.Load()
+
+	methodHandle := getMethodHandle(method)
+
 	// Clone maps for copy-on-write
 	newExactRoutes := copyExactRoutes(old.exactRoutes)
 	newTrees := copyTrees(old.trees)
@@ -243,6 +603,9 @@ func (r *Router) AddRoute(method, path string, handler Handler, middleware ...Mi
 		newChains[r] = chain
 	}
 	newChains[route] = buildChain(route, old.middlewares)
+	if compile {
+		route.compiled = newChains[route]
+	}
 
 	// Create and store new immutable table
 	new := &routingTable{
@@ -251,12 +614,101 @@ func (r *Router) AddRoute(method, path string, handler Handler, middleware ...Mi
 		middlewares:   old.middlewares,   // Unchanged
 		gen:           old.gen,           // Unchanged (only Use() increments)
 		notFoundRoute: old.notFoundRoute, // Unchanged
+		defaultRoute:  old.defaultRoute,  // Unchanged
+		notAllowed:    old.notAllowed,    // Unchanged
 		chains:        newChains,         // Updated with new route's chain
 	}
 
 	r.table.Store(new)
 }
 
+// GET registers a route for GET requests
+func (r *Router) GET(path string, handler Handler, opts ...RouteOption) {
+	r.Handle(http.MethodGet, path, handler, opts...)
+}
+
+// POST registers a route for POST requests
+func (r *Router) POST(path string, handler Handler, opts ...RouteOption) {
+	r.Handle(http.MethodPost, path, handler, opts...)
+}
+
+// PUT registers a route for PUT requests
+func (r *Router) PUT(path string, handler Handler, opts ...RouteOption) {
+	r.Handle(http.MethodPut, path, handler, opts...)
+}
+
+// PATCH registers a route for PATCH requests
+func (r *Router) PATCH(path string, handler Handler, opts ...RouteOption) {
+	r.Handle(http.MethodPatch, path, handler, opts...)
+}
+
+// DELETE registers a route for DELETE requests
+func (r *Router) DELETE(path string, handler Handler, opts ...RouteOption) {
+	r.Handle(http.MethodDelete, path, handler, opts...)
+}
+
+// Walk calls fn for every registered route, ordered by method and then by pattern, so audit
+// tooling sees the same sequence on every run. The NotFound handler is not a route and is
+// not visited.
+//
+// Walk reads a snapshot of the routing table: routes registered by fn are not visited,
+// and registering them does not deadlock.
+//
+//	router.Walk(func(method, pattern string, route *nimbus.Route) {
+//	    if strings.HasPrefix(pattern, "/admin") && len(route.Middlewares()) == 0 {
+//	        log.Printf("%s %s has no route middleware", method, pattern)
+//	    }
+//	})
+func (r *Router) Walk(fn func(method, pattern string, route *Route)) {
+	table := r.table.Load()
+
+	// Every route is in its method's tree (static routes are also indexed in exactRoutes)
+	var routes []*Route
+	for _, tree := range table.trees {
+		routes = append(routes, tree.collectRoutes()...)
+	}
+
+	slices.SortFunc(routes, func(a, b *Route) int {
+		if c := strings.Compare(a.method, b.method); c != 0 {
+			return c
+		}
+		return strings.Compare(a.pattern, b.pattern)
+	})
+
+	for _, route := range routes {
+		fn(route.method, route.pattern, route)
+	}
+}
+
+// normalizeBraceParams rewrites {name} segments to :name and {*path} segments to *path
+func normalizeBraceParams(path string) string {
+	if !strings.Contains(path, "{") {
+		return path
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if !strings.ContainsAny(segment, "{}") {
+			continue
+		}
+		if len(segment) < 3 || segment[0] != '{' || segment[len(segment)-1] != '}' || strings.Count(segment, "{") != 1 {
+			panic(fmt.Sprintf("invalid brace parameter %q in route %s: braces must wrap the whole segment", segment, path))
+		}
+
+		name := segment[1 : len(segment)-1]
+		if name == "*" {
+			panic(fmt.Sprintf("invalid brace parameter %q in route %s: wildcard needs a name", segment, path))
+		}
+		if strings.HasPrefix(name, "*") {
+			segments[i] = name
+		} else {
+			segments[i] = ":" + name
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
 // isStaticRoute returns true if the route has no dynamic parameters
 func isStaticRoute(path string) bool {
 	// Static routes don't contain ':' or '*' characters
@@ -268,6 +720,14 @@ func isStaticRoute(path string) bool {
 	return true
 }
 
+// wildcardKey returns the name of the trailing catch-all segment (e.g. "path" for /files/*path)
+func wildcardKey(path string) string {
+	if i := strings.LastIndexByte(path, '/'); i >= 0 && i+1 < len(path) && path[i+1] == '*' {
+		return path[i+2:]
+	}
+	return ""
+}
+
 // copyExactRoutes creates a shallow copy of the exactRoutes map for copy-on-write.
 // Routes themselves are shared (they're immutable after registration).
 // Uses unique.Handle[string] keys for O(1) pointer-based hashing.
@@ -307,6 +767,11 @@ func copyTrees(old map[unique.Handle[string]]*tree) map[unique.Handle[string]]*t
 func buildChain(route *Route, globalMiddlewares []Middleware) Handler {
 	handler := route.handler
 
+	// Only chains with middleware can be aborted before reaching the handler
+	if len(route.middlewares) > 0 || len(globalMiddlewares) > 0 {
+		handler = skipIfAborted(handler)
+	}
+
 	// Apply route-specific middleware in reverse order (last added wraps first)
 	for i := len(route.middlewares) - 1; i >= 0; i-- {
 		handler = route.middlewares[i](handler)
@@ -325,6 +790,10 @@ func buildChain(route *Route, globalMiddlewares []Middleware) Handler {
 func buildNotFoundChain(notFound Handler, globalMiddlewares []Middleware) Handler {
 	handler := notFound
 
+	if len(globalMiddlewares) > 0 {
+		handler = skipIfAborted(handler)
+	}
+
 	// Apply global middleware in reverse order (last added wraps first)
 	for i := len(globalMiddlewares) - 1; i >= 0; i-- {
 		handler = globalMiddlewares[i](handler)
@@ -333,6 +802,17 @@ func buildNotFoundChain(notFound Handler, globalMiddlewares []Middleware) Handle
 	return handler
 }
 
+// skipIfAborted wraps the innermost handler of a chain so it doesn't run after ctx.Abort().
+// The aborting middleware owns the response, so nothing is returned here.
+func skipIfAborted(handler Handler) Handler {
+	return func(ctx *Context) (any, int, error) {
+		if ctx.aborted {
+			return nil, 0, nil
+		}
+		return handler(ctx)
+	}
+}
+
 // buildAllChains pre-compiles middleware chains for all routes in the routing table.
 // This is called when global middleware changes or when the routing table is rebuilt.
 // Returns an immutable map of route -> compiled chain for lock-free lookups.
@@ -417,7 +897,9 @@ func (r *Router) Group(prefix string, middleware ...Middleware) *Group {
 	}
 }
 
-// Use adds middleware to the group
+// Use adds middleware to the group.
+// Group middleware is captured when a route is registered, so it only applies to routes
+// added to the group after this call. Router.Use, by contrast, rebuilds every route's chain.
 func (g *Group) Use(middleware ...Middleware) {
 	g.middlewares = append(g.middlewares, middleware...)
 }
@@ -425,9 +907,99 @@ func (g *Group) Use(middleware ...Middleware) {
 // AddRoute registers a route in the group with the given HTTP method, path, handler, and optional middleware
 // The group prefix and group middleware are automatically applied
 func (g *Group) AddRoute(method, path string, handler Handler, middleware ...Middleware) {
-	fullPath := g.prefix + path
-	allMiddleware := append(g.middlewares, middleware...)
-	g.router.AddRoute(method, fullPath, handler, allMiddleware...)
+	g.Handle(method, path, handler, WithMiddleware(middleware...))
+}
+
+// Handle registers a route in the group with the given HTTP method, path, handler, and optional RouteOptions
+// The group prefix and group middleware are automatically applied
+func (g *Group) Handle(method, path string, handler Handler, opts ...RouteOption) {
+	// Group middleware goes first so it wraps any middleware added by opts.
+	// WithMiddleware copies into the route's own slice, so routes never share the group's backing array.
+	allOpts := make([]RouteOption, 0, len(opts)+1)
+	allOpts = append(allOpts, WithMiddleware(g.middlewares...))
+	allOpts = append(allOpts, opts...)
+	g.router.Handle(method, g.prefix+path, handler, allOpts...)
+}
+
+// GET registers a route in the group for GET requests
+func (g *Group) GET(path string, handler Handler, opts ...RouteOption) {
+	g.Handle(http.MethodGet, path, handler, opts...)
+}
+
+// POST registers a route in the group for POST requests
+func (g *Group) POST(path string, handler Handler, opts ...RouteOption) {
+	g.Handle(http.MethodPost, path, handler, opts...)
+}
+
+// PUT registers a route in the group for PUT requests
+func (g *Group) PUT(path string, handler Handler, opts ...RouteOption) {
+	g.Handle(http.MethodPut, path, handler, opts...)
+}
+
+// PATCH registers a route in the group for PATCH requests
+func (g *Group) PATCH(path string, handler Handler, opts ...RouteOption) {
+	g.Handle(http.MethodPatch, path, handler, opts...)
+}
+
+// DELETE registers a route in the group for DELETE requests
+func (g *Group) DELETE(path string, handler Handler, opts ...RouteOption) {
+	g.Handle(http.MethodDelete, path, handler, opts...)
+}
+
+// MountRouter registers every route of child under prefix, so modules can build their own
+// *Router with its own routes and middleware and be composed into one server:
+//
+//	v1 := nimbus.NewRouter()
+//	v1.Use(middleware.Auth(validateToken))
+//	v1.GET("/users/:id", getUser)
+//
+//	app := nimbus.NewRouter()
+//	app.Use(middleware.Logger())
+//	app.MountRouter("/v1", v1) // serves GET /v1/users/:id
+//
+// Mounted routes run the parent's global middleware first, then the child's global
+// middleware, then the route's own middleware; routes the child registered with
+// AddCompiledRoute keep the chain compiled there, inside the parent's middleware. Like group
+// middleware, the child's routes and global middleware are captured when it is mounted:
+// routes or middleware added to the child afterwards are not served by the parent. The
+// child's cleanup functions run when the parent is shut down.
+//
+// Only the routes are mounted. Requests are matched and rendered with the parent's router
+// options (wildcard handling, error formatter, request IDs in bodies, 405 responses), except
+// that WithoutSuccessEnvelope on the child still applies to its routes. The child's NotFound
+// handler and Default route are not used: unmatched paths under prefix get the parent's.
+func (r *Router) MountRouter(prefix string, child *Router) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	childMiddlewares := child.table.Load().middlewares
+
+	child.Walk(func(method, pattern string, route *Route) {
+		handler := route.handler
+		if route.compiled != nil {
+			// The compiled chain already includes the child's middleware
+			handler = route.compiled
+		}
+
+		r.Handle(method, prefix+pattern, handler, func(mounted *Route) {
+			if route.compiled == nil {
+				// The child's global middleware wraps the route's own middleware
+				mounted.middlewares = make([]Middleware, 0, len(childMiddlewares)+len(route.middlewares))
+				mounted.middlewares = append(mounted.middlewares, childMiddlewares...)
+				mounted.middlewares = append(mounted.middlewares, route.middlewares...)
+			}
+
+			if route.metadata != nil {
+				metadata := *route.metadata
+				mounted.metadata = &metadata
+			}
+			mounted.noEnvelope = route.noEnvelope || child.config.noEnvelope
+			mounted.priority = route.priority
+			mounted.scopes = slices.Clone(route.scopes)
+			mounted.requestExample = route.requestExample
+			mounted.responseExamples = maps.Clone(route.responseExamples)
+		})
+	})
+
+	r.RegisterCleanup(child.Shutdown)
 }
 
 // ServeHTTP implements http.Handler interface.
@@ -435,6 +1007,16 @@ func (g *Group) AddRoute(method, path string, handler Handler, middleware ...Mid
 // Achieves true lock-free performance: ~40ns per request under high concurrency.
 // HTTP methods use unique.Handle as map keys for O(1) pointer-based hashing (faster than string hashing).
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.stats != nil {
+		r.serveWithStats(w, req)
+		return
+	}
+
+	r.serve(w, req)
+}
+
+// serve matches the request to a route and runs it
+func (r *Router) serve(w http.ResponseWriter, req *http.Request) {
 	ctx := NewContext(w, req)
 	defer ctx.Release() // Return context to pool when done
 
@@ -445,39 +1027,157 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	// unique.Handle provides O(1) pointer-based hashing instead of O(n) string hashing
 	methodHandle := getMethodHandle(req.Method)
 
+	// Paths outside the base path skip matching and go to the unmatched handling below.
+	// A path with escapes that URL.Path can't represent (e.g. %2F) is matched as sent, by
+	// static and dynamic routes alike, so an encoded slash stays within its segment; the
+	// captured params are decoded afterwards
+	path, escaped := req.URL.Path, req.URL.RawPath != ""
+	if escaped {
+		path = req.URL.EscapedPath()
+	}
+	path, underBase := r.stripBasePath(path)
+
 	// Fast path: Try exact match first (O(1) for static routes)
 	// Map lookup uses pointer hash (much faster than string hash)
-	if exactRoutes := table.exactRoutes[methodHandle]; exactRoutes != nil {
-		if route, ok := exactRoutes[req.URL.Path]; ok {
+	// Skipped when the method has prioritized routes, as one may outrank the static route
+	tree := table.trees[methodHandle]
+	if exactRoutes := table.exactRoutes[methodHandle]; underBase && exactRoutes != nil && (tree == nil || !tree.prioritized) {
+		if route, ok := exactRoutes[path]; ok {
 			// Static route - no path params needed (stays nil)
+			ctx.route = route
 			// ✅ Lock-free chain lookup - just a map read!
-			chain := table.chains[route]
-			r.executeHandler(ctx, chain)
+			chain := table.chainFor(route)
+			r.executeHandler(ctx, route, chain)
 			return
 		}
 	}
 
 	// Slow path: Fall back to radix tree for dynamic routes
-	if tree := table.trees[methodHandle]; tree != nil {
-		if route, params := tree.search(req.URL.Path); route != nil {
+	if underBase && tree != nil {
+		if route, params := tree.search(path); route != nil && r.normalizeWildcard(route, params) {
+			if escaped {
+				ctx.rawPathParams = decodePathParams(params)
+			}
 			ctx.PathParams = params
+			ctx.route = route
 
 			// ✅ Lock-free chain lookup - just a map read!
-			chain := table.chains[route]
-			r.executeHandler(ctx, chain)
+			chain := table.chainFor(route)
+			r.executeHandler(ctx, route, chain)
 			return
 		}
 	}
 
-	// No route found - use pre-built 404 chain from chains map
+	// The path may match routes for other methods only
+	if r.config.methodNotAllowed && underBase {
+		if allowed := table.allowedMethods(path); len(allowed) > 0 {
+			ctx.Writer.Header().Set("Allow", strings.Join(allowed, ", "))
+			r.executeHandler(ctx, table.notAllowed, table.chains[table.notAllowed])
+			return
+		}
+	}
+
+	// No route found - run the catch-all route if one is registered
+	if table.defaultRoute != nil {
+		ctx.route = table.defaultRoute
+		r.executeHandler(ctx, table.defaultRoute, table.chains[table.defaultRoute])
+		return
+	}
+
+	// Otherwise use pre-built 404 chain from chains map
 	// ✅ Lock-free - just another map lookup!
-	r.executeHandler(ctx, table.chains[table.notFoundRoute])
+	r.executeHandler(ctx, table.notFoundRoute, table.chains[table.notFoundRoute])
+}
+
+// Methods returns the sorted HTTP methods with a route matching path, such as
+// [GET POST] for /users/42 with GET and POST routes for /users/:id. path is matched as a
+// request path would be, against routes as registered (without the SetBasePath prefix), so
+// SDK generators and OPTIONS handlers can tell what a URL supports. It returns an empty
+// slice when no route matches.
+func (r *Router) Methods(path string) []string {
+	return r.table.Load().allowedMethods(path)
+}
+
+// allowedMethods returns the sorted methods with a route matching the request path, given
+// as it is matched (see ServeHTTP)
+func (t *routingTable) allowedMethods(path string) []string {
+	allowed := []string{}
+	for methodHandle, tree := range t.trees {
+		if _, ok := t.exactRoutes[methodHandle][path]; ok {
+			allowed = append(allowed, methodHandle.Value())
+		} else if route, _ := tree.search(path); route != nil {
+			allowed = append(allowed, methodHandle.Value())
+		}
+	}
+	slices.Sort(allowed)
+	return allowed
+}
+
+// decodePathParams percent-decodes path params in place and returns their raw values.
+// net/http rejects requests with malformed escapes, so decoding only fails for hand-built
+// requests; such a value is left as matched rather than failing the request.
+func decodePathParams(params map[string]string) map[string]string {
+	raw := maps.Clone(params)
+	for name, value := range params {
+		if decoded, err := url.PathUnescape(value); err == nil {
+			params[name] = decoded
+		}
+	}
+	return raw
+}
+
+// chainFor returns the compiled handler chain for a route.
+// Fast path: when neither the router nor the route has middleware, the chain is just the
+// route's handler, so it's called directly without the chains map lookup. Routes added with
+// AddCompiledRoute always use the chain frozen at registration.
+func (t *routingTable) chainFor(route *Route) Handler {
+	if route.compiled != nil {
+		return route.compiled
+	}
+	if len(t.middlewares) == 0 && len(route.middlewares) == 0 {
+		return route.handler
+	}
+	return t.chains[route]
+}
+
+// normalizeWildcard applies the router's wildcard settings to a matched route's params.
+// Returns false if the match should be rejected (empty tail when empty tails are disallowed).
+func (r *Router) normalizeWildcard(route *Route, params map[string]string) bool {
+	if route.wildcardKey == "" {
+		return true
+	}
+
+	tail := params[route.wildcardKey]
+	if tail == "" && r.config.rejectEmptyWildcard {
+		return false
+	}
+	if r.config.wildcardLeadingSlash {
+		params[route.wildcardKey] = "/" + tail
+	}
+	return true
 }
 
 // executeHandler executes the handler and sends the response based on return values
-func (r *Router) executeHandler(ctx *Context, handler Handler) {
+func (r *Router) executeHandler(ctx *Context, route *Route, handler Handler) {
 	data, statusCode, err := handler(ctx)
+	writeResponse(ctx, data, statusCode, err, renderOptions{
+		envelope:    !route.noEnvelope && !r.config.noEnvelope,
+		requestID:   r.config.requestIDInBody,
+		formatError: r.config.errorFormatter,
+	})
+}
+
+// renderOptions controls how writeResponse shapes the response body
+type renderOptions struct {
+	envelope    bool           // Wrap success data in SuccessResponse (false writes data as the JSON root)
+	requestID   bool           // Add meta.request_id to SuccessResponse and ErrorResponse
+	formatError ErrorFormatter // Builds error bodies instead of ErrorResponse, nil for the default
+}
 
+// writeResponse renders a handler's (data, statusCode, error) result to the response writer.
+// Every branch writes through ctx.Writer without resetting its header map, so headers set
+// by middleware and handlers are sent with error responses as well as successful ones.
+func writeResponse(ctx *Context, data any, statusCode int, err error, opts renderOptions) {
 	// If status is 0, the handler has already written the response (e.g., HTML)
 	if statusCode == 0 && err == nil {
 		return
@@ -485,18 +1185,39 @@ func (r *Router) executeHandler(ctx *Context, handler Handler) {
 
 	// Handle error response
 	if err != nil {
+		// Field errors from business-rule checks in the handler get the same body as
+		// SendValidationError, as 422 unless the handler chose another 4xx status
+		var validationErrs ValidationErrors
+		if errors.As(err, &validationErrs) {
+			if statusCode < http.StatusBadRequest || statusCode >= http.StatusInternalServerError {
+				statusCode = http.StatusUnprocessableEntity
+			}
+			ctx.sendValidationError(statusCode, validationErrs)
+			return
+		}
+
 		if statusCode == 0 {
 			statusCode = http.StatusInternalServerError
 		}
 
+		if opts.formatError != nil {
+			writeFormattedError(ctx, statusCode, err, opts.formatError)
+			return
+		}
+
 		// Check if error is a custom error with details
+		var resp *ErrorResponse
 		if apiErr, ok := err.(*APIError); ok {
-			ctx.JSON(statusCode, NewErrorResponse(statusCode, apiErr.Code, apiErr.Message))
-			return
+			resp = NewErrorResponse(statusCode, apiErr.Code, apiErr.Message)
+		} else {
+			// Default error response
+			resp = NewErrorResponse(statusCode, "error", err.Error())
 		}
 
-		// Default error response
-		ctx.JSON(statusCode, NewErrorResponse(statusCode, "error", err.Error()))
+		if opts.requestID {
+			resp.Meta = responseMeta(ctx)
+		}
+		ctx.JSON(statusCode, resp)
 		return
 	}
 
@@ -512,8 +1233,127 @@ func (r *Router) executeHandler(ctx *Context, handler Handler) {
 		return
 	}
 
+	// Write raw content verbatim instead of marshaling it
+	if writeRaw(ctx, data, statusCode) {
+		return
+	}
+
+	// Send bare data for routes registered WithoutEnvelope
+	if !opts.envelope {
+		ctx.JSON(statusCode, data)
+		return
+	}
+
 	// Send success response with data
-	ctx.JSON(statusCode, NewSuccessResponse(data, ""))
+	resp := NewSuccessResponse(data, "")
+	if opts.requestID {
+		resp.Meta = responseMeta(ctx)
+	}
+	ctx.JSON(statusCode, resp)
+}
+
+// writeFormattedError renders an error body built by a router's ErrorFormatter as JSON,
+// keeping a JSON Content-Type the formatter set (such as application/problem+json).
+func writeFormattedError(ctx *Context, statusCode int, err error, formatError ErrorFormatter) {
+	body := formatError(ctx, statusCode, err)
+
+	contentType := ctx.Writer.Header().Get("Content-Type")
+	if !strings.Contains(contentType, "json") {
+		contentType = "application/json"
+	}
+
+	data, marshalErr := json.Marshal(body)
+	if marshalErr != nil {
+		ctx.JSON(http.StatusInternalServerError, NewErrorResponse(http.StatusInternalServerError, "error", marshalErr.Error()))
+		return
+	}
+	ctx.Data(statusCode, contentType, data)
+}
+
+// writeRaw writes []byte, string, io.Reader, and <-chan []byte results as the response body
+// without JSON encoding, reporting whether data was one of those types. A Content-Type set by
+// the handler is kept; otherwise it is sniffed for []byte, text/plain for string, and
+// application/octet-stream for readers and channels. Readers that are also io.Closers are
+// closed, and an error copying a reader is logged.
+func writeRaw(ctx *Context, data any, statusCode int) bool {
+	contentType := ctx.Writer.Header().Get("Content-Type")
+
+	switch body := data.(type) {
+	case []byte:
+		if contentType == "" {
+			contentType = http.DetectContentType(body)
+		}
+		ctx.Data(statusCode, contentType, body)
+	case string:
+		if contentType == "" {
+			contentType = "text/plain; charset=utf-8"
+		}
+		ctx.Data(statusCode, contentType, []byte(body))
+	case io.Reader:
+		if closer, ok := body.(io.Closer); ok {
+			defer closer.Close()
+		}
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		ctx.Set(StatusCodeKey, statusCode) // Store for logging
+		ctx.Writer.Header().Set("Content-Type", contentType)
+		ctx.Writer.WriteHeader(statusCode)
+		// The status is already sent, so a failed copy (a broken reader or a client
+		// that went away) can only be logged
+		if _, err := io.Copy(ctx.Writer, body); err != nil {
+			log.Printf("nimbus: writing %s %s response body: %v", ctx.Request.Method, ctx.Request.URL.Path, err)
+		}
+	case <-chan []byte:
+		writeChunks(ctx, body, statusCode, contentType)
+	case chan []byte:
+		writeChunks(ctx, body, statusCode, contentType)
+	default:
+		return false
+	}
+	return true
+}
+
+// writeChunks streams each chunk received from a handler's channel as it arrives, flushing
+// after every write so clients see it immediately (chunked transfer encoding over HTTP/1.1).
+// It returns when the channel is closed or the client goes away; the handler's goroutine
+// should select on ctx.Request.Context().Done() so it stops sending once nobody is reading.
+func writeChunks(ctx *Context, chunks <-chan []byte, statusCode int, contentType string) {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	ctx.Set(StatusCodeKey, statusCode) // Store for logging
+	ctx.Writer.Header().Set("Content-Type", contentType)
+	ctx.Writer.WriteHeader(statusCode)
+
+	// ResponseController finds the http.Flusher behind middleware writers that implement Unwrap
+	controller := http.NewResponseController(ctx.Writer)
+	controller.Flush()
+
+	done := ctx.Request.Context().Done()
+	for {
+		select {
+		case chunk, ok := <-chunks:
+			if !ok {
+				return
+			}
+			if _, err := ctx.Writer.Write(chunk); err != nil {
+				return
+			}
+			controller.Flush()
+		case <-done:
+			return
+		}
+	}
+}
+
+// responseMeta returns the response metadata for the request, or nil if there is none
+func responseMeta(ctx *Context) *ResponseMeta {
+	requestID := ctx.GetString(ContextKeyRequestID)
+	if requestID == "" {
+		return nil
+	}
+	return &ResponseMeta{RequestID: requestID}
 }
 
 // NotFound sets a custom 404 handler
@@ -549,12 +1389,128 @@ func (r *Router) NotFound(handler Handler) {
 		middlewares:   old.middlewares,
 		gen:           old.gen,
 		notFoundRoute: newNotFoundRoute, // New synthetic route
-		chains:        newChains,        // Updated chains with new 404
+		defaultRoute:  old.defaultRoute,
+		notAllowed:    old.notAllowed,
+		chains:        newChains, // Updated chains with new 404
+	}
+
+	r.table.Store(new)
+}
+
+// ErrRouteNotFound is the error rendered for requests that match no route. A Default
+// handler returns it to hand the request to the NotFound handler.
+var ErrRouteNotFound = NewAPIError("not_found", "route not found")
+
+// ErrMethodNotAllowed is the error rendered for requests whose path only matches routes for
+// other methods, when the router is created WithMethodNotAllowed.
+var ErrMethodNotAllowed = NewAPIError("method_not_allowed", "method not allowed")
+
+// Default registers a catch-all route that runs for any request no other route matches,
+// whatever its method or path, instead of the NotFound handler. It is a normal route:
+// global middleware and opts apply, and ctx.Route() returns it (with pattern "*").
+// Returning ErrRouteNotFound hands the request to the NotFound handler, so an SPA can serve
+// index.html for unmatched pages while unmatched API paths still 404:
+//
+//	router.Default(func(ctx *nimbus.Context) (any, int, error) {
+//	    if ctx.Request.Method != http.MethodGet || strings.HasPrefix(ctx.Request.URL.Path, "/api/") {
+//	        return nil, 0, nimbus.ErrRouteNotFound
+//	    }
+//	    return ctx.HTML(http.StatusOK, indexHTML)
+//	})
+func (r *Router) Default(handler Handler, opts ...RouteOption) {
+	route := &Route{
+		handler: func(ctx *Context) (any, int, error) {
+			data, statusCode, err := handler(ctx)
+			if err == ErrRouteNotFound {
+				return r.runNotFound(ctx)
+			}
+			return data, statusCode, err
+		},
+		pattern: "*",
+	}
+	for _, opt := range opts {
+		opt(route)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old := r.table.Load()
+
+	// Copy chains, replacing any previous catch-all route
+	newChains := make(map[*Route]Handler, len(old.chains)+1)
+	for existing, chain := range old.chains {
+		if existing != old.defaultRoute {
+			newChains[existing] = chain
+		}
+	}
+	newChains[route] = buildChain(route, old.middlewares)
+
+	new := &routingTable{
+		exactRoutes:   old.exactRoutes,
+		trees:         old.trees,
+		middlewares:   old.middlewares,
+		gen:           old.gen,
+		notFoundRoute: old.notFoundRoute,
+		defaultRoute:  route, // New catch-all route
+		notAllowed:    old.notAllowed,
+		chains:        newChains,
 	}
 
 	r.table.Store(new)
 }
 
+// runNotFound hands a matched request to the NotFound handler. The caller is already inside
+// the global middleware, so the bare handler runs rather than its chain.
+func (r *Router) runNotFound(ctx *Context) (any, int, error) {
+	ctx.route = nil
+	return r.table.Load().notFoundRoute.handler(ctx)
+}
+
+// Static serves files from root under prefix for GET and HEAD requests, e.g.
+// router.Static("/assets", http.Dir("./public")) serves ./public/app.js at /assets/app.js.
+// A directory is served by its index.html. Missing files (and directories without an index)
+// go to the NotFound handler, so they get the same JSON 404 as any unmatched route rather
+// than http.FileServer's plain-text page; a custom NotFound handler applies to them too.
+// Files are served with http.ServeContent, so Range and conditional requests work.
+func (r *Router) Static(prefix string, root http.FileSystem, opts ...RouteOption) {
+	handler := func(ctx *Context) (any, int, error) {
+		name := path.Clean("/" + ctx.Param("filepath"))
+
+		file, info, ok := openStatic(root, name)
+		if !ok {
+			return r.runNotFound(ctx)
+		}
+		defer file.Close()
+
+		http.ServeContent(ctx.Writer, ctx.Request, info.Name(), info.ModTime(), file)
+		return nil, 0, nil
+	}
+
+	pattern := strings.TrimSuffix(prefix, "/") + "/*filepath"
+	r.Handle(http.MethodGet, pattern, handler, opts...)
+	r.Handle(http.MethodHead, pattern, handler, opts...)
+}
+
+// openStatic opens a file to serve from root, resolving a directory to its index.html.
+// Reports false if there is no regular file to serve.
+func openStatic(root http.FileSystem, name string) (http.File, fs.FileInfo, bool) {
+	file, err := root.Open(name)
+	if err != nil {
+		return nil, nil, false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, false
+	}
+	if info.IsDir() {
+		file.Close()
+		return openStatic(root, path.Join(name, "index.html"))
+	}
+	return file, info, true
+}
+
 // RegisterCleanup registers a cleanup function to be called on Shutdown.
 // This is used internally by middleware (e.g., rate limiter) to register cleanup goroutines.
 // Users typically don't need to call this directly.
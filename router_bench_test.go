@@ -22,6 +22,29 @@ func BenchmarkRouter_StaticRoute(b *testing.B) {
 	}
 }
 
+// BenchmarkRouter_StaticRoute_PassthroughMiddleware is the counterpart to BenchmarkRouter_StaticRoute
+// with a no-op middleware, so the chain lookup isn't skipped by the no-middleware fast path.
+func BenchmarkRouter_StaticRoute_PassthroughMiddleware(b *testing.B) {
+	router := NewRouter()
+	router.Use(func(next Handler) Handler {
+		return func(ctx *Context) (any, int, error) {
+			return next(ctx)
+		}
+	})
+	router.AddRoute(http.MethodGet, "/test", func(ctx *Context) (any, int, error) {
+		return map[string]any{"status": "ok"}, http.StatusOK, nil
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+	}
+}
+
 func BenchmarkRouter_ParameterRoute(b *testing.B) {
 	router := NewRouter()
 	router.AddRoute(http.MethodGet, "/users/:id", func(ctx *Context) (any, int, error) {
@@ -179,3 +202,69 @@ func BenchmarkContext_SetGet(b *testing.B) {
 		_, _ = ctx.Get("key")
 	}
 }
+
+// BenchmarkChain_Precompiled and BenchmarkChain_ComposePerRequest compare running a route's
+// pre-built chain against wrapping the handler in its middleware on every request.
+func BenchmarkChain_Precompiled(b *testing.B) {
+	router, route := benchmarkChainRouter()
+	chain := router.table.Load().chainFor(route)
+	ctx := &Context{}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		chain(ctx)
+	}
+}
+
+func BenchmarkChain_ComposePerRequest(b *testing.B) {
+	router, route := benchmarkChainRouter()
+	middlewares := router.table.Load().middlewares
+	ctx := &Context{}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buildChain(route, middlewares)(ctx)
+	}
+}
+
+func benchmarkChainRouter() (*Router, *Route) {
+	router := NewRouter()
+	for i := 0; i < 5; i++ {
+		router.Use(func(next Handler) Handler {
+			return func(ctx *Context) (any, int, error) {
+				return next(ctx)
+			}
+		})
+	}
+
+	router.AddRoute(http.MethodGet, "/test", func(ctx *Context) (any, int, error) {
+		return nil, http.StatusOK, nil
+	})
+
+	return router, router.table.Load().exactRoutes[getMethodHandle(http.MethodGet)]["/test"]
+}
+
+// BenchmarkRouter_CompiledRoute is BenchmarkRouter_StaticRoute_PassthroughMiddleware with the
+// route registered through AddCompiledRoute, which skips the chains map lookup.
+func BenchmarkRouter_CompiledRoute(b *testing.B) {
+	router := NewRouter()
+	router.Use(func(next Handler) Handler {
+		return func(ctx *Context) (any, int, error) {
+			return next(ctx)
+		}
+	})
+	router.AddCompiledRoute(http.MethodGet, "/test", func(ctx *Context) (any, int, error) {
+		return map[string]any{"status": "ok"}, http.StatusOK, nil
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+	}
+}
@@ -1,10 +1,21 @@
 package nimbus
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
+	"testing/fstest"
 )
 
 func TestRouter_GET(t *testing.T) {
@@ -206,3 +217,1588 @@ func TestConcurrentTreeMutation(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestRouter_WildcardOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []RouterOption
+		path         string
+		expectedCode int
+		expectedTail string
+	}{
+		{"default tail", nil, "/files/a/b", http.StatusOK, "a/b"},
+		{"default empty tail", nil, "/files/", http.StatusOK, ""},
+		{"leading slash tail", []RouterOption{WithWildcardLeadingSlash()}, "/files/a/b", http.StatusOK, "/a/b"},
+		{"leading slash empty tail", []RouterOption{WithWildcardLeadingSlash()}, "/files/", http.StatusOK, "/"},
+		{"empty tail rejected", []RouterOption{WithoutEmptyWildcard()}, "/files/", http.StatusNotFound, ""},
+		{"non-empty tail with rejection", []RouterOption{WithoutEmptyWildcard()}, "/files/a", http.StatusOK, "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter(tt.opts...)
+			router.AddRoute(http.MethodGet, "/files/*path", func(ctx *Context) (any, int, error) {
+				return ctx.String(http.StatusOK, ctx.Param("path"))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+			if tt.expectedCode == http.StatusOK && w.Body.String() != tt.expectedTail {
+				t.Errorf("Expected tail %q, got %q", tt.expectedTail, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRouter_ParamAndWildcard(t *testing.T) {
+	tests := []struct {
+		name            string
+		opts            []RouterOption
+		path            string
+		expectedCode    int
+		expectedService string
+		expectedRest    string
+	}{
+		{"nested tail", nil, "/proxy/billing/v1/invoices", http.StatusOK, "billing", "v1/invoices"},
+		{"single segment tail", nil, "/proxy/search/query", http.StatusOK, "search", "query"},
+		{"empty tail", nil, "/proxy/billing/", http.StatusOK, "billing", ""},
+		{"static sibling wins", nil, "/proxy/billing/health", http.StatusOK, "billing", "health-check"},
+		{"static sibling backtracks to wildcard", nil, "/proxy/billing/health/deep", http.StatusOK, "billing", "health/deep"},
+		{"leading slash tail", []RouterOption{WithWildcardLeadingSlash()}, "/proxy/billing/v1/invoices", http.StatusOK, "billing", "/v1/invoices"},
+		{"missing tail", nil, "/proxy/billing", http.StatusNotFound, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter(tt.opts...)
+			router.GET("/proxy/:service/*rest", func(ctx *Context) (any, int, error) {
+				return map[string]string{"service": ctx.Param("service"), "rest": ctx.Param("rest")}, http.StatusOK, nil
+			})
+			router.GET("/proxy/:service/health", func(ctx *Context) (any, int, error) {
+				return map[string]string{"service": ctx.Param("service"), "rest": "health-check"}, http.StatusOK, nil
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Data map[string]string `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to decode body: %v", err)
+			}
+			if body.Data["service"] != tt.expectedService || body.Data["rest"] != tt.expectedRest {
+				t.Errorf("Expected service=%q rest=%q, got %v", tt.expectedService, tt.expectedRest, body.Data)
+			}
+		})
+	}
+}
+
+// TestRouter_NoMiddlewareFastPath verifies that routes served via the no-middleware fast path
+// respond identically to routes served through a compiled middleware chain.
+func TestRouter_NoMiddlewareFastPath(t *testing.T) {
+	passthrough := func(next Handler) Handler {
+		return func(ctx *Context) (any, int, error) {
+			return next(ctx)
+		}
+	}
+
+	handlers := map[string]Handler{
+		"/ok": func(ctx *Context) (any, int, error) {
+			return map[string]string{"status": "ok"}, http.StatusOK, nil
+		},
+		"/created/:id": func(ctx *Context) (any, int, error) {
+			return map[string]string{"id": ctx.Param("id")}, http.StatusCreated, nil
+		},
+		"/error": func(ctx *Context) (any, int, error) {
+			return nil, http.StatusConflict, NewAPIError("conflict", "already exists")
+		},
+		"/empty": func(ctx *Context) (any, int, error) {
+			return nil, http.StatusOK, nil
+		},
+	}
+	paths := map[string]string{"/ok": "/ok", "/created/:id": "/created/7", "/error": "/error", "/empty": "/empty"}
+
+	fast := NewRouter()
+	chained := NewRouter()
+	chained.Use(passthrough)
+	for pattern, handler := range handlers {
+		fast.AddRoute(http.MethodGet, pattern, handler)
+		chained.AddRoute(http.MethodGet, pattern, handler)
+	}
+
+	for pattern, path := range paths {
+		t.Run(pattern, func(t *testing.T) {
+			fastW := httptest.NewRecorder()
+			fast.ServeHTTP(fastW, httptest.NewRequest(http.MethodGet, path, nil))
+
+			chainedW := httptest.NewRecorder()
+			chained.ServeHTTP(chainedW, httptest.NewRequest(http.MethodGet, path, nil))
+
+			if fastW.Code != chainedW.Code {
+				t.Errorf("Status mismatch: fast path %d, chained %d", fastW.Code, chainedW.Code)
+			}
+			if fastW.Body.String() != chainedW.Body.String() {
+				t.Errorf("Body mismatch: fast path %q, chained %q", fastW.Body.String(), chainedW.Body.String())
+			}
+			if fastW.Header().Get("Content-Type") != chainedW.Header().Get("Content-Type") {
+				t.Errorf("Content-Type mismatch: fast path %q, chained %q",
+					fastW.Header().Get("Content-Type"), chainedW.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestRouter_UseRebuildsExistingChains(t *testing.T) {
+	router := NewRouter()
+
+	router.AddRoute(http.MethodGet, "/static", func(ctx *Context) (any, int, error) {
+		return map[string]any{"message": "ok"}, http.StatusOK, nil
+	})
+	router.AddRoute(http.MethodGet, "/users/:id", func(ctx *Context) (any, int, error) {
+		return map[string]any{"id": ctx.Param("id")}, http.StatusOK, nil
+	})
+
+	// Warm the chains before adding middleware
+	for _, path := range []string{"/static", "/users/1", "/missing"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	router.Use(func(next Handler) Handler {
+		return func(ctx *Context) (any, int, error) {
+			ctx.Header("X-Added-Later", "true")
+			return next(ctx)
+		}
+	})
+
+	for _, path := range []string{"/static", "/users/1", "/missing"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if w.Header().Get("X-Added-Later") != "true" {
+			t.Errorf("Expected middleware added by Use to run for %s", path)
+		}
+	}
+}
+
+func TestGroup_UseAppliesToLaterRoutesOnly(t *testing.T) {
+	router := NewRouter()
+	api := router.Group("/api")
+
+	api.AddRoute(http.MethodGet, "/before", func(ctx *Context) (any, int, error) {
+		return map[string]any{"message": "ok"}, http.StatusOK, nil
+	})
+
+	api.Use(func(next Handler) Handler {
+		return func(ctx *Context) (any, int, error) {
+			ctx.Header("X-Group", "true")
+			return next(ctx)
+		}
+	})
+
+	api.AddRoute(http.MethodGet, "/after", func(ctx *Context) (any, int, error) {
+		return map[string]any{"message": "ok"}, http.StatusOK, nil
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/before", nil))
+	if w.Header().Get("X-Group") != "" {
+		t.Error("Group middleware should not apply to routes registered before Use")
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/after", nil))
+	if w.Header().Get("X-Group") != "true" {
+		t.Error("Expected group middleware to apply to routes registered after Use")
+	}
+}
+
+func TestGroup_RoutesDoNotShareMiddlewareSlice(t *testing.T) {
+	router := NewRouter()
+
+	tag := func(value string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx *Context) (any, int, error) {
+				ctx.Header("X-Route", value)
+				return next(ctx)
+			}
+		}
+	}
+
+	api := router.Group("/api")
+	api.Use(tag("group"), tag("group")) // grow the slice so it has spare capacity
+	api.Use(tag("group"))
+
+	ok := func(ctx *Context) (any, int, error) {
+		return map[string]any{"message": "ok"}, http.StatusOK, nil
+	}
+	api.AddRoute(http.MethodGet, "/a", ok, tag("a"))
+	api.AddRoute(http.MethodGet, "/b", ok, tag("b"))
+
+	// Rebuilding chains re-reads each route's middleware slice
+	router.Use(tag("global"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/a", nil))
+	if got := w.Header().Get("X-Route"); got != "a" {
+		t.Errorf("Expected route middleware 'a' for /api/a, got %q", got)
+	}
+}
+
+func TestRouter_MethodShortcuts(t *testing.T) {
+	router := NewRouter()
+	api := router.Group("/api")
+
+	respond := func(name string) Handler {
+		return func(ctx *Context) (any, int, error) {
+			return map[string]any{"handler": name}, http.StatusOK, nil
+		}
+	}
+
+	router.GET("/items", respond("router-get"))
+	router.POST("/items", respond("router-post"))
+	router.PUT("/items/:id", respond("router-put"))
+	router.PATCH("/items/:id", respond("router-patch"))
+	router.DELETE("/items/:id", respond("router-delete"))
+
+	api.GET("/items", respond("group-get"))
+	api.POST("/items", respond("group-post"))
+	api.PUT("/items/:id", respond("group-put"))
+	api.PATCH("/items/:id", respond("group-patch"))
+	api.DELETE("/items/:id", respond("group-delete"))
+
+	tests := []struct {
+		method  string
+		path    string
+		handler string
+	}{
+		{http.MethodGet, "/items", "router-get"},
+		{http.MethodPost, "/items", "router-post"},
+		{http.MethodPut, "/items/1", "router-put"},
+		{http.MethodPatch, "/items/1", "router-patch"},
+		{http.MethodDelete, "/items/1", "router-delete"},
+		{http.MethodGet, "/api/items", "group-get"},
+		{http.MethodPost, "/api/items", "group-post"},
+		{http.MethodPut, "/api/items/1", "group-put"},
+		{http.MethodPatch, "/api/items/1", "group-patch"},
+		{http.MethodDelete, "/api/items/1", "group-delete"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", w.Code)
+			}
+
+			var response SuccessResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			if data, _ := response.Data.(map[string]any); data["handler"] != tt.handler {
+				t.Errorf("Expected handler %s, got %v", tt.handler, response.Data)
+			}
+		})
+	}
+
+	// Methods without a registered route fall through to the 404 handler
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodDelete, "/items", nil),
+		httptest.NewRequest(http.MethodGet, "/items/1", nil),
+		httptest.NewRequest(http.MethodPut, "/api/items", nil),
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for %s %s, got %d", req.Method, req.URL.Path, w.Code)
+		}
+	}
+}
+
+func TestRouter_WithMiddlewareOption(t *testing.T) {
+	router := NewRouter()
+
+	var order []string
+	record := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx *Context) (any, int, error) {
+				order = append(order, name)
+				return next(ctx)
+			}
+		}
+	}
+
+	api := router.Group("/api", record("group"))
+	api.GET("/users", func(ctx *Context) (any, int, error) {
+		order = append(order, "handler")
+		return map[string]any{"users": []string{}}, http.StatusOK, nil
+	}, WithMiddleware(record("first")), WithMiddleware(record("second")))
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/users", nil))
+
+	expected := []string{"group", "first", "second", "handler"}
+	if len(order) != len(expected) {
+		t.Fatalf("Expected order %v, got %v", expected, order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Errorf("Expected order %v, got %v", expected, order)
+			break
+		}
+	}
+}
+
+func TestRouter_WithoutEnvelope(t *testing.T) {
+	router := NewRouter()
+
+	item := func(ctx *Context) (any, int, error) {
+		return map[string]any{"id": 1, "name": "widget"}, http.StatusOK, nil
+	}
+	router.GET("/enveloped", item)
+	router.GET("/bare", item, WithoutEnvelope())
+	router.GET("/bare-error", func(ctx *Context) (any, int, error) {
+		return nil, http.StatusNotFound, NewAPIError("not_found", "Item not found")
+	}, WithoutEnvelope())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/enveloped", nil))
+	if body := strings.TrimSpace(w.Body.String()); body != `{"success":true,"data":{"id":1,"name":"widget"}}` {
+		t.Errorf("Expected enveloped body, got %s", body)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/bare", nil))
+	if body := strings.TrimSpace(w.Body.String()); body != `{"id":1,"name":"widget"}` {
+		t.Errorf("Expected bare body, got %s", body)
+	}
+	if w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Expected JSON content type, got %q", w.Header().Get("Content-Type"))
+	}
+
+	// Errors keep the standard error shape
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/bare-error", nil))
+	var errResp ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusNotFound || errResp.Error != "not_found" {
+		t.Errorf("Expected 404 not_found error response, got %d %+v", w.Code, errResp)
+	}
+}
+
+func TestRouter_BraceParams(t *testing.T) {
+	router := NewRouter(WithBraceParams())
+
+	router.GET("/users/{id}", func(ctx *Context) (any, int, error) {
+		return map[string]any{"id": ctx.Param("id")}, http.StatusOK, nil
+	})
+	router.GET("/posts/{slug}/comments/:cid", func(ctx *Context) (any, int, error) {
+		return map[string]any{"slug": ctx.Param("slug"), "cid": ctx.Param("cid")}, http.StatusOK, nil
+	})
+	router.GET("/files/{*path}", func(ctx *Context) (any, int, error) {
+		return map[string]any{"path": ctx.Param("path")}, http.StatusOK, nil
+	})
+
+	tests := []struct {
+		path     string
+		expected map[string]any
+	}{
+		{"/users/42", map[string]any{"id": "42"}},
+		{"/posts/hello/comments/7", map[string]any{"slug": "hello", "cid": "7"}},
+		{"/files/docs/readme.md", map[string]any{"path": "docs/readme.md"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", w.Code)
+			}
+
+			var response SuccessResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			data, _ := response.Data.(map[string]any)
+			for key, value := range tt.expected {
+				if data[key] != value {
+					t.Errorf("Expected %s=%v, got %v", key, value, data[key])
+				}
+			}
+		})
+	}
+}
+
+func TestRouter_BraceParams_InvalidSegmentPanics(t *testing.T) {
+	for _, path := range []string{"/files/{name}.json", "/files/{*}", "/users/{id"} {
+		t.Run(path, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected panic for %s", path)
+				}
+			}()
+
+			NewRouter(WithBraceParams()).GET(path, func(ctx *Context) (any, int, error) {
+				return nil, http.StatusOK, nil
+			})
+		})
+	}
+}
+
+func TestRouter_BracesLiteralWithoutOption(t *testing.T) {
+	router := NewRouter()
+	router.GET("/users/{id}", func(ctx *Context) (any, int, error) {
+		return map[string]any{"literal": true}, http.StatusOK, nil
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 without WithBraceParams, got %d", w.Code)
+	}
+}
+
+func TestRouter_Walk(t *testing.T) {
+	router := NewRouter()
+	router.Use(func(next Handler) Handler { return next })
+
+	handler := func(ctx *Context) (any, int, error) { return nil, http.StatusOK, nil }
+	auth := func(next Handler) Handler { return next }
+
+	router.GET("/health", handler)
+	router.GET("/users/:id", handler)
+	router.POST("/users", handler)
+	router.DELETE("/users/:id", handler)
+	router.GET("/files/*path", handler)
+	router.GET("/health", handler) // re-registered: still one route
+
+	admin := router.Group("/admin", auth)
+	admin.GET("/stats", handler)
+	admin.POST("/users/:id/ban", handler, WithMiddleware(auth))
+
+	router.NotFound(handler)
+
+	var visited []string
+	router.Walk(func(method, pattern string, route *Route) {
+		if route.Method() != method || route.Pattern() != pattern {
+			t.Errorf("Expected route %s %s, got %s %s", method, pattern, route.Method(), route.Pattern())
+		}
+		if strings.HasPrefix(pattern, "/admin") && len(route.Middlewares()) == 0 {
+			t.Errorf("Expected %s %s to have the group's auth middleware", method, pattern)
+		}
+		visited = append(visited, method+" "+pattern)
+	})
+
+	expected := []string{
+		"DELETE /users/:id",
+		"GET /admin/stats",
+		"GET /files/*path",
+		"GET /health",
+		"GET /users/:id",
+		"POST /admin/users/:id/ban",
+		"POST /users",
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("Expected Walk to visit %v, got %v", expected, visited)
+	}
+
+	// Route middleware excludes global middleware
+	router.Walk(func(method, pattern string, route *Route) {
+		want := 0
+		switch pattern {
+		case "/admin/stats":
+			want = 1
+		case "/admin/users/:id/ban":
+			want = 2
+		}
+		if got := len(route.Middlewares()); got != want {
+			t.Errorf("Expected %d middleware on %s %s, got %d", want, method, pattern, got)
+		}
+	})
+}
+
+func TestRouter_Walk_RegisterDuringWalk(t *testing.T) {
+	router := NewRouter()
+	handler := func(ctx *Context) (any, int, error) { return nil, http.StatusOK, nil }
+	router.GET("/a", handler)
+
+	count := 0
+	router.Walk(func(method, pattern string, route *Route) {
+		count++
+		router.GET(pattern+"/copy", handler)
+	})
+
+	if count != 1 {
+		t.Errorf("Expected routes registered during Walk not to be visited, got %d visits", count)
+	}
+}
+
+func TestRouter_MountRouter(t *testing.T) {
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx *Context) (any, int, error) {
+				order = append(order, name)
+				return next(ctx)
+			}
+		}
+	}
+
+	v1 := NewRouter()
+	v1.Use(tag("child"))
+	v1.GET("/users/:id", func(ctx *Context) (any, int, error) {
+		return map[string]string{"id": ctx.Param("id")}, http.StatusOK, nil
+	}, WithMiddleware(tag("route")))
+	v1.GET("/health", func(ctx *Context) (any, int, error) {
+		return map[string]string{"status": "ok"}, http.StatusOK, nil
+	}, WithoutEnvelope())
+
+	cleaned := false
+	v1.RegisterCleanup(func() { cleaned = true })
+
+	app := NewRouter()
+	app.Use(tag("parent"))
+	app.GET("/", func(ctx *Context) (any, int, error) {
+		return nil, http.StatusOK, nil
+	})
+	app.MountRouter("/v1/", v1)
+
+	// Routes added to the child after mounting are not served by the parent
+	v1.GET("/late", func(ctx *Context) (any, int, error) { return nil, http.StatusOK, nil })
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users/42", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"id":"42"`) {
+		t.Errorf("Expected path param from mounted route, got %s", w.Body.String())
+	}
+	if expected := []string{"parent", "child", "route"}; !slices.Equal(order, expected) {
+		t.Errorf("Expected middleware order %v, got %v", expected, order)
+	}
+
+	// Route options survive mounting
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/health", nil))
+	if strings.TrimSpace(w.Body.String()) != `{"status":"ok"}` {
+		t.Errorf("Expected unenveloped body, got %s", w.Body.String())
+	}
+
+	for _, path := range []string{"/users/42", "/v1/late", "/v1"} {
+		w = httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected %s to be 404, got %d", path, w.Code)
+		}
+	}
+
+	// The child router keeps working on its own
+	order = nil
+	w = httptest.NewRecorder()
+	v1.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/7", nil))
+	if w.Code != http.StatusOK || !slices.Equal(order, []string{"child", "route"}) {
+		t.Errorf("Expected child router to serve /users/7 with its own middleware, got %d %v", w.Code, order)
+	}
+
+	app.Shutdown()
+	if !cleaned {
+		t.Error("Expected parent Shutdown to run the child's cleanup functions")
+	}
+}
+
+func TestRouter_MountRouterOptions(t *testing.T) {
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx *Context) (any, int, error) {
+				order = append(order, name)
+				return next(ctx)
+			}
+		}
+	}
+
+	child := NewRouter(WithoutSuccessEnvelope())
+	child.Use(tag("child-early"))
+	child.AddCompiledRoute(http.MethodGet, "/compiled", func(ctx *Context) (any, int, error) {
+		return map[string]string{"status": "ok"}, http.StatusOK, nil
+	})
+	child.Use(tag("child-late"))
+	child.Default(func(ctx *Context) (any, int, error) {
+		return "child default", http.StatusOK, nil
+	})
+
+	app := NewRouter()
+	app.Use(tag("parent"))
+	app.MountRouter("/v1", child)
+
+	// The compiled chain is kept, so middleware added to the child after it doesn't run
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/compiled", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if expected := []string{"parent", "child-early"}; !slices.Equal(order, expected) {
+		t.Errorf("Expected middleware order %v, got %v", expected, order)
+	}
+
+	// The child's WithoutSuccessEnvelope applies to its mounted routes
+	if strings.TrimSpace(w.Body.String()) != `{"status":"ok"}` {
+		t.Errorf("Expected unenveloped body, got %s", w.Body.String())
+	}
+
+	// The child's Default route is not mounted
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/missing", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected the parent's 404 for /v1/missing, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestRouter_RawResponseData(t *testing.T) {
+	router := NewRouter()
+	router.GET("/bytes", func(ctx *Context) (any, int, error) {
+		return []byte(`{"cached":true}`), http.StatusOK, nil
+	})
+	router.GET("/json-bytes", func(ctx *Context) (any, int, error) {
+		ctx.Header("Content-Type", "application/json")
+		return []byte(`{"cached":true}`), http.StatusOK, nil
+	})
+	router.GET("/string", func(ctx *Context) (any, int, error) {
+		return "id,name\n1,widget\n", http.StatusCreated, nil
+	})
+	router.GET("/reader", func(ctx *Context) (any, int, error) {
+		ctx.Header("Content-Type", "text/csv")
+		return strings.NewReader("id,name\n"), http.StatusOK, nil
+	})
+	router.GET("/struct", func(ctx *Context) (any, int, error) {
+		return struct {
+			Name string `json:"name"`
+		}{"widget"}, http.StatusOK, nil
+	})
+
+	tests := []struct {
+		path        string
+		status      int
+		contentType string
+		body        string
+	}{
+		{"/bytes", http.StatusOK, "text/plain; charset=utf-8", `{"cached":true}`},
+		{"/json-bytes", http.StatusOK, "application/json", `{"cached":true}`},
+		{"/string", http.StatusCreated, "text/plain; charset=utf-8", "id,name\n1,widget\n"},
+		{"/reader", http.StatusOK, "text/csv", "id,name\n"},
+		{"/struct", http.StatusOK, "application/json", `{"success":true,"data":{"name":"widget"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Expected Content-Type %q, got %q", tt.contentType, got)
+			}
+			if got := strings.TrimSpace(w.Body.String()); got != strings.TrimSpace(tt.body) {
+				t.Errorf("Expected body %q, got %q", tt.body, got)
+			}
+		})
+	}
+}
+
+// failingReadCloser returns some data, then fails, recording whether it was closed
+type failingReadCloser struct {
+	sent   bool
+	closed bool
+}
+
+func (r *failingReadCloser) Read(p []byte) (int, error) {
+	if r.sent {
+		return 0, errors.New("disk read failed")
+	}
+	r.sent = true
+	return copy(p, "id,name\n"), nil
+}
+
+func (r *failingReadCloser) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestRouter_RawResponseReaderError(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	reader := &failingReadCloser{}
+	router := NewRouter()
+	router.GET("/export", func(ctx *Context) (any, int, error) {
+		return reader, http.StatusOK, nil
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export", nil))
+
+	if w.Body.String() != "id,name\n" {
+		t.Errorf("Expected the data read before the error, got %q", w.Body.String())
+	}
+	if !strings.Contains(buf.String(), "GET /export response body: disk read failed") {
+		t.Errorf("Expected the copy error to be logged, got %q", buf.String())
+	}
+	if !reader.closed {
+		t.Error("Expected the reader to be closed")
+	}
+}
+
+func TestRouter_ChannelStreamsChunks(t *testing.T) {
+	release := make(chan struct{})
+
+	router := NewRouter()
+	router.GET("/export", func(ctx *Context) (any, int, error) {
+		ctx.Header("Content-Type", "text/csv")
+		chunks := make(chan []byte)
+		go func() {
+			defer close(chunks)
+			chunks <- []byte("id,name\n")
+			<-release // The client must see the first chunk before the rest is produced
+			chunks <- []byte("1,widget\n")
+			chunks <- []byte("2,gadget\n")
+		}()
+		return (<-chan []byte)(chunks), http.StatusOK, nil
+	})
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "text/csv" {
+		t.Errorf("Expected Content-Type text/csv, got %q", got)
+	}
+	if len(resp.TransferEncoding) != 1 || resp.TransferEncoding[0] != "chunked" {
+		t.Errorf("Expected chunked transfer encoding, got %v", resp.TransferEncoding)
+	}
+
+	first := make([]byte, len("id,name\n"))
+	if _, err := io.ReadFull(resp.Body, first); err != nil {
+		t.Fatalf("Expected the first chunk to be flushed, got %v", err)
+	}
+	if string(first) != "id,name\n" {
+		t.Errorf("Expected first chunk %q, got %q", "id,name\n", first)
+	}
+
+	close(release)
+	rest, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(rest) != "1,widget\n2,gadget\n" {
+		t.Errorf("Expected remaining chunks, got %q", rest)
+	}
+}
+
+func TestRouter_ChannelStreamDefaults(t *testing.T) {
+	router := NewRouter()
+	router.GET("/stream", func(ctx *Context) (any, int, error) {
+		chunks := make(chan []byte, 2)
+		chunks <- []byte("a")
+		chunks <- []byte("b")
+		close(chunks)
+		return chunks, http.StatusAccepted, nil
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream", nil))
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Expected status 202, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/octet-stream" {
+		t.Errorf("Expected Content-Type application/octet-stream, got %q", got)
+	}
+	if !w.Flushed {
+		t.Error("Expected the response to be flushed")
+	}
+	if w.Body.String() != "ab" {
+		t.Errorf("Expected body %q, got %q", "ab", w.Body.String())
+	}
+}
+
+func TestRouter_WithoutSuccessEnvelope(t *testing.T) {
+	register := func(router *Router) {
+		router.GET("/items/:id", func(ctx *Context) (any, int, error) {
+			return map[string]any{"id": ctx.Param("id")}, http.StatusOK, nil
+		})
+		router.POST("/items", func(ctx *Context) (any, int, error) {
+			return []string{"a", "b"}, http.StatusCreated, nil
+		})
+		router.GET("/missing", func(ctx *Context) (any, int, error) {
+			return nil, http.StatusNotFound, NewAPIError("not_found", "Item not found")
+		})
+	}
+
+	tests := []struct {
+		name     string
+		router   *Router
+		expected map[string]string
+	}{
+		{"disabled", NewRouter(WithoutSuccessEnvelope()), map[string]string{
+			"GET /items/1": `{"id":"1"}`,
+			"POST /items":  `["a","b"]`,
+		}},
+		{"enabled by default", NewRouter(), map[string]string{
+			"GET /items/1": `{"success":true,"data":{"id":"1"}}`,
+			"POST /items":  `{"success":true,"data":["a","b"]}`,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			register(tt.router)
+
+			for request, expected := range tt.expected {
+				method, path, _ := strings.Cut(request, " ")
+				w := httptest.NewRecorder()
+				tt.router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+
+				if body := strings.TrimSpace(w.Body.String()); body != expected {
+					t.Errorf("%s: expected %s, got %s", request, expected, body)
+				}
+			}
+
+			// Errors keep the standard error shape either way
+			w := httptest.NewRecorder()
+			tt.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+			var errResp ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
+				t.Fatal(err)
+			}
+			if w.Code != http.StatusNotFound || errResp.Error != "not_found" {
+				t.Errorf("Expected 404 not_found error response, got %d %+v", w.Code, errResp)
+			}
+		})
+	}
+}
+
+func TestRouter_Default(t *testing.T) {
+	router := NewRouter()
+
+	var seen []string
+	router.Use(func(next Handler) Handler {
+		return func(ctx *Context) (any, int, error) {
+			seen = append(seen, ctx.Request.URL.Path)
+			return next(ctx)
+		}
+	})
+	router.AddRoute(http.MethodGet, "/api/users", func(ctx *Context) (any, int, error) {
+		return map[string]string{"route": "users"}, http.StatusOK, nil
+	})
+	router.NotFound(func(ctx *Context) (any, int, error) {
+		return nil, http.StatusNotFound, NewAPIError("api_not_found", "no such endpoint")
+	})
+	router.Default(func(ctx *Context) (any, int, error) {
+		if ctx.Request.Method != http.MethodGet || strings.HasPrefix(ctx.Request.URL.Path, "/api/") {
+			return nil, 0, ErrRouteNotFound
+		}
+		if ctx.Route() == nil || ctx.Route().Pattern() != "*" {
+			t.Errorf("Expected catch-all route in context, got %v", ctx.Route())
+		}
+		return ctx.HTML(http.StatusOK, "<html>app</html>")
+	})
+
+	t.Run("unmatched GET serves default", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/settings/profile", nil))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		if w.Body.String() != "<html>app</html>" {
+			t.Errorf("Expected index HTML, got %q", w.Body.String())
+		}
+	})
+
+	t.Run("unmatched API path falls through to NotFound", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/missing", nil))
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("Expected status 404, got %d", w.Code)
+		}
+		var body ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Failed to decode body: %v", err)
+		}
+		if body.Error != "api_not_found" {
+			t.Errorf("Expected custom NotFound error, got %q", body.Error)
+		}
+	})
+
+	t.Run("unmatched method reaches default", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/settings", nil))
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("matched routes are unaffected", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "users") {
+			t.Errorf("Expected users route, got %d %s", w.Code, w.Body.String())
+		}
+	})
+
+	want := []string{"/settings/profile", "/api/missing", "/settings", "/api/users"}
+	if !slices.Equal(seen, want) {
+		t.Errorf("Expected global middleware to run once per request %v, got %v", want, seen)
+	}
+}
+
+func TestRouter_AddCompiledRoute(t *testing.T) {
+	logging := func(next Handler) Handler {
+		return func(ctx *Context) (any, int, error) {
+			ctx.Header("X-Global", "1")
+			return next(ctx)
+		}
+	}
+	guard := func(next Handler) Handler {
+		return func(ctx *Context) (any, int, error) {
+			if ctx.Query("deny") != "" {
+				return ctx.AbortWithError(http.StatusForbidden, NewAPIError("forbidden", "denied"))
+			}
+			return next(ctx)
+		}
+	}
+	handler := func(ctx *Context) (any, int, error) {
+		return map[string]string{"id": ctx.Param("id")}, http.StatusOK, nil
+	}
+
+	standard := NewRouter()
+	standard.Use(logging)
+	standard.GET("/users/:id", handler, WithMiddleware(guard))
+
+	compiled := NewRouter()
+	compiled.Use(logging)
+	compiled.AddCompiledRoute(http.MethodGet, "/users/:id", handler, WithMiddleware(guard))
+
+	for _, target := range []string{"/users/42", "/users/42?deny=1", "/users"} {
+		t.Run(target, func(t *testing.T) {
+			want := httptest.NewRecorder()
+			standard.ServeHTTP(want, httptest.NewRequest(http.MethodGet, target, nil))
+			got := httptest.NewRecorder()
+			compiled.ServeHTTP(got, httptest.NewRequest(http.MethodGet, target, nil))
+
+			if got.Code != want.Code || got.Body.String() != want.Body.String() {
+				t.Errorf("Expected %d %s, got %d %s", want.Code, want.Body.String(), got.Code, got.Body.String())
+			}
+			if got.Header().Get("X-Global") != want.Header().Get("X-Global") {
+				t.Errorf("Expected X-Global %q, got %q", want.Header().Get("X-Global"), got.Header().Get("X-Global"))
+			}
+		})
+	}
+
+	// Middleware added after compilation doesn't reach the compiled route
+	later := false
+	compiled.Use(func(next Handler) Handler {
+		return func(ctx *Context) (any, int, error) {
+			later = true
+			return next(ctx)
+		}
+	})
+	compiled.GET("/health", func(ctx *Context) (any, int, error) {
+		return nil, http.StatusNoContent, nil
+	})
+
+	compiled.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	if later {
+		t.Error("Expected middleware added with Use after compilation to be skipped")
+	}
+	compiled.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	if !later {
+		t.Error("Expected later middleware to apply to regular routes")
+	}
+}
+
+func TestRouter_SetBasePath(t *testing.T) {
+	router := NewRouter()
+	router.SetBasePath("/api/")
+	router.GET("/", func(ctx *Context) (any, int, error) {
+		return "root", http.StatusOK, nil
+	})
+	router.GET("/users/:id", func(ctx *Context) (any, int, error) {
+		return map[string]string{"id": ctx.Param("id"), "path": ctx.Request.URL.Path}, http.StatusOK, nil
+	})
+	router.GET("/health", func(ctx *Context) (any, int, error) {
+		return "ok", http.StatusOK, nil
+	})
+
+	tests := []struct {
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{"/api/users/5", http.StatusOK, `"id":"5","path":"/api/users/5"`},
+		{"/api/health", http.StatusOK, "ok"},
+		{"/api", http.StatusOK, "root"},
+		{"/api/", http.StatusOK, "root"},
+		{"/users/5", http.StatusNotFound, ""},
+		{"/health", http.StatusNotFound, ""},
+		{"/apihealth", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+			if !strings.Contains(w.Body.String(), tt.expectedBody) {
+				t.Errorf("Expected body to contain %s, got %s", tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+
+	spec := router.GenerateOpenAPI(OpenAPIConfig{Title: "Test", Version: "1.0.0"})
+	if _, ok := spec.Paths["/api/users/{id}"]; !ok {
+		t.Errorf("Expected OpenAPI paths to include the base path, got %v", slices.Collect(maps.Keys(spec.Paths)))
+	}
+}
+
+func TestRouter_URL(t *testing.T) {
+	router := NewRouter()
+
+	tests := []struct {
+		basePath string
+		pattern  string
+		params   map[string]string
+		expected string
+	}{
+		{"", "/users/:id", map[string]string{"id": "5"}, "/users/5"},
+		{"/api", "/users/:id", map[string]string{"id": "5"}, "/api/users/5"},
+		{"/api", "/", nil, "/api"},
+		{"/api", "/users/:id/posts/:post", map[string]string{"id": "John Doe", "post": "a/b"}, "/api/users/John%20Doe/posts/a%2Fb"},
+		{"/api", "/files/*path", map[string]string{"path": "docs/readme.md"}, "/api/files/docs/readme.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.basePath+tt.pattern, func(t *testing.T) {
+			router.SetBasePath(tt.basePath)
+
+			url, err := router.URL(tt.pattern, tt.params)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if url != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, url)
+			}
+		})
+	}
+
+	if _, err := router.URL("/users/:id", nil); err == nil {
+		t.Error("Expected error for missing parameter")
+	}
+}
+
+func TestRouter_PercentDecodedParams(t *testing.T) {
+	router := NewRouter()
+	router.GET("/users/:name/files/:file", func(ctx *Context) (any, int, error) {
+		return map[string]string{
+			"name":     ctx.Param("name"),
+			"file":     ctx.Param("file"),
+			"raw_file": ctx.RawParam("file"),
+		}, http.StatusOK, nil
+	})
+	router.GET("/static/*path", func(ctx *Context) (any, int, error) {
+		return map[string]string{"path": ctx.Param("path")}, http.StatusOK, nil
+	})
+
+	tests := []struct {
+		name     string
+		path     string
+		expected map[string]string
+	}{
+		{"encoded space", "/users/John%20Doe/files/notes", map[string]string{"name": "John Doe", "file": "notes", "raw_file": "notes"}},
+		{"encoded slash", "/users/ada/files/2024%2Freport.pdf", map[string]string{"name": "ada", "file": "2024/report.pdf", "raw_file": "2024%2Freport.pdf"}},
+		{"encoded slash and space", "/users/John%20Doe/files/a%2Fb", map[string]string{"name": "John Doe", "file": "a/b", "raw_file": "a%2Fb"}},
+		{"encoded slash in wildcard", "/static/css/a%2Fb.css", map[string]string{"path": "css/a/b.css"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", w.Code)
+			}
+			var body struct {
+				Data map[string]string `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to decode body: %v", err)
+			}
+			for k, v := range tt.expected {
+				if body.Data[k] != v {
+					t.Errorf("Expected %s=%q, got %q", k, v, body.Data[k])
+				}
+			}
+		})
+	}
+
+	// Matching happens on the raw path: the encoded slash doesn't split the segment, so this
+	// is /users/:name with nothing after it rather than /users/ada/files/x
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/ada%2Ffiles/x", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an encoded slash between segments, got %d", w.Code)
+	}
+}
+
+func TestRouter_EncodedSlashSkipsStaticRoute(t *testing.T) {
+	router := NewRouter()
+	router.GET("/files/secret", func(ctx *Context) (any, int, error) {
+		return "secret", http.StatusOK, nil
+	})
+	router.GET("/files/:name", func(ctx *Context) (any, int, error) {
+		return "file " + ctx.Param("name"), http.StatusOK, nil
+	})
+
+	// The raw path is /files%2Fsecret, a single segment, so the static route can't match it
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files%2Fsecret", nil))
+	if w.Body.String() == "secret" {
+		t.Error("Expected /files%2Fsecret not to reach the static /files/secret route")
+	}
+
+	// An encoded slash inside the segment reaches the param route, not the static one
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files/secret%2Fx", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "file secret/x" {
+		t.Errorf("Expected the param route with name secret/x, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestDecodePathParams_InvalidEscapeLeftRaw(t *testing.T) {
+	params := map[string]string{"ok": "a%2Fb", "bad": "100%"}
+	raw := decodePathParams(params)
+
+	if params["ok"] != "a/b" || params["bad"] != "100%" {
+		t.Errorf("Expected decoded ok and raw bad, got %v", params)
+	}
+	if raw["ok"] != "a%2Fb" {
+		t.Errorf("Expected raw values to be kept, got %v", raw)
+	}
+}
+
+func TestRouter_Static(t *testing.T) {
+	files := fstest.MapFS{
+		"app.js":          {Data: []byte("console.log('hi')")},
+		"docs/index.html": {Data: []byte("<html>docs</html>")},
+		"empty/.keep":     {Data: nil},
+	}
+
+	router := NewRouter()
+	router.Static("/assets", http.FS(files))
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		status      int
+		contentType string
+		body        string
+	}{
+		{"file", http.MethodGet, "/assets/app.js", http.StatusOK, "text/javascript; charset=utf-8", "console.log('hi')"},
+		{"head", http.MethodHead, "/assets/app.js", http.StatusOK, "text/javascript; charset=utf-8", ""},
+		{"directory index", http.MethodGet, "/assets/docs/", http.StatusOK, "text/html; charset=utf-8", "<html>docs</html>"},
+		{"traversal stays inside root", http.MethodGet, "/assets/../../app.js", http.StatusOK, "text/javascript; charset=utf-8", "console.log('hi')"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Expected Content-Type %q, got %q", tt.contentType, got)
+			}
+			if w.Body.String() != tt.body {
+				t.Errorf("Expected body %q, got %q", tt.body, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRouter_StaticMissesUseNotFound(t *testing.T) {
+	files := fstest.MapFS{
+		"app.js":      {Data: []byte("console.log('hi')")},
+		"empty/.keep": {Data: nil},
+	}
+
+	for _, path := range []string{"/assets/missing.js", "/assets/empty/", "/assets/"} {
+		t.Run("default NotFound "+path, func(t *testing.T) {
+			router := NewRouter()
+			router.Static("/assets", http.FS(files))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+			if w.Code != http.StatusNotFound {
+				t.Fatalf("Expected status 404, got %d", w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Expected a JSON 404, got Content-Type %q", got)
+			}
+			var body ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Expected JSON error envelope, got %q", w.Body.String())
+			}
+			if body.Error != "not_found" {
+				t.Errorf("Expected not_found error envelope, got %+v", body)
+			}
+		})
+	}
+
+	t.Run("custom NotFound", func(t *testing.T) {
+		router := NewRouter()
+		router.Static("/assets", http.FS(files))
+		router.NotFound(func(ctx *Context) (any, int, error) {
+			return nil, http.StatusNotFound, NewAPIError("asset_not_found", "no such asset")
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/assets/missing.js", nil))
+
+		var body ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Failed to decode body: %v", err)
+		}
+		if w.Code != http.StatusNotFound || body.Error != "asset_not_found" {
+			t.Errorf("Expected custom NotFound response, got %d %+v", w.Code, body)
+		}
+	})
+}
+
+func TestRouter_MethodNotAllowed(t *testing.T) {
+	newRouter := func(opts ...RouterOption) *Router {
+		router := NewRouter(opts...)
+		router.Use(func(next Handler) Handler {
+			return func(ctx *Context) (any, int, error) {
+				ctx.Set(ContextKeyRequestID, "req-123")
+				return next(ctx)
+			}
+		})
+		handler := func(ctx *Context) (any, int, error) { return "ok", http.StatusOK, nil }
+		router.GET("/users", handler)
+		router.POST("/users", handler)
+		router.GET("/users/:id", handler)
+		router.DELETE("/users/:id", handler)
+		return router
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newRouter().ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/users", nil))
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		allow  string
+	}{
+		{http.MethodPut, "/users", http.StatusMethodNotAllowed, "GET, POST"},
+		{http.MethodPatch, "/users/42", http.StatusMethodNotAllowed, "DELETE, GET"},
+		{http.MethodGet, "/users/42", http.StatusOK, ""},
+		{http.MethodPut, "/orders", http.StatusNotFound, ""},
+	}
+
+	router := newRouter(WithMethodNotAllowed(), WithRequestIDInBody())
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, w.Code)
+			}
+			if got := w.Header().Get("Allow"); got != tt.allow {
+				t.Errorf("Expected Allow %q, got %q", tt.allow, got)
+			}
+			if tt.status == http.StatusOK {
+				return
+			}
+
+			var body ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Expected ErrorResponse JSON, got %q", w.Body.String())
+			}
+			expected := ErrRouteNotFound.Code
+			if tt.status == http.StatusMethodNotAllowed {
+				expected = ErrMethodNotAllowed.Code
+			}
+			if body.Error != expected || body.Code != tt.status {
+				t.Errorf("Expected %s error with code %d, got %+v", expected, tt.status, body)
+			}
+			if body.Meta == nil || body.Meta.RequestID != "req-123" {
+				t.Errorf("Expected meta.request_id req-123, got %+v", body.Meta)
+			}
+		})
+	}
+}
+
+func TestRouter_ErrorFormatter(t *testing.T) {
+	router := NewRouter(WithMethodNotAllowed(), WithErrorFormatter(func(ctx *Context, status int, err error) any {
+		ctx.Header("Content-Type", "application/problem+json")
+		problem := map[string]any{
+			"status":     status,
+			"title":      http.StatusText(status),
+			"detail":     err.Error(),
+			"request_id": ctx.GetString(ContextKeyRequestID),
+		}
+		if apiErr, ok := err.(*APIError); ok {
+			problem["type"] = apiErr.Code
+		}
+		return problem
+	}))
+	router.Use(func(next Handler) Handler {
+		return func(ctx *Context) (any, int, error) {
+			ctx.Set(ContextKeyRequestID, "req-123")
+			return next(ctx)
+		}
+	})
+	router.GET("/orders/:id", func(ctx *Context) (any, int, error) {
+		return nil, http.StatusConflict, NewAPIError("order_locked", "Order is locked")
+	})
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		status      int
+		contentType string
+		problemType string
+	}{
+		{"not found", http.MethodGet, "/missing", http.StatusNotFound, "application/problem+json", "not_found"},
+		{"method not allowed", http.MethodPost, "/orders/1", http.StatusMethodNotAllowed, "application/problem+json", "method_not_allowed"},
+		{"handler error", http.MethodGet, "/orders/1", http.StatusConflict, "application/problem+json", "order_locked"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Expected Content-Type %q, got %q", tt.contentType, got)
+			}
+
+			var problem map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("Failed to decode body %q: %v", w.Body.String(), err)
+			}
+			if problem["type"] != tt.problemType || problem["status"] != float64(tt.status) {
+				t.Errorf("Expected %s problem with status %d, got %v", tt.problemType, tt.status, problem)
+			}
+			if problem["request_id"] != "req-123" {
+				t.Errorf("Expected request_id req-123, got %v", problem["request_id"])
+			}
+		})
+	}
+}
+
+func TestRouter_HeadersKeptOnErrorResponses(t *testing.T) {
+	for _, opts := range [][]RouterOption{nil, {WithErrorFormatter(func(ctx *Context, status int, err error) any {
+		return map[string]string{"detail": err.Error()}
+	})}} {
+		router := NewRouter(opts...)
+		router.Use(func(next Handler) Handler {
+			return func(ctx *Context) (any, int, error) {
+				ctx.Header("X-Trace-Id", "trace-1")
+				return next(ctx)
+			}
+		})
+		router.GET("/ok", func(ctx *Context) (any, int, error) {
+			ctx.Header("Cache-Control", "no-store")
+			return "ok", http.StatusOK, nil
+		})
+		router.GET("/fail", func(ctx *Context) (any, int, error) {
+			ctx.Header("Cache-Control", "no-store")
+			return nil, http.StatusInternalServerError, errors.New("database unavailable")
+		})
+
+		for _, path := range []string{"/ok", "/fail"} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+			if w.Header().Get("X-Trace-Id") != "trace-1" || w.Header().Get("Cache-Control") != "no-store" {
+				t.Errorf("Expected middleware and handler headers on %s (%d), got %v", path, w.Code, w.Header())
+			}
+		}
+	}
+}
+
+func TestRouter_ReturnedValidationErrors(t *testing.T) {
+	insufficientFunds := ValidationErrors{{
+		Field:   "amount",
+		Value:   500,
+		Tag:     "balance",
+		Message: "amount exceeds the available balance",
+	}}
+
+	tests := []struct {
+		name     string
+		status   int
+		err      error
+		expected int
+	}{
+		{"default status", 0, insufficientFunds, http.StatusUnprocessableEntity},
+		{"server error status", http.StatusInternalServerError, insufficientFunds, http.StatusUnprocessableEntity},
+		{"explicit 4xx kept", http.StatusConflict, insufficientFunds, http.StatusConflict},
+		{"wrapped", 0, fmt.Errorf("transfer: %w", insufficientFunds), http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter()
+			router.POST("/transfers", func(ctx *Context) (any, int, error) {
+				return nil, tt.status, tt.err
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/transfers", nil))
+
+			if w.Code != tt.expected {
+				t.Fatalf("Expected status %d, got %d", tt.expected, w.Code)
+			}
+
+			var body struct {
+				Error   string           `json:"error"`
+				Details []map[string]any `json:"details"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to decode body %q: %v", w.Body.String(), err)
+			}
+			if body.Error != "validation_failed" || len(body.Details) != 1 {
+				t.Fatalf("Expected validation_failed with one detail, got %s", w.Body.String())
+			}
+			detail := body.Details[0]
+			if detail["field"] != "amount" || detail["tag"] != "balance" || detail["message"] != "amount exceeds the available balance" {
+				t.Errorf("Expected the amount field error, got %v", detail)
+			}
+		})
+	}
+}
+
+func TestRouter_RoutePriority(t *testing.T) {
+	router := NewRouter()
+	router.GET("/users/new", func(ctx *Context) (any, int, error) {
+		return "new user form", http.StatusOK, nil
+	})
+	router.GET("/users/:id", func(ctx *Context) (any, int, error) {
+		return "user " + ctx.Param("id"), http.StatusOK, nil
+	}, WithPriority(10))
+	router.GET("/pages/about", func(ctx *Context) (any, int, error) {
+		return "about", http.StatusOK, nil
+	})
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		// The static route would win by default; the prioritized param route overrides it
+		{"/users/new", "user new"},
+		{"/users/42", "user 42"},
+		// Static routes without a competing match still resolve
+		{"/pages/about", "about"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != http.StatusOK || w.Body.String() != tt.expected {
+				t.Errorf("Expected 200 %q, got %d %q", tt.expected, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRouter_Methods(t *testing.T) {
+	router := NewRouter()
+	handler := func(ctx *Context) (any, int, error) { return "ok", http.StatusOK, nil }
+	router.GET("/users", handler)
+	router.POST("/users", handler)
+	router.GET("/users/:id", handler)
+	router.PATCH("/users/:id", handler)
+	router.DELETE("/users/:id", handler)
+	router.GET("/files/*path", handler)
+
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{"/users", []string{"GET", "POST"}},
+		{"/users/42", []string{"DELETE", "GET", "PATCH"}},
+		{"/files/docs/readme.md", []string{"GET"}},
+		{"/orders", []string{}},
+		{"/users/42/posts", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			methods := router.Methods(tt.path)
+			if methods == nil {
+				t.Fatal("Expected a non-nil slice")
+			}
+			if !slices.Equal(methods, tt.expected) {
+				t.Errorf("Expected methods %v, got %v", tt.expected, methods)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package nimbus
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// RouterStats is a snapshot of the request statistics collected with WithStats
+type RouterStats struct {
+	Requests  uint64 `json:"requests"`
+	Status1xx uint64 `json:"status_1xx"`
+	Status2xx uint64 `json:"status_2xx"`
+	Status3xx uint64 `json:"status_3xx"`
+	Status4xx uint64 `json:"status_4xx"`
+	Status5xx uint64 `json:"status_5xx"`
+	// AvgLatency is an exponential moving average of request latency, weighting recent
+	// requests (each new request moves it a tenth of the way to its own latency)
+	AvgLatency time.Duration `json:"avg_latency_ns"`
+}
+
+// routerStats accumulates RouterStats with atomics, so recording never blocks ServeHTTP
+type routerStats struct {
+	requests   atomic.Uint64
+	classes    [5]atomic.Uint64 // Responses by status class: 1xx at index 0 through 5xx at 4
+	avgLatency atomic.Int64     // Nanoseconds
+}
+
+// statsLatencyWeight is the inverse of the weight each request has in the latency average
+const statsLatencyWeight = 10
+
+// WithStats makes the router count requests by response status class and track their average
+// latency, read with Router.Stats. It's a dependency-free baseline for services without a
+// metrics system; collection costs a clock read and a few atomic updates per request, so
+// it's off unless enabled.
+//
+//	router := nimbus.NewRouter(nimbus.WithStats())
+//	router.GET("/internal/stats", router.StatsHandler())
+func WithStats() RouterOption {
+	return func(r *Router) {
+		r.stats = &routerStats{}
+	}
+}
+
+// Stats returns the request statistics collected since the router was created. Without
+// WithStats every count is zero.
+func (r *Router) Stats() RouterStats {
+	if r.stats == nil {
+		return RouterStats{}
+	}
+
+	return RouterStats{
+		Requests:   r.stats.requests.Load(),
+		Status1xx:  r.stats.classes[0].Load(),
+		Status2xx:  r.stats.classes[1].Load(),
+		Status3xx:  r.stats.classes[2].Load(),
+		Status4xx:  r.stats.classes[3].Load(),
+		Status5xx:  r.stats.classes[4].Load(),
+		AvgLatency: time.Duration(r.stats.avgLatency.Load()),
+	}
+}
+
+// StatsHandler returns a handler responding with the router's Stats as JSON, for
+// registering a stats endpoint. Requests to the endpoint are counted too.
+func (r *Router) StatsHandler() Handler {
+	return func(ctx *Context) (any, int, error) {
+		return r.Stats(), http.StatusOK, nil
+	}
+}
+
+// serveWithStats serves the request, recording its status and latency
+func (r *Router) serveWithStats(w http.ResponseWriter, req *http.Request) {
+	start := time.Now()
+	recorder := &statusRecorder{ResponseWriter: w}
+	r.serve(recorder, req)
+
+	status := recorder.status
+	if status == 0 {
+		status = http.StatusOK // net/http's default when nothing was written
+	}
+	r.stats.record(status, time.Since(start))
+}
+
+// record counts a finished request
+func (s *routerStats) record(statusCode int, latency time.Duration) {
+	if class := statusCode/100 - 1; class >= 0 && class < len(s.classes) {
+		s.classes[class].Add(1)
+	}
+
+	s.requests.Add(1)
+
+	// The first request sets the average; later ones move it toward their latency
+	for {
+		old := s.avgLatency.Load()
+		updated := int64(latency)
+		if old != 0 {
+			updated = old + (int64(latency)-old)/statsLatencyWeight
+		}
+		if s.avgLatency.CompareAndSwap(old, updated) {
+			return
+		}
+	}
+}
+
+// statusRecorder remembers the status code written to a response, for WithStats
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *statusRecorder) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap returns the underlying ResponseWriter (used by http.ResponseController)
+func (w *statusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
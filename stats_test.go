@@ -0,0 +1,98 @@
+package nimbus
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRouter_Stats(t *testing.T) {
+	router := NewRouter(WithStats())
+	router.GET("/ok", func(ctx *Context) (any, int, error) {
+		return map[string]string{"status": "ok"}, http.StatusOK, nil
+	})
+	router.POST("/items", func(ctx *Context) (any, int, error) {
+		return map[string]string{"id": "1"}, http.StatusCreated, nil
+	})
+	router.GET("/old", func(ctx *Context) (any, int, error) {
+		ctx.Redirect(http.StatusMovedPermanently, "/ok")
+		return nil, 0, nil
+	})
+	router.GET("/fail", func(ctx *Context) (any, int, error) {
+		return nil, http.StatusInternalServerError, NewAPIError("boom", "failed")
+	})
+	router.GET("/slow", func(ctx *Context) (any, int, error) {
+		time.Sleep(5 * time.Millisecond)
+		return nil, http.StatusNoContent, nil
+	})
+	router.GET("/silent", func(ctx *Context) (any, int, error) {
+		return nil, 0, nil // Writes nothing, so net/http sends 200
+	})
+
+	requests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/ok"},
+		{http.MethodGet, "/ok"},
+		{http.MethodPost, "/items"},
+		{http.MethodGet, "/old"},
+		{http.MethodGet, "/missing"},
+		{http.MethodGet, "/fail"},
+		{http.MethodGet, "/slow"},
+		{http.MethodGet, "/silent"},
+	}
+	for _, r := range requests {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(r.method, r.path, nil))
+	}
+
+	stats := router.Stats()
+	expected := RouterStats{Requests: 8, Status2xx: 5, Status3xx: 1, Status4xx: 1, Status5xx: 1}
+	latency := stats.AvgLatency
+	stats.AvgLatency = 0
+	if stats != expected {
+		t.Errorf("Expected %+v, got %+v", expected, stats)
+	}
+	if latency <= 0 || latency >= 5*time.Millisecond {
+		t.Errorf("Expected a positive average latency below the slow request's 5ms, got %v", latency)
+	}
+}
+
+func TestRouter_StatsHandler(t *testing.T) {
+	router := NewRouter(WithStats(), WithoutSuccessEnvelope())
+	router.GET("/stats", router.StatsHandler())
+	router.GET("/missing-first", func(ctx *Context) (any, int, error) {
+		return nil, http.StatusNotFound, ErrRouteNotFound
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing-first", nil))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats", nil))
+
+	var stats RouterStats
+	if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("Failed to decode %q: %v", w.Body.String(), err)
+	}
+	// The stats request itself is recorded after its response is written
+	if stats.Requests != 1 || stats.Status4xx != 1 {
+		t.Errorf("Expected one 4xx request, got %+v", stats)
+	}
+	if router.Stats().Requests != 2 {
+		t.Errorf("Expected the stats request to be counted, got %d", router.Stats().Requests)
+	}
+}
+
+func TestRouter_StatsDisabled(t *testing.T) {
+	router := NewRouter()
+	router.GET("/ok", func(ctx *Context) (any, int, error) {
+		return "ok", http.StatusOK, nil
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+
+	if stats := router.Stats(); stats != (RouterStats{}) {
+		t.Errorf("Expected no stats without WithStats, got %+v", stats)
+	}
+}
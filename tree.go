@@ -1,6 +1,7 @@
 package nimbus
 
 import (
+	"maps"
 	"strings"
 )
 
@@ -25,13 +26,20 @@ type node struct {
 	route *Route // Handler for this exact path (nil if not a complete route)
 
 	// Children
-	children   []*node // Static and param children
-	paramChild *node   // Single param child (:param)
+	children      []*node // Static and param children
+	paramChild    *node   // Single param child (:param)
+	wildcardChild *node   // Single catch-all child (*path), always terminal
 }
 
 // tree represents a radix tree for a specific HTTP method
 type tree struct {
 	root *node
+	// prioritized is set once a route with a WithPriority priority is inserted, switching
+	// search from first match to comparing every matching route
+	prioritized bool
+	// maxParams is the most path params of any route in the tree, to size the map
+	// searchAll reuses
+	maxParams int
 }
 
 // newTree creates a new radix tree
@@ -54,7 +62,21 @@ func (t *tree) insert(path string, route *Route) {
 		path = "/" + path
 	}
 
+	route.paramCount = countParams(path)
 	t.root.insert(path, route)
+	t.prioritized = t.prioritized || route.priority != 0
+	t.maxParams = max(t.maxParams, route.paramCount)
+}
+
+// countParams returns the number of :param and *wildcard segments in a route path
+func countParams(path string) int {
+	count := 0
+	for segment := range strings.SplitSeq(path, "/") {
+		if segment != "" && (segment[0] == ':' || segment[0] == '*') {
+			count++
+		}
+	}
+	return count
 }
 
 // insert recursively inserts a route into the tree
@@ -95,6 +117,21 @@ func (n *node) insert(path string, route *Route) {
 		segType = static
 	}
 
+	// Handle wildcard nodes (catch-all must be the last segment)
+	if segType == wildcard {
+		if remaining != "" {
+			panic("wildcard segment '" + segment + "' must be the last segment in the path")
+		}
+		n.wildcardChild = &node{
+			nType:    wildcard,
+			prefix:   segment,
+			paramKey: paramKey,
+			route:    route,
+			children: make([]*node, 0),
+		}
+		return
+	}
+
 	// Handle parameter nodes
 	if segType == param {
 		if n.paramChild == nil {
@@ -209,7 +246,13 @@ func (t *tree) search(path string) (*Route, map[string]string) {
 		path = "/"
 	}
 
-	// Lazy allocation: don't allocate params map until we know we need it
+	if t.prioritized {
+		var best match
+		t.root.searchAll(path, make(map[string]string, t.maxParams), &best)
+		return best.route, best.params
+	}
+
+	// Lazy allocation: params are set once the route is found, sized for its params
 	var params map[string]string
 	route := t.root.search(path, &params)
 
@@ -221,11 +264,23 @@ func (t *tree) search(path string) (*Route, map[string]string) {
 	return route, params
 }
 
-// search recursively searches for a route in the tree
+// search recursively searches for a route in the tree.
+// Priority is static > param > wildcard; if a higher-priority branch fails to match
+// deeper in the path, the search backtracks and tries the next candidate.
+// Params are set on the way back from a match, once the route (and so how many params it
+// has) is known; a failed branch allocates nothing and leaves nothing to undo.
 func (n *node) search(path string, params *map[string]string) *Route {
 	// Handle root path
 	if path == "/" || path == "" {
-		return n.route
+		if n.route != nil {
+			return n.route
+		}
+		// Trailing slash with a catch-all child matches with an empty tail (e.g. /files/ -> /files/*path)
+		if path == "/" && n.wildcardChild != nil {
+			setParam(params, n.wildcardChild.route, n.wildcardChild.paramKey, "")
+			return n.wildcardChild.route
+		}
+		return nil
 	}
 
 	// Remove leading slash
@@ -251,35 +306,133 @@ func (n *node) search(path string, params *map[string]string) *Route {
 
 		// Check if segment starts with child's prefix
 		if strings.HasPrefix(segment, child.prefix) {
+			var route *Route
 			if len(segment) == len(child.prefix) {
 				// Exact match
 				if remaining == "" {
-					return child.route
+					route = child.route
+				} else {
+					route = child.search(remaining, params)
 				}
-				return child.search(remaining, params)
-			} else if len(segment) > len(child.prefix) {
+			} else {
 				// Segment is longer - continue matching
 				newPath := "/" + segment[len(child.prefix):] + remaining
-				return child.search(newPath, params)
+				route = child.search(newPath, params)
+			}
+			if route != nil {
+				return route
 			}
 		}
 	}
 
 	// Try parameter child
 	if n.paramChild != nil {
-		// Lazy allocate params map only when we actually have parameters (1 bucket = 8 capacity)
-		if *params == nil {
-			*params = make(map[string]string, 8)
+		var route *Route
+		if remaining == "" {
+			route = n.paramChild.route
+		} else {
+			route = n.paramChild.search(remaining, params)
+		}
+		if route != nil {
+			setParam(params, route, n.paramChild.paramKey, segment)
+			return route
 		}
-		(*params)[n.paramChild.paramKey] = segment
+	}
+
+	// Try wildcard child - captures the rest of the path (without the leading slash)
+	if n.wildcardChild != nil {
+		setParam(params, n.wildcardChild.route, n.wildcardChild.paramKey, path)
+		return n.wildcardChild.route
+	}
+
+	return nil
+}
+
+// match is the best route found so far by searchAll, with the params it captured
+type match struct {
+	route  *Route
+	params map[string]string
+}
 
+// searchAll visits every route matching path, in the same order as search, and keeps the
+// one with the highest priority in best. On equal priorities the route search would have
+// returned wins, so unprioritized routes keep the static > param > wildcard order.
+func (n *node) searchAll(path string, params map[string]string, best *match) {
+	if path == "/" || path == "" {
+		if n.route != nil {
+			best.consider(n.route, params)
+		}
+		if path == "/" && n.wildcardChild != nil {
+			params[n.wildcardChild.paramKey] = ""
+			best.consider(n.wildcardChild.route, params)
+			delete(params, n.wildcardChild.paramKey)
+		}
+		return
+	}
+
+	path = strings.TrimPrefix(path, "/")
+
+	segment, remaining := path, ""
+	if segmentEnd := strings.IndexByte(path, '/'); segmentEnd != -1 {
+		segment, remaining = path[:segmentEnd], path[segmentEnd:]
+	}
+
+	for _, child := range n.children {
+		if child.nType != static || !strings.HasPrefix(segment, child.prefix) {
+			continue
+		}
+		if len(segment) == len(child.prefix) {
+			if remaining == "" {
+				if child.route != nil {
+					best.consider(child.route, params)
+				}
+			} else {
+				child.searchAll(remaining, params, best)
+			}
+		} else {
+			child.searchAll("/"+segment[len(child.prefix):]+remaining, params, best)
+		}
+	}
+
+	if n.paramChild != nil {
+		params[n.paramChild.paramKey] = segment
 		if remaining == "" {
-			return n.paramChild.route
+			if n.paramChild.route != nil {
+				best.consider(n.paramChild.route, params)
+			}
+		} else {
+			n.paramChild.searchAll(remaining, params, best)
 		}
-		return n.paramChild.search(remaining, params)
+		delete(params, n.paramChild.paramKey)
 	}
 
-	return nil
+	if n.wildcardChild != nil {
+		params[n.wildcardChild.paramKey] = path
+		best.consider(n.wildcardChild.route, params)
+		delete(params, n.wildcardChild.paramKey)
+	}
+}
+
+// consider replaces the best match with route if it has a higher priority,
+// copying params since searchAll keeps reusing its map
+func (m *match) consider(route *Route, params map[string]string) {
+	if m.route != nil && route.priority <= m.route.priority {
+		return
+	}
+	m.route = route
+	m.params = nil
+	if len(params) > 0 {
+		m.params = maps.Clone(params)
+	}
+}
+
+// setParam stores a path parameter of the matched route, lazily allocating the params map
+// sized for the route's params, so routes with many params never grow it
+func setParam(params *map[string]string, route *Route, key, value string) {
+	if *params == nil {
+		*params = make(map[string]string, route.paramCount)
+	}
+	(*params)[key] = value
 }
 
 // longestCommonPrefix returns the length of the longest common prefix
@@ -323,6 +476,11 @@ func (n *node) collectRoutes(routes *[]*Route) {
 	if n.paramChild != nil {
 		n.paramChild.collectRoutes(routes)
 	}
+
+	// Collect from wildcard child
+	if n.wildcardChild != nil {
+		n.wildcardChild.collectRoutes(routes)
+	}
 }
 
 // clone creates a deep copy of the tree for thread-safe copy-on-write semantics.
@@ -332,7 +490,9 @@ func (t *tree) clone() *tree {
 		return nil
 	}
 	return &tree{
-		root: t.root.clone(),
+		root:        t.root.clone(),
+		prioritized: t.prioritized,
+		maxParams:   t.maxParams,
 	}
 }
 
@@ -368,6 +528,11 @@ func (n *node) clone() *node {
 		newNode.paramChild = n.paramChild.clone()
 	}
 
+	// Deep copy wildcard child
+	if n.wildcardChild != nil {
+		newNode.wildcardChild = n.wildcardChild.clone()
+	}
+
 	return newNode
 }
 
@@ -384,8 +549,11 @@ func (t *tree) insertWithCopy(path string, route *Route) *tree {
 		path = "/" + path
 	}
 
+	route.paramCount = countParams(path)
 	return &tree{
-		root: t.root.insertWithCopy(path, route),
+		root:        t.root.insertWithCopy(path, route),
+		prioritized: t.prioritized || route.priority != 0,
+		maxParams:   max(t.maxParams, route.paramCount),
 	}
 }
 
@@ -405,8 +573,9 @@ func (n *node) insertWithCopy(path string, route *Route) *node {
 	// Handle root path
 	if path == "/" {
 		newNode.route = route
-		newNode.children = n.children     // Share children (unchanged)
-		newNode.paramChild = n.paramChild // Share param child (unchanged)
+		newNode.children = n.children           // Share children (unchanged)
+		newNode.paramChild = n.paramChild       // Share param child (unchanged)
+		newNode.wildcardChild = n.wildcardChild // Share wildcard child (unchanged)
 		return newNode
 	}
 
@@ -440,9 +609,27 @@ func (n *node) insertWithCopy(path string, route *Route) *node {
 		segType = static
 	}
 
+	// Handle wildcard nodes (catch-all must be the last segment)
+	if segType == wildcard {
+		if remaining != "" {
+			panic("wildcard segment '" + segment + "' must be the last segment in the path")
+		}
+		newNode.children = n.children     // Share static children (unchanged)
+		newNode.paramChild = n.paramChild // Share param child (unchanged)
+		newNode.wildcardChild = &node{
+			nType:    wildcard,
+			prefix:   segment,
+			paramKey: paramKey,
+			route:    route,
+			children: make([]*node, 0),
+		}
+		return newNode
+	}
+
 	// Handle parameter nodes
 	if segType == param {
-		newNode.children = n.children // Share static children (unchanged)
+		newNode.children = n.children           // Share static children (unchanged)
+		newNode.wildcardChild = n.wildcardChild // Share wildcard child (unchanged)
 
 		if n.paramChild == nil {
 			// Create new param child
@@ -463,13 +650,14 @@ func (n *node) insertWithCopy(path string, route *Route) *node {
 			if remaining == "" {
 				// Terminal node - copy and update route
 				newNode.paramChild = &node{
-					nType:      n.paramChild.nType,
-					label:      n.paramChild.label,
-					prefix:     n.paramChild.prefix,
-					paramKey:   n.paramChild.paramKey,
-					route:      route,                   // Updated route
-					children:   n.paramChild.children,   // Share children
-					paramChild: n.paramChild.paramChild, // Share param child
+					nType:         n.paramChild.nType,
+					label:         n.paramChild.label,
+					prefix:        n.paramChild.prefix,
+					paramKey:      n.paramChild.paramKey,
+					route:         route,                      // Updated route
+					children:      n.paramChild.children,      // Share children
+					paramChild:    n.paramChild.paramChild,    // Share param child
+					wildcardChild: n.paramChild.wildcardChild, // Share wildcard child
 				}
 			} else {
 				newNode.paramChild = n.paramChild.insertWithCopy(remaining, route)
@@ -512,13 +700,14 @@ func (n *node) insertWithCopy(path string, route *Route) *node {
 				if remaining == "" {
 					// Terminal node - copy and update route
 					newChildren[matchedIdx] = &node{
-						nType:      matchedChild.nType,
-						label:      matchedChild.label,
-						prefix:     matchedChild.prefix,
-						paramKey:   matchedChild.paramKey,
-						route:      route,                   // Updated route
-						children:   matchedChild.children,   // Share children
-						paramChild: matchedChild.paramChild, // Share param child
+						nType:         matchedChild.nType,
+						label:         matchedChild.label,
+						prefix:        matchedChild.prefix,
+						paramKey:      matchedChild.paramKey,
+						route:         route,                      // Updated route
+						children:      matchedChild.children,      // Share children
+						paramChild:    matchedChild.paramChild,    // Share param child
+						wildcardChild: matchedChild.wildcardChild, // Share wildcard child
 					}
 				} else {
 					newChildren[matchedIdx] = matchedChild.insertWithCopy(remaining, route)
@@ -540,13 +729,14 @@ func (n *node) insertWithCopy(path string, route *Route) *node {
 
 			// Create updated child with remaining prefix
 			updatedChild := &node{
-				nType:      matchedChild.nType,
-				label:      matchedChild.prefix[commonLen],
-				prefix:     matchedChild.prefix[commonLen:],
-				paramKey:   matchedChild.paramKey,
-				route:      matchedChild.route,      // Keep original route
-				children:   matchedChild.children,   // Share children
-				paramChild: matchedChild.paramChild, // Share param child
+				nType:         matchedChild.nType,
+				label:         matchedChild.prefix[commonLen],
+				prefix:        matchedChild.prefix[commonLen:],
+				paramKey:      matchedChild.paramKey,
+				route:         matchedChild.route,         // Keep original route
+				children:      matchedChild.children,      // Share children
+				paramChild:    matchedChild.paramChild,    // Share param child
+				wildcardChild: matchedChild.wildcardChild, // Share wildcard child
 			}
 			splitNode.children = append(splitNode.children, updatedChild)
 
@@ -585,6 +775,7 @@ func (n *node) insertWithCopy(path string, route *Route) *node {
 	}
 
 	newNode.children = newChildren
-	newNode.paramChild = n.paramChild // Share unchanged param child
+	newNode.paramChild = n.paramChild       // Share unchanged param child
+	newNode.wildcardChild = n.wildcardChild // Share unchanged wildcard child
 	return newNode
 }
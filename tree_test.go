@@ -2,6 +2,7 @@ package nimbus
 
 import (
 	"fmt"
+	"maps"
 	"testing"
 )
 
@@ -221,6 +222,197 @@ func TestTree_NoMatch(t *testing.T) {
 	}
 }
 
+func TestTree_Wildcard(t *testing.T) {
+	tree := newTree()
+
+	filesRoute := &Route{pattern: "/files/*path"}
+	readmeRoute := &Route{pattern: "/files/readme"}
+	userRoute := &Route{pattern: "/users/:id/edit"}
+	catchAllRoute := &Route{pattern: "/users/*rest"}
+
+	tree.insert("/files/*path", filesRoute)
+	tree.insert("/files/readme", readmeRoute)
+	tree.insert("/users/:id/edit", userRoute)
+	tree.insert("/users/*rest", catchAllRoute)
+
+	tests := []struct {
+		path           string
+		expectedRoute  *Route
+		expectedParams map[string]string
+	}{
+		{"/files/a/b", filesRoute, map[string]string{"path": "a/b"}},
+		{"/files/a", filesRoute, map[string]string{"path": "a"}},
+		{"/files/", filesRoute, map[string]string{"path": ""}},
+		{"/files/readme", readmeRoute, nil},
+		{"/files/readme/more", filesRoute, map[string]string{"path": "readme/more"}},
+		{"/users/42/edit", userRoute, map[string]string{"id": "42"}},
+		// Param branch fails deeper in the path, so the search backtracks to the wildcard
+		{"/users/42/delete", catchAllRoute, map[string]string{"rest": "42/delete"}},
+		{"/files", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			route, params := tree.search(tt.path)
+			if route != tt.expectedRoute {
+				t.Fatalf("Expected route %v, got %v", tt.expectedRoute, route)
+			}
+			if len(params) != len(tt.expectedParams) {
+				t.Fatalf("Expected params %v, got %v", tt.expectedParams, params)
+			}
+			for k, v := range tt.expectedParams {
+				if params[k] != v {
+					t.Errorf("Expected param %s=%q, got %q", k, v, params[k])
+				}
+			}
+		})
+	}
+}
+
+func TestTree_Priority(t *testing.T) {
+	tree := newTree()
+
+	newRoute := &Route{pattern: "/users/new"}
+	userRoute := &Route{pattern: "/users/:id", priority: 10}
+	editRoute := &Route{pattern: "/users/:id/edit"}
+	filesRoute := &Route{pattern: "/files/*path", priority: 1}
+	readmeRoute := &Route{pattern: "/files/readme"}
+	docsRoute := &Route{pattern: "/files/docs/:page", priority: 2}
+
+	tree.insert("/users/new", newRoute)
+	tree.insert("/users/:id/edit", editRoute)
+	tree.insert("/files/readme", readmeRoute)
+	tree.insert("/files/docs/:page", docsRoute)
+	// Prioritized routes inserted with copy keep the tree prioritized
+	tree = tree.insertWithCopy("/users/:id", userRoute)
+	tree = tree.insertWithCopy("/files/*path", filesRoute)
+
+	tests := []struct {
+		path           string
+		expectedRoute  *Route
+		expectedParams map[string]string
+	}{
+		// The higher-priority param route outranks the static route
+		{"/users/new", userRoute, map[string]string{"id": "new"}},
+		{"/users/42", userRoute, map[string]string{"id": "42"}},
+		// Only one route matches, so priority doesn't matter
+		{"/users/new/edit", editRoute, map[string]string{"id": "new"}},
+		{"/files/readme", filesRoute, map[string]string{"path": "readme"}},
+		// A route can outrank a lower-priority wildcard
+		{"/files/docs/intro", docsRoute, map[string]string{"page": "intro"}},
+		{"/files/", filesRoute, map[string]string{"path": ""}},
+		{"/missing", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			route, params := tree.search(tt.path)
+			if route != tt.expectedRoute {
+				t.Fatalf("Expected route %v, got %v", tt.expectedRoute, route)
+			}
+			if !maps.Equal(params, tt.expectedParams) {
+				t.Errorf("Expected params %v, got %v", tt.expectedParams, params)
+			}
+		})
+	}
+}
+
+func TestTree_PriorityTiesKeepUsualOrder(t *testing.T) {
+	tree := newTree()
+
+	staticRoute := &Route{pattern: "/users/new", priority: 5}
+	dynamicRoute := &Route{pattern: "/users/:id", priority: 5}
+	fallbackRoute := &Route{pattern: "/users/*rest", priority: -1}
+
+	tree.insert("/users/:id", dynamicRoute)
+	tree.insert("/users/new", staticRoute)
+	tree.insert("/users/*rest", fallbackRoute)
+
+	if route, _ := tree.search("/users/new"); route != staticRoute {
+		t.Errorf("Expected the static route on equal priorities, got %v", route)
+	}
+	if route, _ := tree.search("/users/42"); route != dynamicRoute {
+		t.Errorf("Expected the param route, got %v", route)
+	}
+	if route, params := tree.search("/users/42/posts"); route != fallbackRoute || params["rest"] != "42/posts" {
+		t.Errorf("Expected the wildcard route with rest=42/posts, got %v %v", route, params)
+	}
+}
+
+func TestTree_ParamCount(t *testing.T) {
+	tree := newTree()
+	routes := map[string]*Route{}
+	for _, path := range []string{"/health", "/users/:id", "/users/:id/posts/:postId", "/files/*path", "/orgs/:org/files/*path", manyParamsPath} {
+		routes[path] = &Route{pattern: path}
+		tree = tree.insertWithCopy(path, routes[path])
+	}
+	inserted := &Route{pattern: "/teams/:team"}
+	tree.insert("/teams/:team", inserted)
+
+	counts := map[*Route]int{
+		routes["/health"]:                  0,
+		routes["/users/:id"]:               1,
+		routes["/users/:id/posts/:postId"]: 2,
+		routes["/files/*path"]:             1,
+		routes["/orgs/:org/files/*path"]:   2,
+		routes[manyParamsPath]:             12,
+		inserted:                           1,
+	}
+	for route, expected := range counts {
+		if route.paramCount != expected {
+			t.Errorf("Expected %s to have %d params, got %d", route.pattern, expected, route.paramCount)
+		}
+	}
+
+	tests := []struct {
+		path     string
+		expected map[string]string
+	}{
+		{"/health", nil},
+		{"/users/7/posts/9", map[string]string{"id": "7", "postId": "9"}},
+		{"/orgs/acme/files/a/b", map[string]string{"org": "acme", "path": "a/b"}},
+		{"/a/1/2/3/4/5/6/7/8/9/10/11/12", map[string]string{
+			"p1": "1", "p2": "2", "p3": "3", "p4": "4", "p5": "5", "p6": "6",
+			"p7": "7", "p8": "8", "p9": "9", "p10": "10", "p11": "11", "p12": "12",
+		}},
+		// The param branch fails below /users/7, so nothing is captured
+		{"/users/7/unknown", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			_, params := tree.search(tt.path)
+			if (params == nil) != (tt.expected == nil) || !maps.Equal(params, tt.expected) {
+				t.Errorf("Expected params %v, got %v", tt.expected, params)
+			}
+		})
+	}
+}
+
+func TestTree_Wildcard_InsertWithCopy(t *testing.T) {
+	original := newTree()
+	original.insert("/static/*filepath", &Route{pattern: "/static/*filepath"})
+
+	updated := original.insertWithCopy("/static/index", &Route{pattern: "/static/index"})
+
+	if route, params := updated.search("/static/css/app.css"); route == nil || params["filepath"] != "css/app.css" {
+		t.Errorf("Expected wildcard match in copied tree, got %v %v", route, params)
+	}
+	if route, _ := original.search("/static/index"); route == nil || route.pattern != "/static/*filepath" {
+		t.Errorf("Expected original tree to be unchanged, got %v", route)
+	}
+}
+
+func TestTree_Wildcard_NotLastSegmentPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for wildcard that isn't the last segment")
+		}
+	}()
+
+	newTree().insert("/files/*path/edit", &Route{})
+}
+
 func TestLongestCommonPrefix(t *testing.T) {
 	tests := []struct {
 		a, b     string
@@ -293,6 +485,43 @@ func BenchmarkTree_Search_Dynamic(b *testing.B) {
 	}
 }
 
+// manyParamsPath is a route with more params than a default-sized map holds
+const manyParamsPath = "/a/:p1/:p2/:p3/:p4/:p5/:p6/:p7/:p8/:p9/:p10/:p11/:p12"
+
+func BenchmarkTree_Search_SingleParamMiss(b *testing.B) {
+	// The param branch matches the segment but not the rest of the path
+	tree := newTree()
+	tree.insert("/users/:id", &Route{pattern: "/users/:id"})
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tree.search("/users/123/unknown")
+	}
+}
+
+func BenchmarkTree_Search_SingleParam(b *testing.B) {
+	tree := newTree()
+	tree.insert("/users/:id", &Route{pattern: "/users/:id"})
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tree.search("/users/123")
+	}
+}
+
+func BenchmarkTree_Search_ManyParams(b *testing.B) {
+	tree := newTree()
+	tree.insert(manyParamsPath, &Route{pattern: manyParamsPath})
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tree.search("/a/1/2/3/4/5/6/7/8/9/10/11/12")
+	}
+}
+
 func BenchmarkTree_Search_ManyRoutes(b *testing.B) {
 	tree := newTree()
 
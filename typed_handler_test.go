@@ -265,3 +265,62 @@ func TestWithTyped_NoParameters(t *testing.T) {
 		t.Errorf("expected status 200, got %d", w.Code)
 	}
 }
+
+func TestValidatedAccessors_AfterWithTyped(t *testing.T) {
+	router := NewRouter()
+
+	// Audit middleware reads the parsed request once the handler has run
+	var audited struct {
+		id, email string
+		page      int
+		found     bool
+	}
+	audit := func(next Handler) Handler {
+		return func(ctx *Context) (any, int, error) {
+			data, statusCode, err := next(ctx)
+
+			params, okParams := ValidatedParams[TestParams](ctx)
+			body, okBody := ValidatedBody[TestBody](ctx)
+			query, okQuery := ValidatedQuery[TestQuery](ctx)
+			if audited.found = okParams && okBody && okQuery; audited.found {
+				audited.id, audited.email, audited.page = params.ID, body.Email, query.Page
+			}
+
+			// Wrong type is reported rather than panicking
+			if _, ok := ValidatedBody[TestQuery](ctx); ok {
+				t.Error("expected ValidatedBody with the wrong type to report false")
+			}
+
+			return data, statusCode, err
+		}
+	}
+
+	handler := func(ctx *Context, req *TypedRequest[TestParams, TestBody, TestQuery]) (any, int, error) {
+		return nil, http.StatusNoContent, nil
+	}
+	router.PUT("/users/:id",
+		WithTyped(handler, testParamsValidator, testBodyValidator, testQueryValidator),
+		WithMiddleware(audit))
+
+	body := `{"name":"Jane Doe","email":"jane@example.com"}`
+	req := httptest.NewRequest(http.MethodPut, "/users/456?page=3&limit=20", bytes.NewReader([]byte(body)))
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if !audited.found || audited.id != "456" || audited.email != "jane@example.com" || audited.page != 3 {
+		t.Errorf("expected typed params, body and query in middleware, got %+v", audited)
+	}
+}
+
+func TestValidatedAccessors_NotBound(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	defer ctx.Release()
+
+	if body, ok := ValidatedBody[TestBody](ctx); ok || body != nil {
+		t.Errorf("expected no body before binding, got %v", body)
+	}
+}
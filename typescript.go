@@ -0,0 +1,189 @@
+package nimbus
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
+// TypeScript returns a TypeScript interface named name for the schema's struct, so frontends
+// can share the request types the API validates:
+//
+//	export interface CreateUserRequest {
+//	  name: string;
+//	  age?: number;
+//	  role?: "user" | "admin";
+//	}
+//
+// Fields appear in struct order under their JSON names. Required fields are non-optional;
+// enums become unions of their values; numbers, strings and booleans map to number, string and
+// boolean; time.Time is a string, slices are arrays, maps are Records, and pointers are
+// nullable. Nested structs are inlined as object types, with optionality from their own
+// validate tags and names read with the schema's WithValidationTag and WithFieldNameTags
+// options. Schemas from ImportJSONSchema list their properties by name, typed from the
+// JSON Schema.
+func (s *Schema) TypeScript(name string) string {
+	var b strings.Builder
+	b.WriteString("export interface ")
+	b.WriteString(name)
+	b.WriteString(" ")
+	writeTSObject(&b, s, 0, map[reflect.Type]bool{s.structType: true})
+	b.WriteString("\n")
+	return b.String()
+}
+
+// writeTSObject writes a schema's fields as a TypeScript object type at the given indent depth.
+// seen holds the struct types being written, so recursive types end in unknown.
+func writeTSObject(b *strings.Builder, s *Schema, depth int, seen map[reflect.Type]bool) {
+	rules := slices.Collect(maps.Values(s.fields))
+	slices.SortFunc(rules, func(a, c fieldRule) int {
+		// Imported schemas have no struct order, so their properties sort by name
+		return cmp.Or(slices.Compare(a.index, c.index), strings.Compare(a.jsonTag, c.jsonTag))
+	})
+
+	indent := strings.Repeat("  ", depth+1)
+	b.WriteString("{\n")
+	for _, rule := range rules {
+		field, ok := s.structField(rule)
+		if !ok && s.structType != nil {
+			continue
+		}
+
+		b.WriteString(indent)
+		b.WriteString(tsPropertyName(rule.jsonTag))
+		if !rule.required {
+			b.WriteString("?")
+		}
+		b.WriteString(": ")
+		if !ok {
+			// Imported from a JSON Schema (see ImportJSONSchema)
+			writeTSJSONType(b, rule)
+		} else if len(rule.enum) > 0 {
+			b.WriteString(tsEnum(field.Type, rule.enum))
+			if field.Type.Kind() == reflect.Pointer {
+				b.WriteString(" | null")
+			}
+		} else {
+			writeTSType(b, s, field.Type, depth+1, seen)
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString("}")
+}
+
+// writeTSType writes the TypeScript type for a Go type as it marshals to JSON. Nested structs
+// are read with the options of s, the schema the type appears in.
+func writeTSType(b *strings.Builder, s *Schema, t reflect.Type, depth int, seen map[reflect.Type]bool) {
+	switch {
+	case t == timeType:
+		b.WriteString("string")
+		return
+	case t == rawMessageType:
+		b.WriteString("unknown")
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		b.WriteString("string")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		b.WriteString("number")
+	case reflect.Bool:
+		b.WriteString("boolean")
+	case reflect.Pointer:
+		writeTSType(b, s, t.Elem(), depth, seen)
+		b.WriteString(" | null")
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			b.WriteString("string") // []byte marshals as base64
+			return
+		}
+		elem := t.Elem()
+		if elem.Kind() == reflect.Pointer {
+			b.WriteString("(")
+			writeTSType(b, s, elem, depth, seen)
+			b.WriteString(")[]")
+			return
+		}
+		writeTSType(b, s, elem, depth, seen)
+		b.WriteString("[]")
+	case reflect.Map:
+		b.WriteString("Record<string, ")
+		writeTSType(b, s, t.Elem(), depth, seen)
+		b.WriteString(">")
+	case reflect.Struct:
+		if seen[t] {
+			b.WriteString("unknown")
+			return
+		}
+		seen[t] = true
+		nested := NewSchema(reflect.New(t).Interface(), WithValidationTag(s.validateTag), WithFieldNameTags(s.nameTags...))
+		writeTSObject(b, nested, depth, seen)
+		delete(seen, t)
+	default:
+		b.WriteString("unknown")
+	}
+}
+
+// writeTSJSONType writes the TypeScript type for a property imported from a JSON Schema
+func writeTSJSONType(b *strings.Builder, rule fieldRule) {
+	if len(rule.enum) > 0 {
+		b.WriteString(tsEnum(reflect.TypeFor[string](), rule.enum))
+		return
+	}
+
+	switch rule.valueType {
+	case "string", "boolean":
+		b.WriteString(rule.valueType)
+	case "number", "integer":
+		b.WriteString("number")
+	case "array":
+		b.WriteString("unknown[]")
+	case "object":
+		b.WriteString("Record<string, unknown>")
+	default:
+		b.WriteString("unknown")
+	}
+}
+
+// tsEnum returns the union of an enum rule's values, as number literals for numeric fields
+func tsEnum(t reflect.Type, values []string) string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	numeric := t.Kind() != reflect.String
+	literals := make([]string, len(values))
+	for i, value := range values {
+		if _, err := strconv.ParseFloat(value, 64); numeric && err == nil {
+			literals[i] = value
+			continue
+		}
+		quoted, _ := json.Marshal(value)
+		literals[i] = string(quoted)
+	}
+	return strings.Join(literals, " | ")
+}
+
+// tsPropertyName quotes a JSON name that isn't a valid TypeScript identifier
+func tsPropertyName(name string) string {
+	for i, r := range name {
+		if r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9' {
+			continue
+		}
+		quoted, _ := json.Marshal(name)
+		return string(quoted)
+	}
+	if name == "" {
+		return `""`
+	}
+	return name
+}
@@ -0,0 +1,109 @@
+package nimbus
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSchema_TypeScript(t *testing.T) {
+	got := NewSchema(TestUser{}).TypeScript("User")
+
+	expected := `export interface User {
+  name: string;
+  email: string;
+  age?: number;
+  role?: "user" | "admin" | "moderator";
+  password: string;
+}
+`
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+type TestTSAddress struct {
+	Street string `json:"street" validate:"required"`
+	Zip    string `json:"zip"`
+}
+
+type TestTSNode struct {
+	Value    int           `json:"value"`
+	Children []*TestTSNode `json:"children"`
+}
+
+type TestTSOrder struct {
+	ID        string            `json:"id" validate:"required"`
+	Priority  int               `json:"priority" validate:"enum=1|2|3"`
+	Status    *string           `json:"status" validate:"enum=open|closed"`
+	Paid      bool              `json:"paid"`
+	Total     float64           `json:"total" validate:"required"`
+	Tags      []string          `json:"tags"`
+	Labels    map[string]string `json:"labels"`
+	Note      *string           `json:"note"`
+	Attached  []byte            `json:"attachment"`
+	Extra     json.RawMessage   `json:"extra"`
+	CreatedAt time.Time         `json:"created_at" validate:"required"`
+	Shipping  TestTSAddress     `json:"shipping" validate:"required"`
+	Tree      *TestTSNode       `json:"tree"`
+	ItemCount int               `json:"item-count"`
+	Internal  string            `json:"-"`
+}
+
+func TestSchema_TypeScript_FieldTypes(t *testing.T) {
+	got := NewSchema(TestTSOrder{}).TypeScript("Order")
+
+	expected := `export interface Order {
+  id: string;
+  priority?: 1 | 2 | 3;
+  status?: "open" | "closed" | null;
+  paid?: boolean;
+  total: number;
+  tags?: string[];
+  labels?: Record<string, string>;
+  note?: string | null;
+  attachment?: string;
+  extra?: unknown;
+  created_at: string;
+  shipping: {
+    street: string;
+    zip?: string;
+  };
+  tree?: {
+    value?: number;
+    children?: (unknown | null)[];
+  } | null;
+  "item-count"?: number;
+}
+`
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+type TestTSFormAddress struct {
+	Street string `form:"street" binding:"required"`
+	Zip    string `form:"zip"`
+}
+
+type TestTSForm struct {
+	Name    string            `form:"name" binding:"required"`
+	Address TestTSFormAddress `form:"address" binding:"required"`
+}
+
+func TestSchema_TypeScript_NestedUsesSchemaOptions(t *testing.T) {
+	schema := NewSchema(TestTSForm{}, WithValidationTag("binding"), WithFieldNameTags("form"))
+	got := schema.TypeScript("Signup")
+
+	expected := `export interface Signup {
+  name: string;
+  address: {
+    street: string;
+    zip?: string;
+  };
+}
+`
+	if got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+}
@@ -1,17 +1,35 @@
 package nimbus
 
 import (
+	"bytes"
+	"cmp"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"net"
 	"net/url"
 	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 var (
 	emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
+
+	// netFormatNames describes each network address rule in its error message
+	netFormatNames = map[string]string{
+		"ip":   "IP address",
+		"ipv4": "IPv4 address",
+		"ipv6": "IPv6 address",
+		"cidr": "CIDR block",
+		"mac":  "MAC address",
+	}
 )
 
 // ValidationError represents a structured validation error
@@ -20,6 +38,8 @@ type ValidationError struct {
 	Value   any    `json:"value"`
 	Tag     string `json:"tag"`
 	Message string `json:"message"`
+	// param is the rule's argument (e.g. "3" for minlen=3), used by localized messages
+	param string
 }
 
 // ValidationErrors is a collection of validation errors
@@ -35,27 +55,204 @@ func (ve ValidationErrors) Error() string {
 	return fmt.Sprintf("validation failed on %d fields", len(ve))
 }
 
+// ForField returns the errors reported for the named field, in the order they occurred
+func (ve ValidationErrors) ForField(name string) []ValidationError {
+	var matches []ValidationError
+	for _, err := range ve {
+		if err.Field == name {
+			matches = append(matches, err)
+		}
+	}
+	return matches
+}
+
+// Has reports whether any error was reported for the named field
+func (ve ValidationErrors) Has(field string) bool {
+	for _, err := range ve {
+		if err.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Fields returns the names of the fields with errors, each once, in the order they first occurred
+func (ve ValidationErrors) Fields() []string {
+	var fields []string
+	for _, err := range ve {
+		if !slices.Contains(fields, err.Field) {
+			fields = append(fields, err.Field)
+		}
+	}
+	return fields
+}
+
+// Add appends an error for a field, for assembling business-rule failures in a handler:
+//
+//	var errs nimbus.ValidationErrors
+//	if req.Amount > balance {
+//	    errs.Add("amount", "balance", "amount exceeds the available balance")
+//	}
+//	if !slices.Contains(supportedCurrencies, req.Currency) {
+//	    errs.Add("currency", "supported", "currency is not supported")
+//	}
+//	if len(errs) > 0 {
+//	    return nil, 0, errs // Sent like SendValidationError, as 422
+//	}
+func (ve *ValidationErrors) Add(field, tag, message string) {
+	*ve = append(*ve, NewFieldError(field, tag, message))
+}
+
+// NewFieldError returns a ValidationError for a field that failed a check outside a Schema.
+// The tag names the check, and is the key for localized messages (see RegisterMessages).
+func NewFieldError(field, tag, message string) ValidationError {
+	return ValidationError{
+		Field:   field,
+		Tag:     tag,
+		Message: message,
+	}
+}
+
 // Schema represents a validation schema for a struct
 type Schema struct {
-	structType reflect.Type
-	fields     map[string]fieldRule
+	structType  reflect.Type
+	fields      map[string]fieldRule
+	strictQuery bool        // reject query parameters that don't map to a field
+	uniqueQuery bool        // reject repeated query parameters bound to scalar fields
+	presentReq  bool        // required query parameters must be present and non-empty
+	failFast    bool        // stop at the first validation error
+	pathFields  []pathField // fields bound from path parameters by their path tag
+	nameTags    []string    // tags naming fields, set by WithFieldNameTags (nil means json)
+	validateTag string      // tag holding the validation rules, set by WithValidationTag
+}
+
+// pathField is a struct field bound from a path parameter, resolved once per struct type
+type pathField struct {
+	index int    // top-level struct field index
+	param string // path parameter name from the path tag
 }
 
 type fieldRule struct {
 	jsonTag   string
+	index     []int  // Struct field index path (see reflect.Value.FieldByIndex), nil if the struct lacks the field
+	queryName string // Query parameter name: the query tag, falling back to the JSON name ("" if no field)
+	formName  string // Form field name: the form tag, falling back to the JSON name ("" if no field)
+	layout    string // time.Time layout from the layout tag, used when binding query/form values
+	valueType string // JSON type from an imported JSON Schema (see ImportJSONSchema), checked by ValidateMap
 	required  bool
-	minLength int
-	maxLength int
-	min       *int
-	max       *int
-	email     bool
-	pattern   *regexp.Regexp
-	enum      []string
-	custom    func(any) error
+	// minLength and maxLength bound a string's length in characters (runes), or in bytes with
+	// byteLength set by the bytelen rule, e.g. for columns sized in bytes
+	minLength  int
+	maxLength  int
+	byteLength bool
+	min        *float64 // Numeric bounds from min= and max=, which may be fractional (min=-90.5)
+	max        *float64
+	email      bool
+	jsonText   bool   // The string must be well-formed JSON (json rule)
+	netFormat  string // Network address format from the ip, ipv4, ipv6, cidr or mac rule ("" if none)
+	pattern    *regexp.Regexp
+	enum       []string
+	// sortFields lists the fields a sort parameter may name, each optionally prefixed with - or +
+	sortFields []string
+	custom     func(any) error
+	// customOnly skips the built-in rules so only custom validates the field
+	customOnly bool
+	// emailPolicy adds post-checks to the email rule (nil means any well-formed address)
+	emailPolicy *EmailPolicy
+	// groupRules holds rules tagged for a validation group (e.g. "create" -> ["required"])
+	groupRules map[string][]string
+	// requiredWhen makes the field required when it returns true for the struct's field values
+	requiredWhen func(allFields map[string]any) bool
+	// requiredPresence makes the field required based on which sibling fields are present
+	requiredPresence []presenceCond
+}
+
+// presenceCond is a required_with/required_without rule: it holds when the listed sibling
+// fields are present (with) or absent (without), checking all of them or any of them
+type presenceCond struct {
+	fields []string
+	with   bool
+	all    bool
+}
+
+// addPresenceCond adds a presence condition without sharing the slice with copies of the rule
+// (WithGroup applies group rules to a copy)
+func (rule *fieldRule) addPresenceCond(cond presenceCond) {
+	rule.requiredPresence = append(slices.Clip(rule.requiredPresence), cond)
+}
+
+// requiredByPresence reports whether any of the rule's presence conditions holds
+func (rule *fieldRule) requiredByPresence(present func(field string) bool) bool {
+	return slices.ContainsFunc(rule.requiredPresence, func(c presenceCond) bool {
+		return c.holds(present)
+	})
+}
+
+// holds reports whether the condition is met given a field presence check
+func (c presenceCond) holds(present func(field string) bool) bool {
+	for _, field := range c.fields {
+		if present(field) == c.with {
+			if !c.all {
+				return true
+			}
+		} else if c.all {
+			return false
+		}
+	}
+	return c.all
+}
+
+// SchemaOption configures how NewSchema reads a struct
+type SchemaOption func(*schemaOptions)
+
+type schemaOptions struct {
+	validateTag string   // Struct tag holding the validation rules
+	nameTags    []string // Struct tags naming fields, in order of preference (nil means json only)
+}
+
+// WithValidationTag reads validation rules from the given struct tag instead of "validate",
+// for structs whose rules are already written for another library's tag:
+//
+//	type SignupRequest struct {
+//	    Email string `json:"email" binding:"required,email"`
+//	}
+//
+//	schema := nimbus.NewSchema(&SignupRequest{}, nimbus.WithValidationTag("binding"))
+//
+// The rules use the same syntax either way.
+func WithValidationTag(name string) SchemaOption {
+	return func(o *schemaOptions) {
+		o.validateTag = name
+	}
+}
+
+// WithFieldNameTags names fields by the first of the given struct tags they have, falling back
+// to the Go field name, instead of by their json tag alone. Field names are what validation
+// errors, ValidateMap keys and (unless a query or form tag says otherwise) bound query and
+// form parameters use, so structs without json tags can be validated and bound:
+//
+//	type SearchForm struct {
+//	    Term  string `form:"q" validate:"required"`
+//	    Limit int    `form:"limit" validate:"max=100"`
+//	}
+//
+//	schema := nimbus.NewSchema(&SearchForm{}, nimbus.WithFieldNameTags("json", "form"))
+//
+// A tag value of "-" skips the field, as does being unexported or an untagged embedded struct.
+// By default only fields with a json tag are part of the schema.
+func WithFieldNameTags(tags ...string) SchemaOption {
+	return func(o *schemaOptions) {
+		o.nameTags = tags
+	}
 }
 
 // NewSchema creates a new validation schema from a struct type
-func NewSchema(structPtr any) *Schema {
+func NewSchema(structPtr any, opts ...SchemaOption) *Schema {
+	options := schemaOptions{validateTag: "validate"}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	t := reflect.TypeOf(structPtr)
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
@@ -66,29 +263,34 @@ func NewSchema(structPtr any) *Schema {
 	}
 
 	schema := &Schema{
-		structType: t,
-		fields:     make(map[string]fieldRule),
+		structType:  t,
+		fields:      make(map[string]fieldRule),
+		nameTags:    options.nameTags,
+		validateTag: options.validateTag,
 	}
 
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
-		jsonTag := field.Tag.Get("json")
-		validateTag := field.Tag.Get("validate")
+		validateTag := field.Tag.Get(options.validateTag)
 
-		if jsonTag == "" || jsonTag == "-" {
+		jsonName, ok := schemaFieldName(field, options.nameTags)
+		if !ok {
 			continue
 		}
 
-		// Parse JSON tag to get field name
-		jsonName := strings.Split(jsonTag, ",")[0]
-
 		// Parse validation rules
 		rule := parseValidationTag(validateTag)
 		rule.jsonTag = jsonName
+		rule.index = field.Index
+		rule.queryName = tagOrJSONName(field, "query", jsonName)
+		rule.formName = tagOrJSONName(field, "form", jsonName)
+		rule.layout = field.Tag.Get("layout")
 
 		schema.fields[jsonName] = rule
 	}
 
+	schema.pathFields = pathFieldsOf(t)
+
 	return schema
 }
 
@@ -103,6 +305,255 @@ func (s *Schema) AddCustomValidator(fieldName string, validator func(any) error)
 	return s
 }
 
+// AddCustomValidatorOnly is AddCustomValidator for fields whose validation the custom
+// function fully owns: the field's built-in rules (required, minlen, pattern, ...) are
+// skipped and only the function runs, including for empty values.
+func (s *Schema) AddCustomValidatorOnly(fieldName string, validator func(any) error) *Schema {
+	s.AddCustomValidator(fieldName, validator)
+	rule := s.fields[fieldName]
+	rule.customOnly = true
+	s.fields[fieldName] = rule
+	return s
+}
+
+// EmailPolicy restricts which addresses pass the email rule, on top of the format check.
+type EmailPolicy struct {
+	// DisallowPlusAddressing rejects addresses with a +tag in the local part (jane+news@example.com)
+	DisallowPlusAddressing bool
+	// AllowedDomains, if set, only accepts addresses at these domains (case-insensitive)
+	AllowedDomains []string
+}
+
+// check returns a message describing why email violates the policy, or "" if it complies
+func (p *EmailPolicy) check(email string) string {
+	at := strings.LastIndex(email, "@")
+	local, domain := email[:at], email[at+1:]
+
+	if p.DisallowPlusAddressing && strings.Contains(local, "+") {
+		return "must not use plus-addressing"
+	}
+
+	if len(p.AllowedDomains) > 0 && !slices.ContainsFunc(p.AllowedDomains, func(allowed string) bool {
+		return strings.EqualFold(allowed, domain)
+	}) {
+		return fmt.Sprintf("must use one of the allowed domains: %s", strings.Join(p.AllowedDomains, ", "))
+	}
+
+	return ""
+}
+
+// validNetFormat reports whether str is an address in the given network rule's format
+func validNetFormat(format, str string) bool {
+	switch format {
+	case "cidr":
+		_, _, err := net.ParseCIDR(str)
+		return err == nil
+	case "mac":
+		_, err := net.ParseMAC(str)
+		return err == nil
+	}
+
+	ip := net.ParseIP(str)
+	if ip == nil {
+		return false
+	}
+	// An IPv4-mapped IPv6 address (::ffff:192.0.2.1) is written as IPv6, so the colon decides
+	isIPv6 := strings.Contains(str, ":")
+	switch format {
+	case "ipv4":
+		return !isIPv6
+	case "ipv6":
+		return isIPv6
+	}
+	return true
+}
+
+// EmailPolicy applies an email policy to the given email fields (by JSON name),
+// or to every field with the email rule if none are given.
+//
+// Example:
+//
+//	schema.EmailPolicy(nimbus.EmailPolicy{
+//	    DisallowPlusAddressing: true,
+//	    AllowedDomains:         []string{"example.com"},
+//	}, "work_email")
+func (s *Schema) EmailPolicy(policy EmailPolicy, fieldNames ...string) *Schema {
+	if len(fieldNames) == 0 {
+		for fieldName, rule := range s.fields {
+			if rule.email {
+				rule.emailPolicy = &policy
+				s.fields[fieldName] = rule
+			}
+		}
+		return s
+	}
+
+	for _, fieldName := range fieldNames {
+		rule, exists := s.fields[fieldName]
+		if !exists {
+			panic(fmt.Sprintf("field %s not found", fieldName))
+		}
+		if !rule.email {
+			panic(fmt.Sprintf("field %s does not have the email rule", fieldName))
+		}
+		rule.emailPolicy = &policy
+		s.fields[fieldName] = rule
+	}
+	return s
+}
+
+// RequireWhen makes a field (by JSON name) required when cond returns true.
+// During Validate, cond receives every schema field's value keyed by JSON name, so it can
+// express multi-field conditions that tags can't.
+//
+// Example:
+//
+//	schema.RequireWhen("vat_number", func(fields map[string]any) bool {
+//	    return fields["country"] == "DE" && fields["account_type"] == "business"
+//	})
+func (s *Schema) RequireWhen(fieldName string, cond func(allFields map[string]any) bool) *Schema {
+	if rule, exists := s.fields[fieldName]; exists {
+		rule.requiredWhen = cond
+		s.fields[fieldName] = rule
+	} else {
+		panic(fmt.Sprintf("field %s not found", fieldName))
+	}
+	return s
+}
+
+// Merge copies the field rules of another schema into this one, so a shared base schema
+// (e.g. pagination) can be composed into endpoint-specific schemas.
+// The struct being validated must expose the merged fields, either directly or through an
+// embedded struct. Panics if both schemas define a rule for the same field.
+func (s *Schema) Merge(other *Schema) *Schema {
+	for fieldName, rule := range other.fields {
+		if _, exists := s.fields[fieldName]; exists {
+			panic(fmt.Sprintf("field %s is defined in both schemas", fieldName))
+		}
+		// The cached field data is relative to the other schema's struct; resolve it against ours
+		rule.index, rule.queryName, rule.formName, rule.layout = nil, "", "", ""
+		if field, ok := s.structType.FieldByName(getStructFieldName(s.structType, fieldName, s.nameTags)); ok {
+			rule.index = field.Index
+			rule.queryName = tagOrJSONName(field, "query", fieldName)
+			rule.formName = tagOrJSONName(field, "form", fieldName)
+			rule.layout = field.Tag.Get("layout")
+		}
+		s.fields[fieldName] = rule
+	}
+	return s
+}
+
+// WithGroup returns a copy of the schema that also enforces the rules tagged for the group.
+// Rules are tagged with a #group suffix; untagged rules always apply, and tagged rules are
+// ignored unless their group is active. The original schema is left unchanged, so one
+// struct can back both create and update endpoints:
+//
+//	type UserRequest struct {
+//	    Name string `json:"name" validate:"required#create,minlen=3"`
+//	}
+//
+//	createSchema := nimbus.NewSchema(UserRequest{}).WithGroup("create") // name required
+//	updateSchema := nimbus.NewSchema(UserRequest{}).WithGroup("update") // name optional
+//
+// A rule can belong to several groups (required#create#import). Pattern rules can't be tagged.
+func (s *Schema) WithGroup(group string) *Schema {
+	grouped := &Schema{
+		structType:  s.structType,
+		fields:      make(map[string]fieldRule, len(s.fields)),
+		strictQuery: s.strictQuery,
+		uniqueQuery: s.uniqueQuery,
+		presentReq:  s.presentReq,
+		failFast:    s.failFast,
+		pathFields:  s.pathFields,
+		nameTags:    s.nameTags,
+		validateTag: s.validateTag,
+	}
+
+	for fieldName, rule := range s.fields {
+		for _, r := range rule.groupRules[group] {
+			rule.apply(r)
+		}
+		grouped.fields[fieldName] = rule
+	}
+
+	return grouped
+}
+
+// StrictQuery makes ValidateQuery reject query parameters that don't map to a schema field
+// (e.g. a typo like ?pag=2). By default unknown parameters are silently ignored.
+func (s *Schema) StrictQuery() *Schema {
+	s.strictQuery = true
+	return s
+}
+
+// RejectDuplicateQuery makes ValidateQuery reject a query parameter given more than once
+// (e.g. ?page=1&page=2) when it binds to a scalar field. By default the first value wins.
+// Slice fields accept repeated parameters either way.
+func (s *Schema) RejectDuplicateQuery() *Schema {
+	s.uniqueQuery = true
+	return s
+}
+
+// StrictRequiredQuery makes ValidateQuery check required fields by the query string itself
+// rather than by the bound value, so every type is covered (a required int is no longer
+// satisfied by its zero value) and the errors say what was wrong: an absent parameter
+// reports "is required" and a present but empty one (?q=) reports "must not be empty",
+// both with Tag "required". By default empty parameters are skipped like absent ones.
+func (s *Schema) StrictRequiredQuery() *Schema {
+	s.presentReq = true
+	return s
+}
+
+// FailFast makes validation stop at the first error, returning ValidationErrors with just
+// that one, for services that reject a request on any failure rather than listing every
+// problem. The remaining fields (and the failing field's custom validator, after a built-in
+// rule fails) are skipped. Fields are not checked in a fixed order, so when several are
+// invalid, which one is reported may vary.
+func (s *Schema) FailFast() *Schema {
+	s.failFast = true
+	return s
+}
+
+// firstError trims errors to the first one when the schema fails fast
+func (s *Schema) firstError(errors ValidationErrors) ValidationErrors {
+	if s.failFast && len(errors) > 1 {
+		return errors[:1:1]
+	}
+	return errors
+}
+
+// missingQueryParams reports required fields whose query parameter is absent or empty
+func (s *Schema) missingQueryParams(queryParams url.Values) ValidationErrors {
+	var errors ValidationErrors
+	for fieldName, rule := range s.fields {
+		if !rule.required || rule.queryName == "" {
+			continue
+		}
+
+		values, present := queryParams[rule.queryName]
+		if field, ok := s.structField(rule); ok && field.Type.Kind() == reflect.Slice {
+			values = arrayValues(queryParams, rule.queryName)
+			present = len(values) > 0
+		}
+		switch {
+		case !present:
+			errors = append(errors, ValidationError{
+				Field:   fieldName,
+				Tag:     "required",
+				Message: fmt.Sprintf("%s is required", fieldName),
+			})
+		case len(values) == 0 || values[0] == "":
+			errors = append(errors, ValidationError{
+				Field:   fieldName,
+				Value:   "",
+				Tag:     "required",
+				Message: fmt.Sprintf("%s must not be empty", fieldName),
+			})
+		}
+	}
+	return errors
+}
+
 // parseValidationTag parses validation rules from struct tag
 func parseValidationTag(tag string) fieldRule {
 	rule := fieldRule{
@@ -114,46 +565,204 @@ func parseValidationTag(tag string) fieldRule {
 		return rule
 	}
 
-	rules := strings.Split(tag, ",")
+	rules := splitRules(tag)
 	for _, r := range rules {
 		r = strings.TrimSpace(r)
 
-		switch {
-		case r == "required":
-			rule.required = true
-		case r == "email":
-			rule.email = true
-		case strings.HasPrefix(r, "min="):
-			if val, err := strconv.Atoi(r[4:]); err == nil {
-				rule.min = &val
-			}
-		case strings.HasPrefix(r, "max="):
-			if val, err := strconv.Atoi(r[4:]); err == nil {
-				rule.max = &val
+		// Group-tagged rules (e.g. required#create) only apply under Schema.WithGroup
+		if base, groups := splitRuleGroups(r); len(groups) > 0 {
+			if rule.groupRules == nil {
+				rule.groupRules = make(map[string][]string)
 			}
-		case strings.HasPrefix(r, "minlen="):
-			if val, err := strconv.Atoi(r[7:]); err == nil {
-				rule.minLength = val
+			for _, group := range groups {
+				rule.groupRules[group] = append(rule.groupRules[group], base)
 			}
-		case strings.HasPrefix(r, "maxlen="):
-			if val, err := strconv.Atoi(r[7:]); err == nil {
-				rule.maxLength = val
+			continue
+		}
+
+		rule.apply(r)
+	}
+
+	return rule
+}
+
+// splitRules splits a validate tag into its comma-separated rules. Inside an enum rule,
+// commas within single-quoted values don't separate rules (see parseEnumValues).
+func splitRules(tag string) []string {
+	var rules []string
+	start := 0
+	for i := 0; i < len(tag); i++ {
+		switch tag[i] {
+		case '\'':
+			if !strings.HasPrefix(strings.TrimSpace(tag[start:i]), "enum=") {
+				continue
 			}
-		case strings.HasPrefix(r, "pattern="):
-			if regex, err := regexp.Compile(r[8:]); err == nil {
-				rule.pattern = regex
+			if end := enumQuoteEnd(tag, i); end >= 0 {
+				i = end
 			}
-		case strings.HasPrefix(r, "enum="):
-			enumStr := r[5:]
-			rule.enum = strings.Split(enumStr, "|")
+		case ',':
+			rules = append(rules, tag[start:i])
+			start = i + 1
 		}
 	}
+	return append(rules, tag[start:])
+}
 
-	return rule
+// splitUnquoted splits s on sep, ignoring separators within single-quoted enum values
+func splitUnquoted(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if end := enumQuoteEnd(s, i); end >= 0 {
+			i = end
+			continue
+		}
+		if s[i] == sep {
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// enumQuoteEnd returns the index of the quote closing the quoted enum value opened at s[i],
+// or -1 if s[i] doesn't open one. A quote opens a value only at its start (after '=' or '|',
+// or at the start of s) and closes it only at its end (before '|', ',', '#' or the end of s);
+// any other quote is taken literally, as in enum=o'clock|noon.
+func enumQuoteEnd(s string, i int) int {
+	if s[i] != '\'' || i > 0 && s[i-1] != '=' && s[i-1] != '|' {
+		return -1
+	}
+	for j := i + 1; j < len(s); j++ {
+		if s[j] == '\'' && (j == len(s)-1 || strings.IndexByte("|,#", s[j+1]) >= 0) {
+			return j
+		}
+	}
+	return -1
+}
+
+// parseEnumValues splits the values of an enum rule on '|'. A value wrapped in single
+// quotes is taken literally, so it may contain '|', ',' or '#':
+//
+//	validate:"enum='a|b'|c"  // allows "a|b" and "c"
+func parseEnumValues(s string) []string {
+	values := splitUnquoted(s, '|')
+	for i, value := range values {
+		if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			values[i] = value[1 : len(value)-1]
+		}
+	}
+	return values
+}
+
+// splitRuleGroups splits a rule like "required#create#import" into the base rule and its groups.
+// Pattern rules are never split, since a regular expression may contain '#', and a '#' inside
+// a quoted enum value doesn't start a group.
+func splitRuleGroups(r string) (string, []string) {
+	if strings.HasPrefix(r, "pattern=") || !strings.Contains(r, "#") {
+		return r, nil
+	}
+
+	var parts []string
+	if strings.HasPrefix(r, "enum=") {
+		parts = splitUnquoted(r, '#')
+	} else {
+		parts = strings.Split(r, "#")
+	}
+	return parts[0], parts[1:]
 }
 
-// Validate validates a struct against the schema
+// apply adds a single validation rule (e.g. "minlen=3") to the field rule
+func (rule *fieldRule) apply(r string) {
+	switch {
+	case r == "required":
+		rule.required = true
+	case r == "email":
+		rule.email = true
+	case r == "bytelen":
+		rule.byteLength = true
+	case r == "json":
+		rule.jsonText = true
+	case r == "ip", r == "ipv4", r == "ipv6", r == "cidr", r == "mac":
+		rule.netFormat = r
+	case strings.HasPrefix(r, "min="):
+		if val, err := strconv.ParseFloat(r[4:], 64); err == nil {
+			rule.min = &val
+		}
+	case strings.HasPrefix(r, "max="):
+		if val, err := strconv.ParseFloat(r[4:], 64); err == nil {
+			rule.max = &val
+		}
+	case strings.HasPrefix(r, "minlen="):
+		if val, err := strconv.Atoi(r[7:]); err == nil {
+			rule.minLength = val
+		}
+	case strings.HasPrefix(r, "maxlen="):
+		if val, err := strconv.Atoi(r[7:]); err == nil {
+			rule.maxLength = val
+		}
+	case strings.HasPrefix(r, "pattern="):
+		if regex, err := regexp.Compile(r[8:]); err == nil {
+			rule.pattern = regex
+		}
+	case strings.HasPrefix(r, "enum="):
+		rule.enum = parseEnumValues(r[5:])
+	case strings.HasPrefix(r, "required_with="), strings.HasPrefix(r, "required_with_any="):
+		rule.addPresenceCond(presenceCond{fields: ruleFields(r), with: true})
+	case strings.HasPrefix(r, "required_with_all="):
+		rule.addPresenceCond(presenceCond{fields: ruleFields(r), with: true, all: true})
+	case strings.HasPrefix(r, "required_without="), strings.HasPrefix(r, "required_without_any="):
+		rule.addPresenceCond(presenceCond{fields: ruleFields(r)})
+	case strings.HasPrefix(r, "required_without_all="):
+		rule.addPresenceCond(presenceCond{fields: ruleFields(r), all: true})
+	case strings.HasPrefix(r, "sortfields="):
+		rule.sortFields = strings.Split(r[11:], "|")
+	}
+}
+
+// ruleFields returns the |-separated field names after the = of a rule
+func ruleFields(r string) []string {
+	_, fields, _ := strings.Cut(r, "=")
+	return strings.Split(fields, "|")
+}
+
+// Validate validates a struct against the schema.
+// Types implementing FastValidatable are validated by their ValidateFast method instead.
 func (s *Schema) Validate(data any) ValidationErrors {
+	errors, _ := s.validate(nil, data, false, nil)
+	return errors
+}
+
+// ErrValidationAborted is returned by ValidateCtx when the context is done before all fields
+// have been checked. The returned error also wraps the context's error, so
+// errors.Is(err, context.DeadlineExceeded) reports why validation stopped.
+var ErrValidationAborted = errors.New("validation aborted")
+
+// ValidateCtx is Validate for requests with strict deadlines: it checks ctx between fields and
+// stops as soon as ctx is done, returning the errors found so far and an error wrapping
+// ErrValidationAborted. The error is nil when every field was checked.
+//
+//	errs, err := schema.ValidateCtx(ctx.Request.Context(), &payload)
+//	if errors.Is(err, nimbus.ErrValidationAborted) {
+//	    return nil, http.StatusServiceUnavailable, nimbus.NewAPIError("timeout", "Request deadline exceeded")
+//	}
+func (s *Schema) ValidateCtx(ctx context.Context, data any) (ValidationErrors, error) {
+	return s.validate(ctx, data, false, nil)
+}
+
+// ValidatePartial validates only the fields in present, for PATCH requests that send just
+// the fields they change. Rules of absent fields, including required, are skipped; a present
+// field must still satisfy all of its rules, so sending "" for a required field fails.
+//
+// The presence set typically comes from ValidateJSONPartial or Context.BodyFields.
+func (s *Schema) ValidatePartial(data any, present FieldSet) ValidationErrors {
+	errors, _ := s.validate(nil, data, true, present)
+	return errors
+}
+
+// validate checks data against the schema, restricted to the fields in present when partial is set.
+// A non-nil ctx is checked between fields, aborting validation once it is done.
+func (s *Schema) validate(ctx context.Context, data any, partial bool, present FieldSet) (ValidationErrors, error) {
 	var errors ValidationErrors
 
 	v := reflect.ValueOf(data)
@@ -165,12 +774,57 @@ func (s *Schema) Validate(data any) ValidationErrors {
 		return ValidationErrors{{
 			Field:   "root",
 			Message: "expected struct type",
-		}}
+		}}, nil
 	}
 
+	// A schema imported from a JSON Schema has no struct whose fields the rules map to
+	if s.structType == nil {
+		return ValidationErrors{{
+			Field:   "root",
+			Message: "schema has no struct type; validate decoded data with ValidateMap",
+		}}, nil
+	}
+
+	// Partial validation needs the per-field rules, so it always takes the reflective path
+	if fast, ok := data.(FastValidatable); ok && !partial {
+		return s.firstError(fast.ValidateFast()), nil
+	}
+
+	// Field values for conditional requiredness (built lazily, only if a rule needs them)
+	var allFields map[string]any
+
 	// Check each field in the schema
 	for fieldName, rule := range s.fields {
-		fieldValue := v.FieldByName(getStructFieldName(s.structType, fieldName))
+		if ctx != nil && ctx.Err() != nil {
+			return errors, fmt.Errorf("%w: %w", ErrValidationAborted, ctx.Err())
+		}
+
+		if partial && !present.Has(fieldName) {
+			continue
+		}
+
+		if rule.requiredWhen != nil && !rule.required {
+			if allFields == nil {
+				allFields = s.fieldValues(v)
+			}
+			rule.required = rule.requiredWhen(allFields)
+		}
+
+		// Sibling presence comes from the JSON body when known, otherwise from non-zero values
+		if len(rule.requiredPresence) > 0 && !rule.required {
+			if allFields == nil {
+				allFields = s.fieldValues(v)
+			}
+			rule.required = rule.requiredByPresence(func(field string) bool {
+				if present != nil {
+					return present.Has(field)
+				}
+				value, ok := allFields[field]
+				return ok && value != nil && !reflect.ValueOf(value).IsZero()
+			})
+		}
+
+		fieldValue := s.fieldValue(v, fieldName, rule)
 
 		if !fieldValue.IsValid() {
 			if rule.required {
@@ -180,22 +834,141 @@ func (s *Schema) Validate(data any) ValidationErrors {
 					Message: fmt.Sprintf("%s is required", fieldName),
 				})
 			}
-			continue
+		} else if fieldErrors := s.validateField(fieldName, fieldValue.Interface(), rule); len(fieldErrors) > 0 {
+			errors = append(errors, fieldErrors...)
+		}
+
+		if s.failFast && len(errors) > 0 {
+			return s.firstError(errors), nil
+		}
+	}
+
+	return errors, nil
+}
+
+// ValidateMap validates already-decoded data (e.g. passthrough JSON) against the schema's
+// field rules without binding it to a struct. Keys are JSON names; values are checked
+// against the kind of the schema's struct field, so "age": "twenty" reports a type error.
+func (s *Schema) ValidateMap(data map[string]any) ValidationErrors {
+	var errors ValidationErrors
+
+	for fieldName, rule := range s.fields {
+		if rule.requiredWhen != nil && !rule.required {
+			rule.required = rule.requiredWhen(data)
+		}
+		if len(rule.requiredPresence) > 0 && !rule.required {
+			rule.required = rule.requiredByPresence(func(field string) bool {
+				return data[field] != nil
+			})
 		}
 
-		// Validate the field
-		if fieldErrors := s.validateField(fieldName, fieldValue.Interface(), rule); len(fieldErrors) > 0 {
+		value, exists := data[fieldName]
+		if !exists {
+			if rule.required {
+				errors = append(errors, ValidationError{
+					Field:   fieldName,
+					Tag:     "required",
+					Message: fmt.Sprintf("%s is required", fieldName),
+				})
+			}
+		} else if typeError, ok := s.checkMapValueType(fieldName, rule, value); !ok {
+			errors = append(errors, typeError)
+		} else if fieldErrors := s.validateField(fieldName, value, rule); len(fieldErrors) > 0 {
 			errors = append(errors, fieldErrors...)
 		}
+
+		if s.failFast && len(errors) > 0 {
+			return s.firstError(errors)
+		}
 	}
 
 	return errors
 }
 
+// validateDocument validates a decoded JSON object with ValidateMap, keeping only the errors
+// of the fields in present when partial is set
+func (s *Schema) validateDocument(document map[string]any, partial bool, present FieldSet) ValidationErrors {
+	errors := s.ValidateMap(document)
+	if !partial {
+		return errors
+	}
+
+	var kept ValidationErrors
+	for _, err := range errors {
+		if present.Has(err.Field) {
+			kept = append(kept, err)
+		}
+	}
+	return kept
+}
+
+// checkMapValueType reports whether a raw map value fits the kind of the struct field it
+// stands in for. Only strings and numbers are checked; other kinds are passed through.
+// Fields imported from a JSON Schema are checked against their declared type instead.
+func (s *Schema) checkMapValueType(fieldName string, rule fieldRule, value any) (ValidationError, bool) {
+	if value == nil {
+		return ValidationError{}, true
+	}
+	if rule.valueType != "" {
+		return checkJSONType(fieldName, rule.valueType, value)
+	}
+
+	field, found := s.structField(rule)
+	if !found {
+		return ValidationError{}, true
+	}
+
+	kind := field.Type.Kind()
+	if kind == reflect.Ptr {
+		kind = field.Type.Elem().Kind()
+	}
+
+	switch kind {
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			return ValidationError{
+				Field:   fieldName,
+				Value:   value,
+				Tag:     "type",
+				Message: fmt.Sprintf("%s must be a string", fieldName),
+			}, false
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if _, ok := convertToInt(value); !ok {
+			return ValidationError{
+				Field:   fieldName,
+				Value:   value,
+				Tag:     "type",
+				Message: fmt.Sprintf("%s must be a number", fieldName),
+			}, false
+		}
+	}
+
+	return ValidationError{}, true
+}
+
+// fieldValues collects the value of every schema field present on the struct, keyed by JSON name
+func (s *Schema) fieldValues(v reflect.Value) map[string]any {
+	values := make(map[string]any, len(s.fields))
+	for fieldName, rule := range s.fields {
+		if fieldValue := s.fieldValue(v, fieldName, rule); fieldValue.IsValid() {
+			values[fieldName] = fieldValue.Interface()
+		}
+	}
+	return values
+}
+
 // validateField validates a single field against its rule
 func (s *Schema) validateField(fieldName string, value any, rule fieldRule) ValidationErrors {
 	var errors ValidationErrors
 
+	// A custom-only field skips the built-in rules
+	if rule.customOnly {
+		return rule.customErrors(fieldName, value)
+	}
+
 	// Handle nil/empty values
 	if value == nil || (reflect.ValueOf(value).Kind() == reflect.String && value.(string) == "") {
 		if rule.required {
@@ -211,21 +984,29 @@ func (s *Schema) validateField(fieldName string, value any, rule fieldRule) Vali
 
 	// String validations
 	if str, ok := value.(string); ok {
-		if rule.minLength >= 0 && len(str) < rule.minLength {
+		// Lengths count user-visible characters, so "café" is 4 long rather than 5 bytes
+		length, unit := utf8.RuneCountInString(str), "characters"
+		if rule.byteLength {
+			length, unit = len(str), "bytes"
+		}
+
+		if rule.minLength >= 0 && length < rule.minLength {
 			errors = append(errors, ValidationError{
 				Field:   fieldName,
 				Value:   value,
 				Tag:     "minlen",
-				Message: fmt.Sprintf("%s must be at least %d characters", fieldName, rule.minLength),
+				param:   strconv.Itoa(rule.minLength),
+				Message: fmt.Sprintf("%s must be at least %d %s", fieldName, rule.minLength, unit),
 			})
 		}
 
-		if rule.maxLength >= 0 && len(str) > rule.maxLength {
+		if rule.maxLength >= 0 && length > rule.maxLength {
 			errors = append(errors, ValidationError{
 				Field:   fieldName,
 				Value:   value,
 				Tag:     "maxlen",
-				Message: fmt.Sprintf("%s must be at most %d characters", fieldName, rule.maxLength),
+				param:   strconv.Itoa(rule.maxLength),
+				Message: fmt.Sprintf("%s must be at most %d %s", fieldName, rule.maxLength, unit),
 			})
 		}
 
@@ -237,14 +1018,42 @@ func (s *Schema) validateField(fieldName string, value any, rule fieldRule) Vali
 					Tag:     "email",
 					Message: fmt.Sprintf("%s must be a valid email", fieldName),
 				})
+			} else if rule.emailPolicy != nil {
+				if message := rule.emailPolicy.check(str); message != "" {
+					errors = append(errors, ValidationError{
+						Field:   fieldName,
+						Value:   value,
+						Tag:     "email",
+						Message: fmt.Sprintf("%s %s", fieldName, message),
+					})
+				}
 			}
 		}
 
+		if rule.jsonText && !json.Valid([]byte(str)) {
+			errors = append(errors, ValidationError{
+				Field:   fieldName,
+				Value:   value,
+				Tag:     "json",
+				Message: fmt.Sprintf("%s must be valid JSON", fieldName),
+			})
+		}
+
+		if rule.netFormat != "" && !validNetFormat(rule.netFormat, str) {
+			errors = append(errors, ValidationError{
+				Field:   fieldName,
+				Value:   value,
+				Tag:     rule.netFormat,
+				Message: fmt.Sprintf("%s must be a valid %s", fieldName, netFormatNames[rule.netFormat]),
+			})
+		}
+
 		if rule.pattern != nil && !rule.pattern.MatchString(str) {
 			errors = append(errors, ValidationError{
 				Field:   fieldName,
 				Value:   value,
 				Tag:     "pattern",
+				param:   rule.pattern.String(),
 				Message: fmt.Sprintf("%s format is invalid", fieldName),
 			})
 		}
@@ -262,63 +1071,190 @@ func (s *Schema) validateField(fieldName string, value any, rule fieldRule) Vali
 					Field:   fieldName,
 					Value:   value,
 					Tag:     "enum",
+					param:   strings.Join(rule.enum, ", "),
 					Message: fmt.Sprintf("%s must be one of: %s", fieldName, strings.Join(rule.enum, ", ")),
 				})
 			}
 		}
+
+		// A leading - sorts descending and + ascending; the field itself must be allowed
+		if len(rule.sortFields) > 0 {
+			sortField := str
+			if sortField[0] == '-' || sortField[0] == '+' {
+				sortField = sortField[1:]
+			}
+			if !slices.Contains(rule.sortFields, sortField) {
+				errors = append(errors, ValidationError{
+					Field:   fieldName,
+					Value:   value,
+					Tag:     "sortfields",
+					param:   strings.Join(rule.sortFields, ", "),
+					Message: fmt.Sprintf("%s must sort by one of: %s (prefix with - for descending)", fieldName, strings.Join(rule.sortFields, ", ")),
+				})
+			}
+		}
 	}
 
 	// Numeric validations
-	if num, ok := convertToInt(value); ok {
-		if rule.min != nil && num < *rule.min {
+	if rule.min != nil {
+		if order, ok := compareToBound(value, *rule.min); ok && order < 0 {
 			errors = append(errors, ValidationError{
 				Field:   fieldName,
 				Value:   value,
 				Tag:     "min",
-				Message: fmt.Sprintf("%s must be at least %d", fieldName, *rule.min),
+				param:   formatBound(*rule.min),
+				Message: fmt.Sprintf("%s must be at least %s", fieldName, formatBound(*rule.min)),
 			})
 		}
+	}
 
-		if rule.max != nil && num > *rule.max {
+	if rule.max != nil {
+		if order, ok := compareToBound(value, *rule.max); ok && order > 0 {
 			errors = append(errors, ValidationError{
 				Field:   fieldName,
 				Value:   value,
 				Tag:     "max",
-				Message: fmt.Sprintf("%s must be at most %d", fieldName, *rule.max),
+				param:   formatBound(*rule.max),
+				Message: fmt.Sprintf("%s must be at most %s", fieldName, formatBound(*rule.max)),
 			})
 		}
 	}
 
-	// Custom validation
-	if rule.custom != nil {
-		if err := rule.custom(value); err != nil {
-			errors = append(errors, ValidationError{
-				Field:   fieldName,
-				Value:   value,
-				Tag:     "custom",
-				Message: err.Error(),
-			})
-		}
+	// Custom validation (skipped once a built-in rule failed, when failing fast)
+	if s.failFast && len(errors) > 0 {
+		return errors
 	}
+	errors = append(errors, rule.customErrors(fieldName, value)...)
 
 	return errors
 }
 
-// Helper function to get struct field name from JSON tag
-func getStructFieldName(t reflect.Type, jsonName string) string {
+// customErrors runs the rule's custom validator, if any, on a field value
+func (rule *fieldRule) customErrors(fieldName string, value any) ValidationErrors {
+	if rule.custom == nil {
+		return nil
+	}
+	if err := rule.custom(value); err != nil {
+		return ValidationErrors{{
+			Field:   fieldName,
+			Value:   value,
+			Tag:     "custom",
+			Message: err.Error(),
+		}}
+	}
+	return nil
+}
+
+// fieldValue returns the struct field a schema rule applies to, using the index cached by
+// NewSchema instead of scanning the struct. Values of another struct type (e.g. one embedding
+// the schema's struct) fall back to a lookup by name.
+func (s *Schema) fieldValue(v reflect.Value, fieldName string, rule fieldRule) reflect.Value {
+	if s.structType == nil {
+		return reflect.Value{} // Imported schemas have no fields to read
+	}
+	if v.Type() != s.structType {
+		return v.FieldByName(getStructFieldName(s.structType, fieldName, s.nameTags))
+	}
+	if rule.index == nil {
+		return reflect.Value{}
+	}
+	return v.FieldByIndex(rule.index)
+}
+
+// structField returns the struct field definition a schema rule applies to.
+func (s *Schema) structField(rule fieldRule) (reflect.StructField, bool) {
+	if rule.index == nil {
+		return reflect.StructField{}, false
+	}
+	return s.structType.FieldByIndex(rule.index), true
+}
+
+// tagOrJSONName returns the field's value for the given struct tag, falling back to its JSON name
+func tagOrJSONName(field reflect.StructField, tag, jsonName string) string {
+	if name := field.Tag.Get(tag); name != "" {
+		return name
+	}
+	return jsonName
+}
+
+// schemaFieldName returns the name a schema knows a struct field by: its json tag name, or
+// with nameTags (see WithFieldNameTags) the first of those tags it has, falling back to the
+// Go field name. It reports false for fields the schema skips.
+func schemaFieldName(field reflect.StructField, nameTags []string) (string, bool) {
+	if nameTags == nil {
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "" || jsonTag == "-" {
+			return "", false
+		}
+		return strings.Split(jsonTag, ",")[0], true
+	}
+
+	for _, tag := range nameTags {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return "", false
+		}
+		if name != "" {
+			return name, true
+		}
+	}
+	if !field.IsExported() || field.Anonymous {
+		return "", false
+	}
+	return field.Name, true
+}
+
+// Helper function to get struct field name from its schema name (see schemaFieldName).
+// Fields promoted from untagged embedded structs are found as well, so the returned
+// name can be resolved with FieldByName on the outer struct.
+func getStructFieldName(t reflect.Type, jsonName string, nameTags []string) string {
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
-		jsonTag := field.Tag.Get("json")
-		if jsonTag != "" {
-			tagName := strings.Split(jsonTag, ",")[0]
+		if tagName, ok := schemaFieldName(field, nameTags); ok {
 			if tagName == jsonName {
 				return field.Name
 			}
+		} else if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if name := getStructFieldName(field.Type, jsonName, nameTags); name != "" {
+				return name
+			}
 		}
 	}
 	return ""
 }
 
+// compareToBound compares a numeric value with a min or max bound, returning -1, 0 or +1.
+// Every numeric kind is compared as a float, so 47.6 is above max=47.5 rather than
+// truncated to 47. ok is false if value isn't a number.
+func compareToBound(value any, bound float64) (order int, ok bool) {
+	num, ok := convertToFloat(value)
+	return cmp.Compare(num, bound), ok
+}
+
+// formatBound formats a min or max bound for messages: 18 or 47.5, never 18.000000
+func formatBound(bound float64) string {
+	return strconv.FormatFloat(bound, 'f', -1, 64)
+}
+
+// convertToFloat converts the numeric types to float64
+func convertToFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	default:
+		return 0, false
+	}
+}
+
 // Helper function to convert various numeric types to int
 func convertToInt(value any) (int, bool) {
 	switch v := value.(type) {
@@ -356,6 +1292,23 @@ type ValidatedStruct interface {
 	Validate() error
 }
 
+// FastValidatable is implemented by types that validate themselves without reflection, by
+// hand or with generated code, for hot paths where Schema.Validate's reflection dominates.
+// Schema.Validate (and so ValidateJSON, ValidateQuery, ValidateForm and the typed handler
+// wrappers) calls ValidateFast instead of checking the schema's field rules, so it should
+// report the same errors the validate tags would. ValidatePartial still uses the tags.
+//
+//	func (r *CreateOrderRequest) ValidateFast() nimbus.ValidationErrors {
+//	    var errs nimbus.ValidationErrors
+//	    if r.SKU == "" {
+//	        errs = append(errs, nimbus.ValidationError{Field: "sku", Tag: "required", Message: "sku is required"})
+//	    }
+//	    return errs
+//	}
+type FastValidatable interface {
+	ValidateFast() ValidationErrors
+}
+
 // Validator bundles a validation schema with a factory function for creating instances.
 // This provides a cleaner API by ensuring schema and factory are always paired correctly.
 type Validator[T any] struct {
@@ -364,29 +1317,192 @@ type Validator[T any] struct {
 }
 
 // NewValidator creates a new Validator from an example struct.
-// The factory will create new instances using new(T).
-func NewValidator[T any](example *T) *Validator[T] {
+// The factory will create new instances using new(T). Options are passed to NewSchema.
+func NewValidator[T any](example *T, opts ...SchemaOption) *Validator[T] {
 	return &Validator[T]{
-		Schema:  NewSchema(example),
+		Schema:  NewSchema(example, opts...),
 		Factory: func() *T { return new(T) },
 	}
 }
 
+// FieldSet records which top-level JSON fields were present in a request body, by JSON name.
+// It tells a field sent as its zero value ({"age":0}) apart from an omitted one ({}),
+// which plain structs cannot, so PATCH handlers can skip absent fields.
+type FieldSet map[string]struct{}
+
+// Has reports whether the field with the given JSON name was present
+func (f FieldSet) Has(field string) bool {
+	_, ok := f[field]
+	return ok
+}
+
 // ValidateJSON validates JSON data against a schema and unmarshal it
+// A top-level JSON array binds into a pointer to a slice, with schema describing each element.
 func ValidateJSON(data []byte, target any, schema *Schema) error {
+	_, err := ValidateJSONWithPresence(data, target, schema)
+	return err
+}
+
+// ValidateJSONWithPresence is ValidateJSON that also returns the set of top-level fields
+// present in the JSON object, including fields explicitly set to null or a zero value.
+// The set is nil for top-level arrays and for input that fails to decode.
+func ValidateJSONWithPresence(data []byte, target any, schema *Schema) (FieldSet, error) {
+	return validateJSON(data, target, schema, false)
+}
+
+// ValidateJSONPartial is ValidateJSONWithPresence for PATCH bodies: only the fields present
+// in the JSON object are validated (see Schema.ValidatePartial). Top-level arrays are
+// validated in full.
+func ValidateJSONPartial(data []byte, target any, schema *Schema) (FieldSet, error) {
+	return validateJSON(data, target, schema, true)
+}
+
+// validateJSON unmarshals and validates a JSON object, returning the fields it contained
+func validateJSON(data []byte, target any, schema *Schema, partial bool) (FieldSet, error) {
+	// Top-level arrays bind into a slice, validating each element against the schema
+	if trimmed := bytes.TrimLeft(data, " \t\r\n"); len(trimmed) > 0 && trimmed[0] == '[' {
+		return nil, validateJSONArray(data, target, schema)
+	}
+
 	// First unmarshal into a map to check for missing/extra fields
 	var jsonData map[string]any
 	if err := json.Unmarshal(data, &jsonData); err != nil {
-		return fmt.Errorf("invalid JSON: %w", err)
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	present := make(FieldSet, len(jsonData))
+	for key := range jsonData {
+		present[key] = struct{}{}
 	}
 
 	// Unmarshal into the target struct
+	if err := json.Unmarshal(data, target); err != nil {
+		return nil, fmt.Errorf("JSON unmarshal error: %w", err)
+	}
+
+	// Validate using schema; an imported schema checks the decoded document instead
+	var errors ValidationErrors
+	if schema.structType == nil {
+		errors = schema.validateDocument(jsonData, partial, present)
+	} else {
+		errors, _ = schema.validate(nil, target, partial, present)
+	}
+	if len(errors) > 0 {
+		return present, errors
+	}
+
+	// Check if the struct implements ValidatedStruct for custom validation
+	if validator, ok := target.(ValidatedStruct); ok {
+		if err := validator.Validate(); err != nil {
+			return present, err
+		}
+	}
+
+	return present, nil
+}
+
+// validateJSONArray binds a top-level JSON array into a pointer to a slice and validates
+// each element against the element schema. Errors are reported with indexed field paths,
+// e.g. "[1].email".
+func validateJSONArray(data []byte, target any, schema *Schema) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("invalid JSON: expected an object, got an array")
+	}
+
 	if err := json.Unmarshal(data, target); err != nil {
 		return fmt.Errorf("JSON unmarshal error: %w", err)
 	}
 
+	// An imported schema checks each element as decoded, since it has no struct to read
+	var documents []map[string]any
+	if schema.structType == nil {
+		if err := json.Unmarshal(data, &documents); err != nil {
+			return fmt.Errorf("JSON unmarshal error: %w", err)
+		}
+	}
+
+	slice := v.Elem()
+	var errors ValidationErrors
+	for i := 0; i < slice.Len(); i++ {
+		element := slice.Index(i)
+		if element.Kind() != reflect.Ptr {
+			element = element.Addr()
+		}
+
+		var elementErrors ValidationErrors
+		if documents != nil {
+			elementErrors = schema.ValidateMap(documents[i])
+		} else {
+			elementErrors = schema.Validate(element.Interface())
+		}
+		for _, err := range elementErrors {
+			err.Field = fmt.Sprintf("[%d].%s", i, err.Field)
+			errors = append(errors, err)
+		}
+		if schema.failFast && len(errors) > 0 {
+			break
+		}
+
+		// Check if the element implements ValidatedStruct for custom validation
+		if validator, ok := element.Interface().(ValidatedStruct); ok && len(errors) == 0 {
+			if err := validator.Validate(); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
+
+// ValidateQuery validates query parameters against a schema and binds them to a struct.
+// Slice fields collect every value of their parameter, given as repeated keys (?tag=a&tag=b),
+// with empty brackets (?tag[]=a&tag[]=b) or with indexes (?tag[0]=a&tag[1]=b).
+func ValidateQuery(queryParams url.Values, target any, schema *Schema) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr {
+		return fmt.Errorf("target must be a pointer to struct")
+	}
+
+	v = v.Elem()
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("target must be a pointer to struct")
+	}
+
+	// Reject parameters that don't correspond to any field
+	if schema.strictQuery {
+		if errors := schema.unknownQueryParams(queryParams); len(errors) > 0 {
+			return errors
+		}
+	}
+
+	// Reject ambiguous repeated parameters for scalar fields
+	if schema.uniqueQuery {
+		if errors := schema.duplicateQueryParams(queryParams); len(errors) > 0 {
+			return errors
+		}
+	}
+
+	// Bind query parameters to struct fields (use query tag or json tag)
+	if err := schema.bindValues(queryParams, v, schema.queryKey); err != nil {
+		return err
+	}
+
 	// Validate using schema
-	if errors := schema.Validate(target); len(errors) > 0 {
+	errors := schema.Validate(target)
+	if schema.presentReq {
+		// Report missing parameters in place of the value-based required check
+		if missing := schema.missingQueryParams(queryParams); len(missing) > 0 {
+			errors = slices.DeleteFunc(errors, func(err ValidationError) bool {
+				return missing.Has(err.Field) && err.Tag == "required"
+			})
+			errors = schema.firstError(append(missing, errors...))
+		}
+	}
+	if len(errors) > 0 {
 		return errors
 	}
 
@@ -400,8 +1516,9 @@ func ValidateJSON(data []byte, target any, schema *Schema) error {
 	return nil
 }
 
-// ValidateQuery validates query parameters against a schema and binds them to a struct
-func ValidateQuery(queryParams url.Values, target any, schema *Schema) error {
+// ValidateForm validates URL-encoded form values (e.g. Request.PostForm) against a schema
+// and binds them to a struct. Fields are matched by their form tag, falling back to the JSON name.
+func ValidateForm(form url.Values, target any, schema *Schema) error {
 	v := reflect.ValueOf(target)
 	if v.Kind() != reflect.Ptr {
 		return fmt.Errorf("target must be a pointer to struct")
@@ -412,30 +1529,49 @@ func ValidateQuery(queryParams url.Values, target any, schema *Schema) error {
 		return fmt.Errorf("target must be a pointer to struct")
 	}
 
-	// Bind query parameters to struct fields
-	for fieldName, rule := range schema.fields {
-		structFieldName := getStructFieldName(schema.structType, fieldName)
-		if structFieldName == "" {
-			continue
+	// Bind form values to struct fields (use form tag or json tag)
+	if err := schema.bindValues(form, v, schema.formKey); err != nil {
+		return err
+	}
+
+	// Validate using schema
+	if errors := schema.Validate(target); len(errors) > 0 {
+		return errors
+	}
+
+	// Check if the struct implements ValidatedStruct for custom validation
+	if validator, ok := target.(ValidatedStruct); ok {
+		if err := validator.Validate(); err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
 
-		fieldValue := v.FieldByName(structFieldName)
+// bindValues sets struct fields from URL-encoded values, looking up each field's key with keyFor.
+// Empty values are skipped, leaving the field at its zero value for required checks.
+func (s *Schema) bindValues(values url.Values, v reflect.Value, keyFor func(string, fieldRule) string) error {
+	for fieldName, rule := range s.fields {
+		fieldValue := s.fieldValue(v, fieldName, rule)
 		if !fieldValue.IsValid() || !fieldValue.CanSet() {
 			continue
 		}
 
-		// Get the query parameter value (use query tag or json tag)
-		structField, ok := schema.structType.FieldByName(structFieldName)
-		if !ok {
+		key := keyFor(fieldName, rule)
+		if key == "" {
 			continue
 		}
 
-		queryTag := structField.Tag.Get("query")
-		if queryTag == "" {
-			queryTag = rule.jsonTag
+		// Slices take every value of the parameter, in any of the array syntaxes
+		if fieldValue.Kind() == reflect.Slice {
+			if err := setSliceValue(fieldValue, arrayValues(values, key), rule.layout); err != nil {
+				return fmt.Errorf("error setting field %s: %w", fieldName, err)
+			}
+			continue
 		}
 
-		paramValue := queryParams.Get(queryTag)
+		paramValue := values.Get(key)
 
 		// Skip if empty and not required
 		if paramValue == "" {
@@ -443,28 +1579,173 @@ func ValidateQuery(queryParams url.Values, target any, schema *Schema) error {
 		}
 
 		// Convert and set the value based on field type
-		if err := setFieldValue(fieldValue, paramValue); err != nil {
+		if err := setFieldValue(fieldValue, paramValue, rule.layout); err != nil {
 			return fmt.Errorf("error setting field %s: %w", fieldName, err)
 		}
 	}
 
-	// Validate using schema
-	if errors := schema.Validate(target); len(errors) > 0 {
-		return errors
-	}
+	return nil
+}
 
-	// Check if the struct implements ValidatedStruct for custom validation
-	if validator, ok := target.(ValidatedStruct); ok {
-		if err := validator.Validate(); err != nil {
-			return err
+// arrayValues returns the values of an array parameter in order. Three syntaxes are accepted,
+// and can be mixed: repeated keys (items=a&items=b), empty brackets (items[]=a&items[]=b) and
+// explicit indexes (items[0]=a&items[1]=b). Values of repeated keys come first, then empty
+// brackets, then indexed values sorted by index. Gaps in the indexes are closed up, so
+// items[0]=a&items[5]=b gives [a b], and a repeated index keeps all its values in order.
+func arrayValues(values url.Values, key string) []string {
+	result := slices.Clone(values[key])
+	result = append(result, values[key+"[]"]...)
+
+	type indexed struct {
+		index  int
+		values []string
+	}
+	var entries []indexed
+	for name, vals := range values {
+		if index, ok := arrayIndex(name, key); ok {
+			entries = append(entries, indexed{index, vals})
 		}
 	}
+	slices.SortFunc(entries, func(a, b indexed) int { return a.index - b.index })
+	for _, entry := range entries {
+		result = append(result, entry.values...)
+	}
+
+	return result
+}
+
+// arrayIndex parses the index of an indexed array parameter name such as items[2]
+func arrayIndex(name, key string) (int, bool) {
+	rest, ok := strings.CutPrefix(name, key+"[")
+	if !ok {
+		return 0, false
+	}
+	rest, ok = strings.CutSuffix(rest, "]")
+	if !ok || rest == "" || rest[0] == '+' || rest[0] == '-' {
+		return 0, false
+	}
+	index, err := strconv.Atoi(rest)
+	return index, err == nil
+}
+
+// arrayBaseKey returns the parameter name of an array syntax key: items for items[] and
+// items[0], or the key itself otherwise
+func arrayBaseKey(name string) string {
+	base, rest, ok := strings.Cut(name, "[")
+	if !ok || base == "" {
+		return name
+	}
+	if rest == "]" {
+		return base
+	}
+	if _, ok := arrayIndex(name, base); ok {
+		return base
+	}
+	return name
+}
 
+// setSliceValue sets a slice field to the converted values, leaving it unchanged when there are none
+func setSliceValue(field reflect.Value, values []string, layout string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+	for i, value := range values {
+		if err := setFieldValue(slice.Index(i), value, layout); err != nil {
+			return fmt.Errorf("[%d]: %w", i, err)
+		}
+	}
+	field.Set(slice)
 	return nil
 }
 
-// setFieldValue sets a struct field value from a string
-func setFieldValue(field reflect.Value, value string) error {
+// queryKey returns the query parameter name for a field: its query tag, falling back to the JSON name.
+func (s *Schema) queryKey(fieldName string, rule fieldRule) string {
+	return rule.queryName
+}
+
+// formKey returns the form field name for a field: its form tag, falling back to the JSON name.
+func (s *Schema) formKey(fieldName string, rule fieldRule) string {
+	return rule.formName
+}
+
+// unknownQueryParams returns a validation error for every query key that doesn't map to a schema field
+func (s *Schema) unknownQueryParams(queryParams url.Values) ValidationErrors {
+	known := make(map[string]bool, len(s.fields))
+	arrays := make(map[string]bool) // Slice fields, which also accept the bracket syntaxes
+	for fieldName, rule := range s.fields {
+		known[s.queryKey(fieldName, rule)] = true
+		if field, ok := s.structField(rule); ok && field.Type.Kind() == reflect.Slice {
+			arrays[s.queryKey(fieldName, rule)] = true
+		}
+	}
+
+	var errors ValidationErrors
+	for _, key := range slices.Sorted(maps.Keys(queryParams)) {
+		if !known[key] && !arrays[arrayBaseKey(key)] {
+			errors = append(errors, ValidationError{
+				Field:   key,
+				Value:   queryParams.Get(key),
+				Tag:     "unknown",
+				Message: fmt.Sprintf("%s is not a recognized query parameter", key),
+			})
+		}
+	}
+	return errors
+}
+
+// duplicateQueryParams reports scalar fields whose query parameter was given more than once.
+func (s *Schema) duplicateQueryParams(queryParams url.Values) ValidationErrors {
+	var errors ValidationErrors
+	for _, fieldName := range slices.Sorted(maps.Keys(s.fields)) {
+		rule := s.fields[fieldName]
+		key := s.queryKey(fieldName, rule)
+		if values := queryParams[key]; len(values) > 1 {
+			structField, _ := s.structField(rule)
+			if structField.Type.Kind() == reflect.Slice {
+				continue
+			}
+
+			errors = append(errors, ValidationError{
+				Field:   fieldName,
+				Value:   values,
+				Tag:     "duplicate",
+				Message: fmt.Sprintf("%s must be provided only once", key),
+			})
+		}
+	}
+	return errors
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// setFieldValue sets a struct field value from a string.
+// time.Duration is parsed with time.ParseDuration (e.g. "30s"), and time.Time with layout,
+// defaulting to RFC3339 when the field has no layout tag.
+func setFieldValue(field reflect.Value, value string, layout string) error {
+	switch field.Type() {
+	case durationType:
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration value: %s (expected a duration such as 30s or 1h30m)", value)
+		}
+		field.SetInt(int64(duration))
+		return nil
+	case timeType:
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		parsed, err := time.Parse(layout, value)
+		if err != nil {
+			return fmt.Errorf("invalid time value: %s (expected layout %s)", value, layout)
+		}
+		field.Set(reflect.ValueOf(parsed))
+		return nil
+	}
+
 	switch field.Kind() {
 	case reflect.String:
 		field.SetString(value)
@@ -514,6 +1795,13 @@ func WithBodyValidation[T any](validator *Validator[T]) func(Handler) Handler {
 				if validationErrs, ok := err.(ValidationErrors); ok {
 					return ctx.SendValidationError(validationErrs)
 				}
+				if apiErr, ok := err.(*APIError); ok {
+					return nil, 400, apiErr
+				}
+				var statusErr *StatusError
+				if errors.As(err, &statusErr) {
+					return nil, statusErr.Status, statusErr.APIError
+				}
 				return nil, 400, NewAPIError("invalid_request", err.Error())
 			}
 
@@ -526,6 +1814,58 @@ func WithBodyValidation[T any](validator *Validator[T]) func(Handler) Handler {
 	}
 }
 
+// BodyValidator is a body validator of any type, used to pick between body versions in
+// WithVersionedBody. Every *Validator[T] is a BodyValidator.
+type BodyValidator interface {
+	withBody(handler Handler) Handler
+}
+
+func (v *Validator[T]) withBody(handler Handler) Handler {
+	return WithBodyValidation(v)(handler)
+}
+
+// WithVersionedBody is WithBodyValidation for APIs with several versions of a request body.
+// version resolves the version of each request (from a header, path param, ...) and the
+// matching validator binds the body, which is stored with key ContextKeyValidatedBody.
+// Requests with a version not in validators are rejected with 400.
+//
+// Example selecting by media type version:
+//
+//	router.POST("/users", createUser, nimbus.WithMiddleware(nimbus.WithVersionedBody(
+//	    map[string]nimbus.BodyValidator{"v1": createUserV1, "v2": createUserV2},
+//	    func(ctx *nimbus.Context) string {
+//	        if strings.Contains(ctx.GetHeader("Accept"), "vnd.app.v2") {
+//	            return "v2"
+//	        }
+//	        return "v1"
+//	    },
+//	)))
+//
+// The handler switches on the bound type:
+//
+//	switch body := ctx.MustGet(nimbus.ContextKeyValidatedBody).(type) {
+//	case *CreateUserV1:
+//	case *CreateUserV2:
+//	}
+func WithVersionedBody(validators map[string]BodyValidator, version func(*Context) string) func(Handler) Handler {
+	return func(handler Handler) Handler {
+		// Wrap the handler once per version instead of on every request
+		handlers := make(map[string]Handler, len(validators))
+		for v, validator := range validators {
+			handlers[v] = validator.withBody(handler)
+		}
+
+		return func(ctx *Context) (any, int, error) {
+			v := version(ctx)
+			versioned, ok := handlers[v]
+			if !ok {
+				return nil, 400, NewAPIError("unsupported_version", fmt.Sprintf("unsupported request body version %q", v))
+			}
+			return versioned(ctx)
+		}
+	}
+}
+
 // WithQueryValidation wraps a handler with automatic query parameter validation
 // The validated query params will be stored in the context with key ContextKeyValidatedQuery
 func WithQueryValidation[T any](validator *Validator[T]) func(Handler) Handler {
@@ -558,35 +1898,55 @@ func populatePathParams(pathParams map[string]string, target any) error {
 		return fmt.Errorf("target must be a pointer to a struct")
 	}
 
-	val = val.Elem()
-	typ := val.Type()
+	return bindPathParams(pathParams, val.Elem(), pathFieldsOf(val.Elem().Type()))
+}
+
+// populatePathParamsWithSchema is populatePathParams using the path fields cached in the
+// validator's schema, avoiding a per-request scan of the struct's tags.
+func populatePathParamsWithSchema(pathParams map[string]string, target any, schema *Schema) error {
+	val := reflect.ValueOf(target)
+	if schema == nil || val.Kind() != reflect.Ptr || val.Elem().Type() != schema.structType {
+		return populatePathParams(pathParams, target)
+	}
+
+	return bindPathParams(pathParams, val.Elem(), schema.pathFields)
+}
 
-	for i := 0; i < val.NumField(); i++ {
-		field := val.Field(i)
-		fieldType := typ.Field(i)
+// pathFieldsOf returns the exported fields of a struct type that have a path tag
+func pathFieldsOf(t reflect.Type) []pathField {
+	var fields []pathField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
 
 		// Skip unexported fields
-		if !field.CanSet() {
+		if !field.IsExported() {
 			continue
 		}
 
 		// Get the path tag
-		pathTag := fieldType.Tag.Get("path")
-		if pathTag == "" {
-			continue
+		if pathTag := field.Tag.Get("path"); pathTag != "" {
+			fields = append(fields, pathField{index: i, param: pathTag})
 		}
+	}
+	return fields
+}
+
+// bindPathParams sets the given path fields of a struct value from path parameters
+func bindPathParams(pathParams map[string]string, val reflect.Value, fields []pathField) error {
+	for _, pf := range fields {
+		field := val.Field(pf.index)
 
 		// Get the value from path params
-		paramValue, exists := pathParams[pathTag]
+		paramValue, exists := pathParams[pf.param]
 		if !exists {
-			return fmt.Errorf("required path parameter '%s' not found", pathTag)
+			return fmt.Errorf("required path parameter '%s' not found", pf.param)
 		}
 
 		// Set the field value
 		if field.Kind() == reflect.String {
 			field.SetString(paramValue)
 		} else {
-			return fmt.Errorf("path parameter '%s' has unsupported type %s (only string is supported)", pathTag, field.Kind())
+			return fmt.Errorf("path parameter '%s' has unsupported type %s (only string is supported)", pf.param, field.Kind())
 		}
 	}
 
@@ -612,7 +1972,7 @@ func WithPathParams[T any](validator *Validator[T]) func(Handler) Handler {
 			}
 
 			// Extract path parameters and populate the struct
-			if err := populatePathParams(ctx.PathParams, params); err != nil {
+			if err := populatePathParamsWithSchema(ctx.PathParams, params, validator.Schema); err != nil {
 				return nil, 400, NewAPIError("invalid_path_params", err.Error())
 			}
 
@@ -691,7 +2051,7 @@ func WithTyped[P any, B any, Q any](
 			if paramsPtr == nil {
 				return nil, 400, NewAPIError("invalid_request", "params factory returned nil")
 			}
-			if err := populatePathParams(ctx.PathParams, paramsPtr); err != nil {
+			if err := populatePathParamsWithSchema(ctx.PathParams, paramsPtr, params.Schema); err != nil {
 				return nil, 400, NewAPIError("invalid_path_params", err.Error())
 			}
 			ctx.Set(ContextKeyValidatedParams, paramsPtr)
@@ -704,6 +2064,16 @@ func WithTyped[P any, B any, Q any](
 				return nil, 400, NewAPIError("invalid_request", "body factory returned nil")
 			}
 			if err := ctx.BindAndValidateJSON(bodyPtr, body.Schema); err != nil {
+				if validationErrs, ok := err.(ValidationErrors); ok {
+					return ctx.SendValidationError(validationErrs)
+				}
+				if apiErr, ok := err.(*APIError); ok {
+					return nil, 400, apiErr
+				}
+				var statusErr *StatusError
+				if errors.As(err, &statusErr) {
+					return nil, statusErr.Status, statusErr.APIError
+				}
 				return nil, 400, NewAPIError("invalid_request", err.Error())
 			}
 			ctx.Set(ContextKeyValidatedBody, bodyPtr)
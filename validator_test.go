@@ -1,9 +1,18 @@
 package nimbus
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 )
 
 // Test structs for schema validation
@@ -68,6 +77,124 @@ func TestNewSchema(t *testing.T) {
 	}
 }
 
+// bindingUser mirrors TestUser's rules under the binding tag, with different rules left
+// under validate to show that tag is ignored
+type bindingUser struct {
+	Name     string `json:"name" binding:"required,minlen=2,maxlen=50" validate:"maxlen=1"`
+	Email    string `json:"email" binding:"required,email"`
+	Age      int    `json:"age" binding:"min=18,max=120"`
+	Role     string `json:"role" binding:"enum=user|admin|moderator"`
+	Password string `json:"password" binding:"required,minlen=8"`
+}
+
+func TestNewSchema_WithValidationTag(t *testing.T) {
+	validateSchema := NewSchema(TestUser{})
+	bindingSchema := NewSchema(bindingUser{}, WithValidationTag("binding"))
+
+	tests := []struct {
+		name string
+		user TestUser
+	}{
+		{"valid", TestUser{Name: "Ada", Email: "ada@example.com", Age: 36, Role: "admin", Password: "analytical"}},
+		{"missing required", TestUser{Age: 36}},
+		{"out of bounds", TestUser{Name: "A", Email: "not-an-email", Age: 12, Role: "owner", Password: "short"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := validateSchema.Validate(&tt.user)
+			got := bindingSchema.Validate(&bindingUser{
+				Name: tt.user.Name, Email: tt.user.Email, Age: tt.user.Age, Role: tt.user.Role, Password: tt.user.Password,
+			})
+
+			describe := func(errs ValidationErrors) []string {
+				var described []string
+				for _, e := range errs {
+					described = append(described, e.Field+":"+e.Tag+":"+e.Message)
+				}
+				slices.Sort(described)
+				return described
+			}
+			if !slices.Equal(describe(expected), describe(got)) {
+				t.Errorf("Expected the same errors as the validate tag:\n  %v\ngot:\n  %v", describe(expected), describe(got))
+			}
+		})
+	}
+
+	validator := NewValidator(&bindingUser{}, WithValidationTag("binding"))
+	if errs := validator.Schema.Validate(&bindingUser{Name: "Ada"}); !errs.Has("email") || errs.Has("name") {
+		t.Errorf("Expected NewValidator to read the binding tag, got %v", errs)
+	}
+}
+
+// searchForm has no json tags, only form tags, like structs shared with form-binding libraries
+type searchForm struct {
+	Term     string `form:"q" validate:"required,minlen=2"`
+	Limit    int    `form:"limit" validate:"min=1,max=100"`
+	Category string `form:"category" query:"cat" validate:"enum=books|music"`
+	Internal string `form:"-"`
+	Exact    bool
+}
+
+func TestNewSchema_WithFieldNameTags(t *testing.T) {
+	schema := NewSchema(&searchForm{}, WithFieldNameTags("json", "form"))
+
+	if fields := slices.Sorted(maps.Keys(schema.fields)); !slices.Equal(fields, []string{"Exact", "category", "limit", "q"}) {
+		t.Fatalf("Expected fields named by form tags and the field name, got %v", fields)
+	}
+	if len(NewSchema(&searchForm{}).fields) != 0 {
+		t.Error("Expected the default schema to skip fields without json tags")
+	}
+
+	t.Run("validation", func(t *testing.T) {
+		errs := schema.Validate(&searchForm{Term: "a", Limit: 500, Category: "films"})
+		for _, field := range []string{"q", "limit", "category"} {
+			if !errs.Has(field) {
+				t.Errorf("Expected an error for %s, got %v", field, errs)
+			}
+		}
+		if errs := schema.ValidateMap(map[string]any{"q": "go", "limit": 10}); len(errs) != 0 {
+			t.Errorf("Expected ValidateMap to use form names, got %v", errs)
+		}
+	})
+
+	t.Run("query binding", func(t *testing.T) {
+		var form searchForm
+		query := url.Values{"q": {"golang"}, "limit": {"20"}, "cat": {"books"}, "Exact": {"true"}, "Internal": {"x"}}
+		if err := ValidateQuery(query, &form, schema); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		expected := searchForm{Term: "golang", Limit: 20, Category: "books", Exact: true}
+		if form != expected {
+			t.Errorf("Expected %+v, got %+v", expected, form)
+		}
+	})
+
+	t.Run("form binding", func(t *testing.T) {
+		var form searchForm
+		err := ValidateForm(url.Values{"q": {"x"}, "limit": {"5"}}, &form, schema)
+		errs, ok := err.(ValidationErrors)
+		if !ok || !errs.Has("q") {
+			t.Fatalf("Expected a q validation error, got %v", err)
+		}
+		if form.Limit != 5 {
+			t.Errorf("Expected limit bound from the form, got %d", form.Limit)
+		}
+	})
+
+	t.Run("grouped schema", func(t *testing.T) {
+		// A struct embedding the form is read by field name, which needs the name tags
+		type advancedSearch struct {
+			searchForm
+			Sort string
+		}
+		errs := schema.WithGroup("admin").Validate(&advancedSearch{searchForm: searchForm{Term: "golang", Limit: 500}})
+		if len(errs) != 1 || !errs.Has("limit") {
+			t.Errorf("Expected WithGroup to keep the name tags and report limit, got %v", errs)
+		}
+	})
+}
+
 func TestSchema_Validate_Success(t *testing.T) {
 	schema := NewSchema(TestUser{})
 
@@ -134,6 +261,96 @@ func TestSchema_Validate_Email(t *testing.T) {
 	}
 }
 
+type TestWebhook struct {
+	URL      string `json:"url" validate:"required"`
+	Metadata string `json:"metadata" validate:"json"`
+	Payload  string `json:"payload" validate:"required,json,maxlen=20"`
+}
+
+func TestSchema_Validate_JSON(t *testing.T) {
+	schema := NewSchema(TestWebhook{})
+
+	tests := []struct {
+		name     string
+		webhook  TestWebhook
+		expected []string // "field:tag" for each expected error
+	}{
+		{"valid object", TestWebhook{URL: "https://example.com", Metadata: `{"team": "billing", "tags": [1, 2]}`, Payload: `{}`}, nil},
+		{"valid scalars", TestWebhook{URL: "https://example.com", Metadata: `"text"`, Payload: `null`}, nil},
+		{"empty optional skipped", TestWebhook{URL: "https://example.com", Payload: `[]`}, nil},
+		{"malformed", TestWebhook{URL: "https://example.com", Metadata: `{"team": billing}`, Payload: `[1, 2`}, []string{"metadata:json", "payload:json"}},
+		{"not JSON at all", TestWebhook{URL: "https://example.com", Metadata: `team=billing`, Payload: `{}`}, []string{"metadata:json"}},
+		{"empty required", TestWebhook{URL: "https://example.com"}, []string{"payload:required"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := schema.Validate(tt.webhook)
+
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Field+":"+e.Tag)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("Expected errors %v, got %v", tt.expected, errs)
+			}
+		})
+	}
+
+	errs := schema.Validate(TestWebhook{URL: "https://example.com", Payload: "{"})
+	if got := errs.ForField("payload"); len(got) != 1 || got[0].Message != "payload must be valid JSON" {
+		t.Errorf("Expected payload must be valid JSON, got %v", got)
+	}
+}
+
+type TestNetworkConfig struct {
+	Gateway string `json:"gateway" validate:"ip"`
+	Host    string `json:"host" validate:"ipv4"`
+	Peer    string `json:"peer" validate:"ipv6"`
+	Subnet  string `json:"subnet" validate:"cidr"`
+	NIC     string `json:"nic" validate:"mac"`
+}
+
+func TestSchema_Validate_NetworkFormats(t *testing.T) {
+	schema := NewSchema(TestNetworkConfig{})
+
+	tests := []struct {
+		name     string
+		config   TestNetworkConfig
+		expected []string // "field:tag" for each expected error
+	}{
+		{"valid", TestNetworkConfig{Gateway: "10.0.0.1", Host: "192.168.1.20", Peer: "2001:db8::1", Subnet: "10.0.0.0/16", NIC: "00:1a:2b:3c:4d:5e"}, nil},
+		{"ip accepts IPv6", TestNetworkConfig{Gateway: "fe80::1", Subnet: "2001:db8::/32", NIC: "00-1A-2B-3C-4D-5E"}, nil},
+		{"empty skipped", TestNetworkConfig{}, nil},
+		{"malformed", TestNetworkConfig{Gateway: "10.0.0.256", Host: "localhost", Peer: "2001:db8:::1", Subnet: "10.0.0.0/33", NIC: "00:1a:2b:3c:4d"}, []string{"gateway:ip", "host:ipv4", "nic:mac", "peer:ipv6", "subnet:cidr"}},
+		{"IPv6 against ipv4", TestNetworkConfig{Host: "2001:db8::1"}, []string{"host:ipv4"}},
+		{"IPv4-mapped IPv6 against ipv4", TestNetworkConfig{Host: "::ffff:192.0.2.1"}, []string{"host:ipv4"}},
+		{"IPv4 against ipv6", TestNetworkConfig{Peer: "192.0.2.1"}, []string{"peer:ipv6"}},
+		{"address without prefix against cidr", TestNetworkConfig{Subnet: "10.0.0.0"}, []string{"subnet:cidr"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := schema.Validate(tt.config)
+
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Field+":"+e.Tag)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("Expected errors %v, got %v", tt.expected, errs)
+			}
+		})
+	}
+
+	errs := schema.Validate(TestNetworkConfig{Subnet: "10.0.0.0"})
+	if got := errs.ForField("subnet"); len(got) != 1 || got[0].Message != "subnet must be a valid CIDR block" {
+		t.Errorf("Expected subnet must be a valid CIDR block, got %v", got)
+	}
+}
+
 func TestSchema_Validate_MinMax(t *testing.T) {
 	schema := NewSchema(TestUser{})
 
@@ -529,6 +746,135 @@ func TestValidationErrors_Error(t *testing.T) {
 	}
 }
 
+type TestQuotedEnum struct {
+	Separator string `json:"separator" validate:"required,enum='|'|','|tab"`
+	Channel   string `json:"channel" validate:"enum='#general#ops'|random"`
+}
+
+func TestValidate_QuotedEnumValues(t *testing.T) {
+	schema := NewSchema(TestQuotedEnum{})
+
+	if got := schema.fields["separator"].enum; !slices.Equal(got, []string{"|", ",", "tab"}) {
+		t.Errorf("Expected enum [| , tab], got %q", got)
+	}
+	if !schema.fields["separator"].required {
+		t.Error("Expected rules after a quoted enum to still apply")
+	}
+	if got := schema.fields["channel"].enum; !slices.Equal(got, []string{"#general#ops", "random"}) {
+		t.Errorf("Expected quoted '#' not to start a group, got %q", got)
+	}
+
+	for _, separator := range []string{"|", ",", "tab"} {
+		if errs := schema.Validate(TestQuotedEnum{Separator: separator}); len(errs) != 0 {
+			t.Errorf("Expected %q to be allowed, got %v", separator, errs)
+		}
+	}
+
+	// The quoted pipe is a single value, not a separator between empty values
+	for _, separator := range []string{"'|'", "';'", "|,"} {
+		errs := schema.Validate(TestQuotedEnum{Separator: separator})
+		if len(errs) != 1 || errs[0].Tag != "enum" {
+			t.Errorf("Expected enum error for %q, got %v", separator, errs)
+		}
+	}
+}
+
+type TestApostropheEnum struct {
+	Slot     string `json:"slot" validate:"enum=o'clock|noon"`
+	Reminder string `json:"reminder" validate:"enum=o'clock|noon,required"`
+}
+
+func TestValidate_EnumApostrophesAreLiteral(t *testing.T) {
+	schema := NewSchema(TestApostropheEnum{})
+
+	for _, field := range []string{"slot", "reminder"} {
+		if got := schema.fields[field].enum; !slices.Equal(got, []string{"o'clock", "noon"}) {
+			t.Errorf("Expected %s enum [o'clock noon], got %q", field, got)
+		}
+	}
+	if !schema.fields["reminder"].required {
+		t.Error("Expected the rule after an enum with an apostrophe to still apply")
+	}
+
+	for _, value := range []string{"o'clock", "noon"} {
+		if errs := schema.Validate(TestApostropheEnum{Slot: value, Reminder: value}); len(errs) != 0 {
+			t.Errorf("Expected %q to be allowed, got %v", value, errs)
+		}
+	}
+	if errs := schema.Validate(TestApostropheEnum{Slot: "dusk"}); !errs.Has("slot") || !errs.Has("reminder") {
+		t.Errorf("Expected slot enum and reminder required errors, got %v", errs)
+	}
+}
+
+func TestValidationErrors_FieldLookups(t *testing.T) {
+	errs := ValidationErrors{
+		{Field: "email", Tag: "required", Message: "email is required"},
+		{Field: "age", Tag: "min", Message: "age must be at least 18"},
+		{Field: "email", Tag: "email", Message: "email must be a valid email address"},
+	}
+
+	emailErrs := errs.ForField("email")
+	if len(emailErrs) != 2 || emailErrs[0].Tag != "required" || emailErrs[1].Tag != "email" {
+		t.Errorf("Expected both email errors in order, got %v", emailErrs)
+	}
+	if ageErrs := errs.ForField("age"); len(ageErrs) != 1 || ageErrs[0].Tag != "min" {
+		t.Errorf("Expected one age error, got %v", ageErrs)
+	}
+	if nameErrs := errs.ForField("name"); len(nameErrs) != 0 {
+		t.Errorf("Expected no name errors, got %v", nameErrs)
+	}
+
+	if !errs.Has("email") || !errs.Has("age") {
+		t.Error("Expected Has to report fields with errors")
+	}
+	if errs.Has("name") {
+		t.Error("Expected Has to be false for a field without errors")
+	}
+
+	if fields := errs.Fields(); !slices.Equal(fields, []string{"email", "age"}) {
+		t.Errorf("Expected fields [email age], got %v", fields)
+	}
+	if fields := (ValidationErrors{}).Fields(); len(fields) != 0 {
+		t.Errorf("Expected no fields for empty errors, got %v", fields)
+	}
+}
+
+func TestValidationErrors_Add(t *testing.T) {
+	var errs ValidationErrors
+	errs.Add("amount", "balance", "amount exceeds the available balance")
+	errs = append(errs, NewFieldError("currency", "supported", "currency is not supported"))
+
+	if got := errs.Error(); got != "validation failed on 2 fields" {
+		t.Errorf("Expected 'validation failed on 2 fields', got %q", got)
+	}
+	if fields := errs.Fields(); !slices.Equal(fields, []string{"amount", "currency"}) {
+		t.Errorf("Expected fields [amount currency], got %v", fields)
+	}
+	if got := errs[:1].Error(); got != "amount exceeds the available balance" {
+		t.Errorf("Expected the single error's message, got %q", got)
+	}
+
+	// Returned from a handler, they render like the framework's own validation errors
+	router := NewRouter()
+	router.POST("/transfers", func(ctx *Context) (any, int, error) {
+		return nil, 0, errs
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/transfers", nil))
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status 422, got %d", w.Code)
+	}
+	expected := `{"details":[` +
+		`{"field":"amount","value":null,"tag":"balance","message":"amount exceeds the available balance"},` +
+		`{"field":"currency","value":null,"tag":"supported","message":"currency is not supported"}],` +
+		`"error":"validation_failed","message":"Request validation failed"}`
+	if got := strings.TrimSpace(w.Body.String()); got != expected {
+		t.Errorf("Expected body %s, got %s", expected, got)
+	}
+}
+
 // Test structs for query parameter validation
 type TestSearchQuery struct {
 	Query    string `json:"query" validate:"required,minlen=2,maxlen=100"`
@@ -686,6 +1032,88 @@ func TestValidateQuery_MinMaxValidation(t *testing.T) {
 	}
 }
 
+type TestGeoQuery struct {
+	Lat    float64 `json:"lat" validate:"min=-90,max=90"`
+	Lng    float64 `json:"lng" validate:"min=-180,max=180"`
+	Radius float32 `json:"radius" validate:"min=1,max=50"`
+}
+
+func TestValidateQuery_FloatMinMax(t *testing.T) {
+	schema := NewSchema(TestGeoQuery{})
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string // "field:tag" for each expected error
+	}{
+		{"within bounds", "lat=47.6&lng=-122.3&radius=2.5", nil},
+		{"on the bounds", "lat=90&lng=-180&radius=50", nil},
+		{"just past max", "lat=90.5&lng=180.01&radius=50.5", []string{"lat:max", "lng:max", "radius:max"}},
+		{"just past min", "lat=-90.5&lng=-180.01&radius=0.5", []string{"lat:min", "lng:min", "radius:min"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(tt.query)
+
+			var target TestGeoQuery
+			err := ValidateQuery(query, &target, schema)
+
+			var got []string
+			if errs, ok := err.(ValidationErrors); ok {
+				for _, e := range errs {
+					got = append(got, e.Field+":"+e.Tag)
+				}
+			} else if err != nil {
+				t.Fatalf("Expected ValidationErrors, got %T: %v", err, err)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("Expected errors %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	// Decoded JSON numbers are float64 too
+	errs := schema.ValidateMap(map[string]any{"lat": 90.25, "lng": 0.0, "radius": 10.0})
+	if len(errs) != 1 || errs[0].Field != "lat" || errs[0].Message != "lat must be at most 90" {
+		t.Errorf("Expected lat must be at most 90, got %v", errs)
+	}
+}
+
+type TestFractionalBoundsQuery struct {
+	Above  float64 `json:"above" query:"lat" validate:"min=47.7"`
+	Below  float64 `json:"below" query:"lat" validate:"max=47.5"`
+	Within float64 `json:"within" query:"lat" validate:"min=47.5,max=47.7"`
+	Floor  int     `json:"floor" validate:"min=0.5"`
+}
+
+func TestValidateQuery_FractionalBounds(t *testing.T) {
+	schema := NewSchema(TestFractionalBoundsQuery{})
+
+	query, _ := url.ParseQuery("lat=47.6&floor=0")
+	var target TestFractionalBoundsQuery
+	err := ValidateQuery(query, &target, schema)
+
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatalf("Expected ValidationErrors, got %T: %v", err, err)
+	}
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Field+":"+e.Tag+":"+e.Message)
+	}
+	slices.Sort(got)
+	expected := []string{
+		"above:min:above must be at least 47.7",
+		"below:max:below must be at most 47.5",
+		"floor:min:floor must be at least 0.5",
+	}
+	if !slices.Equal(got, expected) {
+		t.Errorf("Expected errors %v, got %v", expected, got)
+	}
+}
+
 func TestValidateQuery_StringLengthValidation(t *testing.T) {
 	schema := NewSchema(TestSearchQuery{})
 
@@ -1031,3 +1459,1431 @@ func TestAddCustomValidator_CombinedWithBuiltIn(t *testing.T) {
 		t.Error("Expected custom validation error for username")
 	}
 }
+
+func TestAddCustomValidatorOnly_SkipsBuiltIn(t *testing.T) {
+	schema := NewSchema(TestCustomUser{})
+
+	// Short handles like "al" are allowed for staff, which minlen=3 can't express
+	schema.AddCustomValidatorOnly("username", func(value any) error {
+		username, _ := value.(string)
+		if username == "" {
+			return errors.New("username is required")
+		}
+		if len(username) < 3 && !strings.HasPrefix(username, "a") {
+			return errors.New("username is too short")
+		}
+		return nil
+	})
+
+	if errs := schema.Validate(TestCustomUser{Username: "al", Password: "password123"}); len(errs) != 0 {
+		t.Errorf("Expected built-in minlen to be skipped, got %v", errs)
+	}
+
+	errs := schema.Validate(TestCustomUser{Username: "bo", Password: "password123"})
+	if len(errs) != 1 || errs[0].Tag != "custom" || errs[0].Message != "username is too short" {
+		t.Errorf("Expected only the custom error, got %v", errs)
+	}
+
+	// The custom function also owns requiredness
+	errs = schema.Validate(TestCustomUser{Password: "password123"})
+	if len(errs) != 1 || errs[0].Tag != "custom" {
+		t.Errorf("Expected the custom error for an empty username, got %v", errs)
+	}
+
+	// Other fields keep their built-in rules
+	errs = schema.Validate(TestCustomUser{Username: "al", Password: "short"})
+	if len(errs) != 1 || errs[0].Field != "password" || errs[0].Tag != "minlen" {
+		t.Errorf("Expected password minlen error, got %v", errs)
+	}
+}
+
+// Test structs for schema merging
+type TestPagination struct {
+	Page  int `json:"page" validate:"min=1"`
+	Limit int `json:"limit" validate:"min=1,max=100"`
+}
+
+type TestListUsersQuery struct {
+	TestPagination
+	Role string `json:"role" validate:"required,enum=user|admin"`
+}
+
+func TestSchema_Merge(t *testing.T) {
+	base := NewSchema(TestPagination{})
+	schema := NewSchema(TestListUsersQuery{}).Merge(base)
+
+	if len(schema.fields) != 3 {
+		t.Fatalf("Expected 3 fields after merge, got %d", len(schema.fields))
+	}
+
+	// Satisfies both schemas
+	valid := TestListUsersQuery{
+		TestPagination: TestPagination{Page: 1, Limit: 20},
+		Role:           "admin",
+	}
+	if errs := schema.Validate(valid); len(errs) != 0 {
+		t.Errorf("Expected no validation errors, got: %v", errs)
+	}
+
+	// Violates rules from both the base and the specific schema
+	invalid := TestListUsersQuery{
+		TestPagination: TestPagination{Page: 0, Limit: 500},
+		Role:           "guest",
+	}
+	errs := schema.Validate(invalid)
+	if len(errs) != 3 {
+		t.Fatalf("Expected 3 validation errors, got %d: %v", len(errs), errs)
+	}
+
+	tags := map[string]string{}
+	for _, err := range errs {
+		tags[err.Field] = err.Tag
+	}
+	if tags["page"] != "min" || tags["limit"] != "max" || tags["role"] != "enum" {
+		t.Errorf("Unexpected validation errors: %v", errs)
+	}
+}
+
+func TestSchema_Merge_ConflictPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic when merging schemas with conflicting fields")
+		}
+	}()
+
+	NewSchema(TestPagination{}).Merge(NewSchema(TestPagination{}))
+}
+
+type TestArrayQuery struct {
+	Tags []string `json:"tags"`
+	IDs  []int    `json:"ids" query:"id"`
+	Sort string   `json:"sort"`
+}
+
+func TestValidateQuery_ArraySyntaxes(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{"repeated keys", "tags=a&tags=b&tags=c", []string{"a", "b", "c"}},
+		{"empty brackets", "tags[]=a&tags[]=b&tags[]=c", []string{"a", "b", "c"}},
+		{"indexes", "tags[0]=a&tags[1]=b&tags[2]=c", []string{"a", "b", "c"}},
+		{"indexes out of order", "tags[2]=c&tags[0]=a&tags[1]=b", []string{"a", "b", "c"}},
+		{"index gaps closed up", "tags[7]=c&tags[0]=a&tags[3]=b", []string{"a", "b", "c"}},
+		{"numeric index order", "tags[10]=c&tags[9]=b&tags[1]=a", []string{"a", "b", "c"}},
+		{"repeated index", "tags[1]=c&tags[0]=a&tags[0]=b", []string{"a", "b", "c"}},
+		{"mixed syntaxes", "tags[0]=c&tags[]=b&tags=a", []string{"a", "b", "c"}},
+		{"single value", "tags=a", []string{"a"}},
+		{"absent", "sort=name", nil},
+		{"non-index brackets ignored", "tags[x]=a&tags[-1]=b&tags[0]=c", []string{"c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var target TestArrayQuery
+			if err := ValidateQuery(query, &target, NewSchema(TestArrayQuery{})); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !slices.Equal(target.Tags, tt.expected) {
+				t.Errorf("Expected tags %q, got %q", tt.expected, target.Tags)
+			}
+		})
+	}
+}
+
+func TestValidateQuery_ArrayConversion(t *testing.T) {
+	query, _ := url.ParseQuery("id[1]=20&id[0]=10&tags[]=x")
+
+	var target TestArrayQuery
+	schema := NewSchema(TestArrayQuery{}).StrictQuery()
+	if err := ValidateQuery(query, &target, schema); err != nil {
+		t.Fatalf("Expected bracketed keys to be recognized in strict mode, got %v", err)
+	}
+	if !slices.Equal(target.IDs, []int{10, 20}) {
+		t.Errorf("Expected ids [10 20] from the query tag name, got %v", target.IDs)
+	}
+
+	query, _ = url.ParseQuery("id[]=10&id[]=ten")
+	err := ValidateQuery(query, &target, NewSchema(TestArrayQuery{}))
+	if err == nil || !strings.Contains(err.Error(), "[1]: invalid integer value: ten") {
+		t.Errorf("Expected the bad element to be reported, got %v", err)
+	}
+
+	query, _ = url.ParseQuery("sort[]=name&tags[x]=a")
+	errs, ok := ValidateQuery(query, &target, NewSchema(TestArrayQuery{}).StrictQuery()).(ValidationErrors)
+	if !ok || !errs.Has("tags[x]") || !errs.Has("sort[]") {
+		t.Errorf("Expected tags[x] and sort[] (not a slice) to be unknown in strict mode, got %v", errs)
+	}
+}
+
+func TestValidateQuery_UnknownParams(t *testing.T) {
+	queryParams := map[string][]string{
+		"query": {"laptop"},
+		"limit": {"10"},
+		"page":  {"1"},
+		"pag":   {"2"}, // typo for "page"
+	}
+
+	// Lenient (default): unknown params are ignored
+	var lenient TestSearchQuery
+	if err := ValidateQuery(queryParams, &lenient, NewSchema(TestSearchQuery{})); err != nil {
+		t.Errorf("Expected no error in lenient mode, got: %v", err)
+	}
+
+	// Strict: unknown params are rejected
+	var strict TestSearchQuery
+	err := ValidateQuery(queryParams, &strict, NewSchema(TestSearchQuery{}).StrictQuery())
+
+	validationErrors, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatalf("Expected ValidationErrors, got %T: %v", err, err)
+	}
+	if len(validationErrors) != 1 {
+		t.Fatalf("Expected 1 validation error, got %d: %v", len(validationErrors), validationErrors)
+	}
+	if validationErrors[0].Field != "pag" || validationErrors[0].Tag != "unknown" {
+		t.Errorf("Expected unknown error for 'pag', got: %v", validationErrors[0])
+	}
+
+	// Strict: known params (including query tag names) are accepted
+	var known TestQuery
+	knownParams := map[string][]string{"page": {"2"}, "limit": {"10"}, "sort": {"name"}}
+	if err := ValidateQuery(knownParams, &known, NewSchema(TestQuery{}).StrictQuery()); err != nil {
+		t.Errorf("Expected no error for known params in strict mode, got: %v", err)
+	}
+}
+
+// Test struct for programmatic conditional requiredness
+type TestAccount struct {
+	Country     string `json:"country" validate:"required"`
+	AccountType string `json:"account_type" validate:"required,enum=personal|business"`
+	VATNumber   string `json:"vat_number" validate:"minlen=8"`
+}
+
+func TestSchema_RequireWhen(t *testing.T) {
+	schema := NewSchema(TestAccount{}).RequireWhen("vat_number", func(fields map[string]any) bool {
+		return fields["country"] == "DE" && fields["account_type"] == "business"
+	})
+
+	tests := []struct {
+		name        string
+		account     TestAccount
+		expectError bool
+	}{
+		{"both conditions met, missing", TestAccount{Country: "DE", AccountType: "business"}, true},
+		{"both conditions met, present", TestAccount{Country: "DE", AccountType: "business", VATNumber: "DE123456789"}, false},
+		{"only country matches", TestAccount{Country: "DE", AccountType: "personal"}, false},
+		{"only account type matches", TestAccount{Country: "US", AccountType: "business"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := schema.Validate(tt.account)
+			if tt.expectError {
+				if len(errs) != 1 || errs[0].Field != "vat_number" || errs[0].Tag != "required" {
+					t.Errorf("Expected required error for vat_number, got: %v", errs)
+				}
+			} else if len(errs) != 0 {
+				t.Errorf("Expected no validation errors, got: %v", errs)
+			}
+		})
+	}
+}
+
+func TestSchema_RequireWhen_NonExistentField_Panic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic when adding condition to non-existent field")
+		}
+	}()
+
+	NewSchema(TestAccount{}).RequireWhen("nonexistent", func(map[string]any) bool { return true })
+}
+
+func TestSchema_ValidateMap(t *testing.T) {
+	userSchema := NewSchema(TestUser{})
+	contactSchema := NewSchema(TestContact{})
+
+	tests := []struct {
+		name      string
+		schema    *Schema
+		data      map[string]any
+		wantField string
+		wantTag   string
+	}{
+		{
+			name:   "valid payload",
+			schema: userSchema,
+			data: map[string]any{
+				"name": "John Doe", "email": "john@example.com", "age": float64(30), "role": "admin", "password": "password123",
+			},
+		},
+		{
+			name:      "missing required",
+			schema:    userSchema,
+			data:      map[string]any{"email": "john@example.com", "password": "password123"},
+			wantField: "name",
+			wantTag:   "required",
+		},
+		{
+			name:      "below min",
+			schema:    userSchema,
+			data:      map[string]any{"name": "John", "email": "john@example.com", "password": "password123", "age": float64(17)},
+			wantField: "age",
+			wantTag:   "min",
+		},
+		{
+			name:      "above max",
+			schema:    userSchema,
+			data:      map[string]any{"name": "John", "email": "john@example.com", "password": "password123", "age": 121},
+			wantField: "age",
+			wantTag:   "max",
+		},
+		{
+			name:      "not in enum",
+			schema:    userSchema,
+			data:      map[string]any{"name": "John", "email": "john@example.com", "password": "password123", "role": "root"},
+			wantField: "role",
+			wantTag:   "enum",
+		},
+		{
+			name:      "pattern mismatch",
+			schema:    contactSchema,
+			data:      map[string]any{"name": "John", "email": "john@example.com", "phone": "5551234567"},
+			wantField: "phone",
+			wantTag:   "pattern",
+		},
+		{
+			name:      "string where number expected",
+			schema:    userSchema,
+			data:      map[string]any{"name": "John", "email": "john@example.com", "password": "password123", "age": "twenty"},
+			wantField: "age",
+			wantTag:   "type",
+		},
+		{
+			name:      "number where string expected",
+			schema:    userSchema,
+			data:      map[string]any{"name": float64(42), "email": "john@example.com", "password": "password123"},
+			wantField: "name",
+			wantTag:   "type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.schema.ValidateMap(tt.data)
+
+			if tt.wantField == "" {
+				if len(errs) != 0 {
+					t.Errorf("Expected no validation errors, got: %v", errs)
+				}
+				return
+			}
+
+			if len(errs) != 1 {
+				t.Fatalf("Expected 1 validation error, got %d: %v", len(errs), errs)
+			}
+			if errs[0].Field != tt.wantField || errs[0].Tag != tt.wantTag {
+				t.Errorf("Expected %s error on %s, got %s on %s", tt.wantTag, tt.wantField, errs[0].Tag, errs[0].Field)
+			}
+		})
+	}
+}
+
+// Test struct for email policies
+type TestEmailContacts struct {
+	WorkEmail     string `json:"work_email" validate:"required,email"`
+	PersonalEmail string `json:"personal_email" validate:"email"`
+}
+
+func TestSchema_EmailPolicy(t *testing.T) {
+	lenient := NewSchema(TestEmailContacts{})
+	strict := NewSchema(TestEmailContacts{}).EmailPolicy(EmailPolicy{
+		DisallowPlusAddressing: true,
+		AllowedDomains:         []string{"example.com", "corp.example.com"},
+	}, "work_email")
+
+	tests := []struct {
+		name       string
+		schema     *Schema
+		contacts   TestEmailContacts
+		errorCount int
+		message    string
+	}{
+		{"plus-addressing allowed by default", lenient, TestEmailContacts{WorkEmail: "jane+news@example.com"}, 0, ""},
+		{"plus-addressing rejected by policy", strict, TestEmailContacts{WorkEmail: "jane+news@example.com"}, 1, "work_email must not use plus-addressing"},
+		{"allowed domain", strict, TestEmailContacts{WorkEmail: "jane@Corp.Example.com"}, 0, ""},
+		{"domain not in allowlist", strict, TestEmailContacts{WorkEmail: "jane@gmail.com"}, 1, "work_email must use one of the allowed domains: example.com, corp.example.com"},
+		{"policy limited to named field", strict, TestEmailContacts{WorkEmail: "jane@example.com", PersonalEmail: "jane+x@gmail.com"}, 0, ""},
+		{"invalid format reported once", strict, TestEmailContacts{WorkEmail: "not-an-email"}, 1, "work_email must be a valid email"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.schema.Validate(tt.contacts)
+			if len(errs) != tt.errorCount {
+				t.Fatalf("Expected %d errors, got %d: %v", tt.errorCount, len(errs), errs)
+			}
+			if tt.errorCount > 0 && (errs[0].Message != tt.message || errs[0].Tag != "email") {
+				t.Errorf("Expected email error %q, got %s: %q", tt.message, errs[0].Tag, errs[0].Message)
+			}
+		})
+	}
+}
+
+func TestSchema_EmailPolicy_AllEmailFields(t *testing.T) {
+	schema := NewSchema(TestEmailContacts{}).EmailPolicy(EmailPolicy{DisallowPlusAddressing: true})
+
+	errs := schema.Validate(TestEmailContacts{WorkEmail: "jane+a@example.com", PersonalEmail: "jane+b@example.com"})
+	if len(errs) != 2 {
+		t.Errorf("Expected both email fields to be checked, got: %v", errs)
+	}
+}
+
+func TestSchema_EmailPolicy_NonEmailFieldPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic when applying an email policy to a non-email field")
+		}
+	}()
+
+	NewSchema(TestUser{}).EmailPolicy(EmailPolicy{}, "name")
+}
+
+func TestValidateJSON_TopLevelArray(t *testing.T) {
+	schema := NewSchema(TestUser{})
+
+	t.Run("valid elements", func(t *testing.T) {
+		data := []byte(` [
+			{"name":"John Doe","email":"john@example.com","age":25,"role":"user","password":"password123"},
+			{"name":"Jane Doe","email":"jane@example.com","age":30,"role":"admin","password":"password456"}
+		]`)
+
+		var users []TestUser
+		if err := ValidateJSON(data, &users, schema); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if len(users) != 2 || users[1].Name != "Jane Doe" {
+			t.Errorf("Expected 2 bound users, got %+v", users)
+		}
+	})
+
+	t.Run("indexed element errors", func(t *testing.T) {
+		data := []byte(`[
+			{"name":"John Doe","email":"john@example.com","age":25,"role":"user","password":"password123"},
+			{"name":"Jane Doe","email":"not-an-email","age":30,"role":"user","password":"password456"},
+			{"email":"sam@example.com","age":40,"role":"user","password":"password789"}
+		]`)
+
+		var users []TestUser
+		err := ValidateJSON(data, &users, schema)
+
+		validationErrors, ok := err.(ValidationErrors)
+		if !ok {
+			t.Fatalf("Expected ValidationErrors, got %T: %v", err, err)
+		}
+
+		fields := make(map[string]string)
+		for _, e := range validationErrors {
+			fields[e.Field] = e.Tag
+		}
+		if len(fields) != 2 || fields["[1].email"] != "email" || fields["[2].name"] != "required" {
+			t.Errorf("Expected errors on [1].email and [2].name, got: %v", validationErrors)
+		}
+	})
+
+	t.Run("element custom validation", func(t *testing.T) {
+		data := []byte(`[{"name":"Kid","email":"kid@example.com","age":19,"role":"admin","password":"password123"}]`)
+
+		var users []*TestUser
+		err := ValidateJSON(data, &users, schema)
+		if err == nil || !strings.Contains(err.Error(), "[0]: admin must be at least 21") {
+			t.Errorf("Expected indexed custom validation error, got: %v", err)
+		}
+	})
+
+	t.Run("array into struct target", func(t *testing.T) {
+		var user TestUser
+		err := ValidateJSON([]byte(`[{"name":"John"}]`), &user, schema)
+		if err == nil || !strings.Contains(err.Error(), "invalid JSON") {
+			t.Errorf("Expected invalid JSON error for array into struct, got: %v", err)
+		}
+	})
+}
+
+// Test struct for validation groups
+type TestUserRequest struct {
+	Username string `json:"username" validate:"required#create,minlen=3"`
+	Email    string `json:"email" validate:"required#create#invite,email"`
+	Age      int    `json:"age" validate:"min=13#create"`
+	Color    string `json:"color" validate:"pattern=^#[0-9a-f]{6}$"`
+}
+
+func TestSchema_WithGroup(t *testing.T) {
+	base := NewSchema(TestUserRequest{})
+	create := base.WithGroup("create")
+	update := base.WithGroup("update")
+	invite := base.WithGroup("invite")
+
+	partial := TestUserRequest{Username: "jane", Age: 20}
+
+	if errs := update.Validate(partial); len(errs) != 0 {
+		t.Errorf("Expected update to treat email as optional, got: %v", errs)
+	}
+	if errs := base.Validate(partial); len(errs) != 0 {
+		t.Errorf("Expected schema without a group to skip tagged rules, got: %v", errs)
+	}
+
+	errs := create.Validate(partial)
+	if len(errs) != 1 || errs[0].Field != "email" || errs[0].Tag != "required" {
+		t.Errorf("Expected create to require email, got: %v", errs)
+	}
+
+	errs = invite.Validate(TestUserRequest{})
+	if len(errs) != 1 || errs[0].Field != "email" {
+		t.Errorf("Expected invite to require only email, got: %v", errs)
+	}
+
+	// Untagged rules apply in every group
+	for name, schema := range map[string]*Schema{"create": create, "update": update} {
+		errs := schema.Validate(TestUserRequest{Username: "jo", Email: "jo@example.com", Age: 20})
+		if len(errs) != 1 || errs[0].Tag != "minlen" {
+			t.Errorf("Expected %s to enforce untagged minlen, got: %v", name, errs)
+		}
+	}
+
+	// Tagged numeric rules and pattern rules containing '#'
+	errs = create.Validate(TestUserRequest{Username: "jane", Email: "jane@example.com", Age: 10, Color: "red"})
+	if len(errs) != 2 {
+		t.Errorf("Expected min and pattern errors under create, got: %v", errs)
+	}
+	if errs := update.Validate(TestUserRequest{Age: 10, Color: "#ff0000"}); len(errs) != 0 {
+		t.Errorf("Expected update to skip create-only min and accept the color, got: %v", errs)
+	}
+}
+
+func TestSchema_WithGroupBindsPathParams(t *testing.T) {
+	type GroupedParams struct {
+		ID string `path:"id" json:"id" validate:"minlen=3#create"`
+	}
+
+	schema := NewSchema(GroupedParams{}).WithGroup("create")
+	validator := &Validator[GroupedParams]{Schema: schema, Factory: func() *GroupedParams { return new(GroupedParams) }}
+
+	var bound *GroupedParams
+	router := NewRouter()
+	router.GET("/users/:id", func(ctx *Context) (any, int, error) {
+		bound, _ = ValidatedParams[GroupedParams](ctx)
+		return nil, http.StatusOK, nil
+	}, WithMiddleware(WithPathParams(validator)))
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/usr_42", nil))
+
+	if bound == nil || bound.ID != "usr_42" {
+		t.Errorf("Expected the grouped schema to bind id usr_42, got %+v", bound)
+	}
+	if errs := schema.Validate(GroupedParams{ID: "ab"}); !errs.Has("id") {
+		t.Errorf("Expected the create group's minlen to apply, got %v", errs)
+	}
+}
+
+func TestValidateQuery_DuplicateParams(t *testing.T) {
+	queryParams := map[string][]string{
+		"query": {"laptop"},
+		"limit": {"10"},
+		"page":  {"1", "2"},
+	}
+
+	// Lenient (default): the first value wins
+	var lenient TestSearchQuery
+	if err := ValidateQuery(queryParams, &lenient, NewSchema(TestSearchQuery{})); err != nil {
+		t.Fatalf("Expected no error in lenient mode, got: %v", err)
+	}
+	if lenient.Page != 1 {
+		t.Errorf("Expected first value (1) to be bound, got %d", lenient.Page)
+	}
+
+	// Strict: repeated scalar params are rejected
+	var strict TestSearchQuery
+	err := ValidateQuery(queryParams, &strict, NewSchema(TestSearchQuery{}).RejectDuplicateQuery())
+
+	validationErrors, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatalf("Expected ValidationErrors, got %T: %v", err, err)
+	}
+	if len(validationErrors) != 1 {
+		t.Fatalf("Expected 1 validation error, got %d: %v", len(validationErrors), validationErrors)
+	}
+	if validationErrors[0].Field != "page" || validationErrors[0].Tag != "duplicate" {
+		t.Errorf("Expected duplicate error for 'page', got: %v", validationErrors[0])
+	}
+
+	// Strict: single values are accepted
+	queryParams["page"] = []string{"2"}
+	var single TestSearchQuery
+	if err := ValidateQuery(queryParams, &single, NewSchema(TestSearchQuery{}).RejectDuplicateQuery()); err != nil {
+		t.Errorf("Expected no error for single values, got: %v", err)
+	}
+}
+
+// Wide struct for schema field lookup benchmarks
+type TestWideStruct struct {
+	Field01 string `json:"field_01" validate:"required,minlen=1"`
+	Field02 string `json:"field_02" validate:"maxlen=50"`
+	Field03 string `json:"field_03" validate:"email"`
+	Field04 string `json:"field_04" validate:"enum=a|b|c"`
+	Field05 string `json:"field_05"`
+	Field06 int    `json:"field_06" validate:"min=0"`
+	Field07 int    `json:"field_07" validate:"max=100"`
+	Field08 int    `json:"field_08" validate:"min=1,max=10"`
+	Field09 int    `json:"field_09"`
+	Field10 int    `json:"field_10"`
+	Field11 string `json:"field_11" validate:"required"`
+	Field12 string `json:"field_12" validate:"minlen=2"`
+	Field13 string `json:"field_13"`
+	Field14 string `json:"field_14"`
+	Field15 string `json:"field_15"`
+	Field16 int    `json:"field_16" validate:"min=0"`
+	Field17 int    `json:"field_17"`
+	Field18 int    `json:"field_18"`
+	Field19 int    `json:"field_19"`
+	Field20 string `json:"field_20" validate:"required"`
+}
+
+func newTestWideStruct() TestWideStruct {
+	return TestWideStruct{
+		Field01: "a", Field02: "b", Field03: "wide@example.com", Field04: "a", Field08: 5,
+		Field11: "k", Field12: "kk", Field20: "t",
+	}
+}
+
+func TestSchema_CachedFieldIndex(t *testing.T) {
+	schemas := map[string]*Schema{
+		"wide":     NewSchema(TestWideStruct{}),
+		"user":     NewSchema(TestUser{}),
+		"embedded": NewSchema(TestListUsersQuery{}).Merge(NewSchema(TestPagination{})),
+	}
+
+	for name, schema := range schemas {
+		t.Run(name, func(t *testing.T) {
+			for fieldName, rule := range schema.fields {
+				cached, ok := schema.structField(rule)
+				scanned, scannedOK := schema.structType.FieldByName(getStructFieldName(schema.structType, fieldName, nil))
+
+				if ok != scannedOK || cached.Name != scanned.Name {
+					t.Errorf("Field %s: cached index resolves to %q, scan resolves to %q", fieldName, cached.Name, scanned.Name)
+				}
+			}
+		})
+	}
+
+	// Validating through the cached index and through a by-name lookup gives identical results
+	schema := NewSchema(TestWideStruct{})
+	invalid := newTestWideStruct()
+	invalid.Field01, invalid.Field03, invalid.Field08 = "", "bad", 11
+
+	type embedding struct{ TestWideStruct }
+	for _, data := range []TestWideStruct{newTestWideStruct(), invalid} {
+		cached := errorTags(schema.Validate(data))
+		byName := errorTags(schema.Validate(embedding{data}))
+		if !slices.Equal(cached, byName) {
+			t.Errorf("Expected identical results, cached %v vs by name %v", cached, byName)
+		}
+	}
+}
+
+func errorTags(errs ValidationErrors) []string {
+	tags := make([]string, 0, len(errs))
+	for _, e := range errs {
+		tags = append(tags, e.Field+":"+e.Tag)
+	}
+	slices.Sort(tags)
+	return tags
+}
+
+func BenchmarkSchema_Validate_WideStruct(b *testing.B) {
+	schema := NewSchema(TestWideStruct{})
+	data := newTestWideStruct()
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		schema.Validate(&data)
+	}
+}
+
+type TestBindingKeys struct {
+	Search   string `json:"search" query:"q" form:"search_term"`
+	Page     int    `json:"page" validate:"min=1"`
+	OrgID    string `json:"org_id" path:"org"`
+	ItemID   string `path:"item"`
+	internal string `path:"internal"`
+}
+
+func TestSchema_CachedBindingKeys(t *testing.T) {
+	schema := NewSchema(TestBindingKeys{})
+
+	// Cached query/form names match a per-call tag lookup, including the JSON fallback
+	for fieldName, rule := range schema.fields {
+		field, _ := schema.structField(rule)
+		for tag, cached := range map[string]string{"query": rule.queryName, "form": rule.formName} {
+			want := field.Tag.Get(tag)
+			if want == "" {
+				want = fieldName
+			}
+			if cached != want {
+				t.Errorf("Field %s: expected cached %s name %q, got %q", fieldName, tag, want, cached)
+			}
+		}
+	}
+
+	query := url.Values{"q": {"shoes"}, "page": {"2"}}
+	var bound TestBindingKeys
+	if errs := ValidateQuery(query, &bound, schema); errs != nil {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+	if bound.Search != "shoes" || bound.Page != 2 {
+		t.Errorf("Expected search=shoes page=2, got %+v", bound)
+	}
+
+	// Path binding through the cached fields matches the uncached scan, errors included
+	for _, params := range []map[string]string{
+		{"org": "acme", "item": "42", "internal": "x"},
+		{"org": "acme"},
+	} {
+		var cached, scanned TestBindingKeys
+		cachedErr := populatePathParamsWithSchema(params, &cached, schema)
+		scannedErr := populatePathParams(params, &scanned)
+
+		if cached != scanned || fmt.Sprint(cachedErr) != fmt.Sprint(scannedErr) {
+			t.Errorf("Params %v: cached (%+v, %v) differs from scan (%+v, %v)", params, cached, cachedErr, scanned, scannedErr)
+		}
+	}
+}
+
+func BenchmarkValidateQuery(b *testing.B) {
+	schema := NewSchema(TestSearchQuery{})
+	query := url.Values{
+		"query":     {"laptop"},
+		"category":  {"electronics"},
+		"min_price": {"100"},
+		"max_price": {"2000"},
+		"page":      {"2"},
+		"limit":     {"20"},
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var target TestSearchQuery
+		ValidateQuery(query, &target, schema)
+	}
+}
+
+func BenchmarkPopulatePathParams(b *testing.B) {
+	schema := NewSchema(TestBindingKeys{})
+	params := map[string]string{"org": "acme", "item": "42"}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var target TestBindingKeys
+		populatePathParamsWithSchema(params, &target, schema)
+	}
+}
+
+type TestProfilePatch struct {
+	Name string `json:"name" validate:"minlen=2"`
+	Age  int    `json:"age" validate:"min=0,max=150"`
+}
+
+func TestValidateJSONWithPresence(t *testing.T) {
+	schema := NewSchema(TestProfilePatch{})
+
+	tests := []struct {
+		name        string
+		body        string
+		wantPresent []string
+		wantAbsent  []string
+	}{
+		{"zero value is present", `{"age":0}`, []string{"age"}, []string{"name"}},
+		{"omitted field is absent", `{}`, nil, []string{"name", "age"}},
+		{"null is present", `{"name":null,"age":30}`, []string{"name", "age"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch TestProfilePatch
+			present, err := ValidateJSONWithPresence([]byte(tt.body), &patch, schema)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			for _, field := range tt.wantPresent {
+				if !present.Has(field) {
+					t.Errorf("Expected %s to be present in %s", field, tt.body)
+				}
+			}
+			for _, field := range tt.wantAbsent {
+				if present.Has(field) {
+					t.Errorf("Expected %s to be absent from %s", field, tt.body)
+				}
+			}
+		})
+	}
+
+	// Presence is reported alongside validation errors
+	var patch TestProfilePatch
+	present, err := ValidateJSONWithPresence([]byte(`{"age":200}`), &patch, schema)
+	if _, ok := err.(ValidationErrors); !ok {
+		t.Fatalf("Expected ValidationErrors, got %T: %v", err, err)
+	}
+	if !present.Has("age") {
+		t.Error("Expected age to be present despite validation failure")
+	}
+
+	// Invalid JSON yields no presence set
+	present, err = ValidateJSONWithPresence([]byte(`{`), &patch, schema)
+	if err == nil || present != nil {
+		t.Errorf("Expected error and nil set for invalid JSON, got %v, %v", present, err)
+	}
+}
+
+func TestSchema_ValidatePartial(t *testing.T) {
+	schema := NewSchema(TestUser{})
+
+	tests := []struct {
+		name       string
+		body       string
+		wantFields []string
+	}{
+		{"absent required fields are ignored", `{"age":30}`, nil},
+		{"present invalid field errors", `{"email":"not-an-email"}`, []string{"email:email"}},
+		{"present empty required field errors", `{"name":""}`, []string{"name:required"}},
+		{"only present fields are checked", `{"age":200,"role":"admin"}`, []string{"age:max"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var user TestUser
+			present, err := ValidateJSONPartial([]byte(tt.body), &user, schema)
+
+			if tt.wantFields == nil {
+				if err != nil {
+					t.Fatalf("Expected no error, got: %v", err)
+				}
+				return
+			}
+
+			validationErrors, ok := err.(ValidationErrors)
+			if !ok {
+				t.Fatalf("Expected ValidationErrors, got %T: %v", err, err)
+			}
+			if got := errorTags(validationErrors); !slices.Equal(got, tt.wantFields) {
+				t.Errorf("Expected errors %v, got %v", tt.wantFields, got)
+			}
+
+			// Same result when validating the bound struct directly
+			if got := errorTags(schema.ValidatePartial(&user, present)); !slices.Equal(got, tt.wantFields) {
+				t.Errorf("Expected ValidatePartial errors %v, got %v", tt.wantFields, got)
+			}
+		})
+	}
+
+	// A full validation of the same partial body still enforces required fields
+	var user TestUser
+	if err := ValidateJSON([]byte(`{"age":30}`), &user, schema); err == nil {
+		t.Error("Expected full validation to require absent fields")
+	}
+}
+
+type TestEventsQuery struct {
+	TTL   time.Duration `json:"ttl"`
+	Since time.Time     `json:"since"`
+	Day   time.Time     `json:"day" layout:"2006-01-02"`
+	Limit int           `json:"limit" validate:"min=1"`
+}
+
+func TestValidateQuery_TimeAndDuration(t *testing.T) {
+	schema := NewSchema(TestEventsQuery{})
+
+	var query TestEventsQuery
+	params := url.Values{
+		"ttl":   {"1h30m"},
+		"since": {"2024-01-01T00:00:00Z"},
+		"day":   {"2024-03-15"},
+		"limit": {"10"},
+	}
+	if err := ValidateQuery(params, &query, schema); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if query.TTL != 90*time.Minute {
+		t.Errorf("Expected ttl 1h30m, got %v", query.TTL)
+	}
+	if !query.Since.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected since 2024-01-01T00:00:00Z, got %v", query.Since)
+	}
+	if !query.Day.Equal(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected day 2024-03-15, got %v", query.Day)
+	}
+
+	tests := []struct {
+		name    string
+		params  url.Values
+		wantErr string
+	}{
+		{"malformed duration", url.Values{"ttl": {"30"}, "limit": {"1"}}, "error setting field ttl: invalid duration value: 30"},
+		{"malformed timestamp", url.Values{"since": {"yesterday"}, "limit": {"1"}}, "error setting field since: invalid time value: yesterday (expected layout " + time.RFC3339 + ")"},
+		{"timestamp not in custom layout", url.Values{"day": {"2024-03-15T00:00:00Z"}, "limit": {"1"}}, "expected layout 2006-01-02"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query TestEventsQuery
+			err := ValidateQuery(tt.params, &query, schema)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSchema_FailFast(t *testing.T) {
+	// Every field breaks a rule; name breaks two
+	invalid := &TestUser{Name: "A", Email: "not-an-email", Age: 12, Role: "owner"}
+
+	all := NewSchema(TestUser{}).Validate(invalid)
+	if len(all) < 5 {
+		t.Fatalf("Expected the default schema to report every field, got %v", all)
+	}
+
+	errs := NewSchema(TestUser{}).FailFast().Validate(invalid)
+	if len(errs) != 1 {
+		t.Fatalf("Expected exactly one error, got %v", errs)
+	}
+	first := errs[0]
+	if first.Field == "" || first.Tag == "" || first.Message == "" || errs.Error() != first.Message {
+		t.Errorf("Expected a well-formed error, got %+v", first)
+	}
+	if !slices.ContainsFunc(all, func(e ValidationError) bool {
+		return e.Field == first.Field && e.Tag == first.Tag && e.Message == first.Message
+	}) {
+		t.Errorf("Expected %+v to be one of the default errors %v", first, all)
+	}
+
+	if errs := NewSchema(TestUser{}).FailFast().Validate(&TestUser{Name: "Ada", Email: "ada@example.com", Age: 30, Password: "analytical"}); len(errs) != 0 {
+		t.Errorf("Expected no errors for a valid struct, got %v", errs)
+	}
+}
+
+func TestSchema_FailFastSkipsRemainingWork(t *testing.T) {
+	var customCalls int
+	expensive := func(any) error {
+		customCalls++
+		return errors.New("rejected by custom check")
+	}
+
+	// name fails its built-in minlen rule, so its custom validator is skipped
+	schema := NewSchema(TestUser{}).FailFast().AddCustomValidator("name", expensive)
+	errs := schema.Validate(&TestUser{Name: "A", Email: "ada@example.com", Age: 30, Password: "analytical"})
+	if len(errs) != 1 || errs[0].Tag != "minlen" {
+		t.Fatalf("Expected only the minlen error, got %v", errs)
+	}
+	if customCalls != 0 {
+		t.Errorf("Expected the custom validator to be skipped, got %d calls", customCalls)
+	}
+
+	// Each field passes its built-in rules, so validation stops at the first custom failure
+	for _, field := range []string{"email", "role", "password"} {
+		schema.AddCustomValidator(field, expensive)
+	}
+	errs = schema.Validate(&TestUser{Name: "Ada", Email: "ada@example.com", Age: 30, Role: "user", Password: "analytical"})
+	if len(errs) != 1 || errs[0].Tag != "custom" {
+		t.Fatalf("Expected one custom error, got %v", errs)
+	}
+	if customCalls != 1 {
+		t.Errorf("Expected one custom validator call before stopping, got %d", customCalls)
+	}
+
+	mapErrs := schema.ValidateMap(map[string]any{"name": "A", "email": "bad", "age": 1})
+	if len(mapErrs) != 1 {
+		t.Errorf("Expected ValidateMap to stop at one error, got %v", mapErrs)
+	}
+
+	err := ValidateJSON([]byte(`[{"name": "A"}, {"name": "B"}]`), &[]TestUser{}, NewSchema(TestUser{}).FailFast())
+	if arrayErrs, ok := err.(ValidationErrors); !ok || len(arrayErrs) != 1 || !strings.HasPrefix(arrayErrs[0].Field, "[0].") {
+		t.Errorf("Expected one error from the first element, got %v", err)
+	}
+}
+
+// fastProduct has the same rules as TestProduct, also checked by hand in ValidateFast
+type fastProduct struct {
+	Name     string  `json:"name" validate:"required,maxlen=100"`
+	Price    float64 `json:"price" validate:"min=0"`
+	Category string  `json:"category" validate:"required"`
+
+	fastCalls *int
+}
+
+func (p *fastProduct) ValidateFast() ValidationErrors {
+	*p.fastCalls++
+
+	var errs ValidationErrors
+	if p.Name == "" {
+		errs = append(errs, ValidationError{Field: "name", Value: p.Name, Tag: "required", Message: "name is required"})
+	} else if utf8.RuneCountInString(p.Name) > 100 {
+		errs = append(errs, ValidationError{Field: "name", Value: p.Name, Tag: "maxlen", Message: "name must be at most 100 characters"})
+	}
+	if p.Price < 0 {
+		errs = append(errs, ValidationError{Field: "price", Value: p.Price, Tag: "min", Message: "price must be at least 0"})
+	}
+	if p.Category == "" {
+		errs = append(errs, ValidationError{Field: "category", Value: p.Category, Tag: "required", Message: "category is required"})
+	}
+	return errs
+}
+
+func TestSchema_FastValidatable(t *testing.T) {
+	describe := func(errs ValidationErrors) []string {
+		var described []string
+		for _, e := range errs {
+			described = append(described, e.Field+":"+e.Tag+":"+e.Message)
+		}
+		slices.Sort(described)
+		return described
+	}
+
+	tests := []struct {
+		name    string
+		product fastProduct
+	}{
+		{"valid", fastProduct{Name: "Lamp", Price: 20, Category: "home"}},
+		{"missing fields", fastProduct{Price: 5}},
+		{"out of bounds", fastProduct{Name: strings.Repeat("x", 101), Price: -1, Category: "home"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fastCalls, reflectiveCalls int
+			product := tt.product
+			product.fastCalls = &fastCalls
+
+			// The custom validator only runs on the reflective path
+			schema := NewSchema(fastProduct{}).AddCustomValidator("name", func(any) error {
+				reflectiveCalls++
+				return nil
+			})
+			reference := NewSchema(fastProduct{}) // validates a value, which lacks ValidateFast
+
+			got := schema.Validate(&product)
+			expected := reference.Validate(product)
+
+			if fastCalls != 1 || reflectiveCalls != 0 {
+				t.Errorf("Expected only ValidateFast to run, got %d fast and %d reflective calls", fastCalls, reflectiveCalls)
+			}
+			if !slices.Equal(describe(got), describe(expected)) {
+				t.Errorf("Expected the reflective errors %v, got %v", describe(expected), describe(got))
+			}
+		})
+	}
+}
+
+func TestValidateJSON_FastValidatable(t *testing.T) {
+	var fastCalls int
+	product := &fastProduct{fastCalls: &fastCalls}
+
+	err := ValidateJSON([]byte(`{"name": "Lamp", "price": -3}`), product, NewSchema(fastProduct{}).FailFast())
+	errs, ok := err.(ValidationErrors)
+	if !ok || len(errs) != 1 {
+		t.Fatalf("Expected one validation error, got %v", err)
+	}
+	if fastCalls != 1 {
+		t.Errorf("Expected ValidateJSON to call ValidateFast once, got %d", fastCalls)
+	}
+
+	// Partial validation checks only the fields sent, so it keeps the reflective path
+	if _, err := ValidateJSONPartial([]byte(`{"price": 3}`), product, NewSchema(fastProduct{})); err != nil {
+		t.Errorf("Expected the partial update to pass, got %v", err)
+	}
+	if fastCalls != 1 {
+		t.Errorf("Expected ValidateJSONPartial to skip ValidateFast, got %d calls", fastCalls)
+	}
+}
+
+func TestSchema_ValidateCtx(t *testing.T) {
+	schema := NewSchema(TestWideStruct{})
+	data := newTestWideStruct()
+
+	// A live context validates every field, like Validate
+	errs, err := schema.ValidateCtx(context.Background(), &data)
+	if err != nil || len(errs) != 0 {
+		t.Fatalf("Expected valid data, got %v, %v", errs, err)
+	}
+
+	// An already-cancelled context aborts before any field is checked
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	invalid := TestWideStruct{}
+	errs, err = schema.ValidateCtx(cancelled, &invalid)
+	if !errors.Is(err, ErrValidationAborted) || !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected ErrValidationAborted wrapping context.Canceled, got %v", err)
+	}
+	if len(errs) != 0 {
+		t.Errorf("Expected no field errors before aborting, got %v", errs)
+	}
+
+	// Cancelling mid-validation stops at the next field
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	checked := 0
+	counting := func(any) error {
+		checked++
+		cancel()
+		return nil
+	}
+	for _, field := range []string{"field_05", "field_09", "field_13", "field_17"} {
+		schema.AddCustomValidator(field, counting)
+	}
+
+	_, err = schema.ValidateCtx(ctx, &data)
+	if !errors.Is(err, ErrValidationAborted) {
+		t.Errorf("Expected ErrValidationAborted, got %v", err)
+	}
+	if checked != 1 {
+		t.Errorf("Expected validation to stop after the first cancelling field, %d ran", checked)
+	}
+}
+
+type TestCreateUserV1 struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type TestCreateUserV2 struct {
+	FirstName string `json:"first_name" validate:"required"`
+	LastName  string `json:"last_name" validate:"required"`
+}
+
+func TestWithVersionedBody(t *testing.T) {
+	router := NewRouter()
+
+	versioned := WithVersionedBody(map[string]BodyValidator{
+		"v1": NewValidator(&TestCreateUserV1{}),
+		"v2": NewValidator(&TestCreateUserV2{}),
+	}, func(ctx *Context) string {
+		// Accept: application/vnd.app.v2+json
+		accept := ctx.GetHeader("Accept")
+		if _, rest, ok := strings.Cut(accept, "vnd.app."); ok {
+			version, _, _ := strings.Cut(rest, "+")
+			return version
+		}
+		return "v1"
+	})
+
+	router.POST("/users", func(ctx *Context) (any, int, error) {
+		switch body := ctx.MustGet(ContextKeyValidatedBody).(type) {
+		case *TestCreateUserV1:
+			return map[string]string{"name": body.Name}, http.StatusCreated, nil
+		case *TestCreateUserV2:
+			return map[string]string{"name": body.FirstName + " " + body.LastName}, http.StatusCreated, nil
+		default:
+			return nil, http.StatusInternalServerError, fmt.Errorf("unexpected body %T", body)
+		}
+	}, WithMiddleware(versioned))
+
+	tests := []struct {
+		name         string
+		accept       string
+		body         string
+		expectedCode int
+		expected     string
+	}{
+		{"default v1", "", `{"name":"Ada Lovelace"}`, http.StatusCreated, `"name":"Ada Lovelace"`},
+		{"explicit v1", "application/vnd.app.v1+json", `{"name":"Ada Lovelace"}`, http.StatusCreated, `"name":"Ada Lovelace"`},
+		{"v2", "application/vnd.app.v2+json", `{"first_name":"Ada","last_name":"Lovelace"}`, http.StatusCreated, `"name":"Ada Lovelace"`},
+		{"v2 schema applied", "application/vnd.app.v2+json", `{"name":"Ada Lovelace"}`, http.StatusBadRequest, `"validation_failed"`},
+		{"unknown version", "application/vnd.app.v3+json", `{"name":"Ada Lovelace"}`, http.StatusBadRequest, `"unsupported_version"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body))
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedCode, w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.expected) {
+				t.Errorf("Expected body to contain %s, got %s", tt.expected, w.Body.String())
+			}
+		})
+	}
+}
+
+type TestSortQuery struct {
+	Sort string `json:"sort" validate:"sortfields=name|created_at"`
+}
+
+func TestValidateQuery_SortFields(t *testing.T) {
+	schema := NewSchema(TestSortQuery{})
+
+	tests := []struct {
+		name    string
+		query   string
+		wantErr bool
+	}{
+		{"allowed ascending", "sort=name", false},
+		{"allowed descending", "sort=-created_at", false},
+		{"explicit ascending", "sort=%2Bcreated_at", false},
+		{"absent", "", false},
+		{"disallowed", "sort=password", true},
+		{"disallowed descending", "sort=-password", true},
+		{"bare prefix", "sort=-", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.query)
+			var query TestSortQuery
+			err := ValidateQuery(values, &query, schema)
+
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			validationErrs, ok := err.(ValidationErrors)
+			if !ok || len(validationErrs) != 1 || validationErrs[0].Tag != "sortfields" {
+				t.Fatalf("Expected a sortfields error, got %v", err)
+			}
+			expected := "sort must sort by one of: name, created_at (prefix with - for descending)"
+			if validationErrs[0].Message != expected {
+				t.Errorf("Expected message %q, got %q", expected, validationErrs[0].Message)
+			}
+		})
+	}
+}
+
+type TestStrictRequiredQuery struct {
+	Q     string `json:"q" validate:"required,minlen=2"`
+	Page  int    `json:"page" validate:"required,min=1"`
+	Limit int    `json:"limit" validate:"max=100"`
+}
+
+func TestValidateQuery_StrictRequired(t *testing.T) {
+	lenient := NewSchema(TestStrictRequiredQuery{})
+	strict := NewSchema(TestStrictRequiredQuery{}).StrictRequiredQuery()
+
+	tests := []struct {
+		name     string
+		schema   *Schema
+		query    string
+		expected map[string]string // field -> message of its required error
+	}{
+		{"strict absent", strict, "", map[string]string{"q": "q is required", "page": "page is required"}},
+		{"strict present but empty", strict, "q=&page=", map[string]string{"q": "q must not be empty", "page": "page must not be empty"}},
+		{"strict mixed", strict, "q=&page=2", map[string]string{"q": "q must not be empty"}},
+		{"strict satisfied", strict, "q=go&page=2", nil},
+		// By default empty and absent are the same, and a zero int satisfies required
+		{"lenient absent", lenient, "", map[string]string{"q": "q is required"}},
+		{"lenient present but empty", lenient, "q=&page=", map[string]string{"q": "q is required"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.query)
+			var query TestStrictRequiredQuery
+			err := ValidateQuery(values, &query, tt.schema)
+
+			var errs ValidationErrors
+			if err != nil {
+				var ok bool
+				if errs, ok = err.(ValidationErrors); !ok {
+					t.Fatalf("Expected ValidationErrors, got %T: %v", err, err)
+				}
+			}
+
+			var required ValidationErrors
+			for _, e := range errs {
+				if e.Tag == "required" {
+					required = append(required, e)
+				}
+			}
+			if len(required) != len(tt.expected) {
+				t.Fatalf("Expected required errors for %v, got %v", tt.expected, errs)
+			}
+			for field, message := range tt.expected {
+				fieldErrs := required.ForField(field)
+				if len(fieldErrs) != 1 || fieldErrs[0].Message != message {
+					t.Errorf("Expected one %q error for %s, got %v", message, field, fieldErrs)
+				}
+			}
+		})
+	}
+
+	// Other rules still apply alongside strict required checks
+	values, _ := url.ParseQuery("q=g&page=2")
+	var query TestStrictRequiredQuery
+	if errs, ok := ValidateQuery(values, &query, strict).(ValidationErrors); !ok || !errs.Has("q") || errs.ForField("q")[0].Tag != "minlen" {
+		t.Errorf("Expected minlen error for q, got %v", errs)
+	}
+}
+
+func TestSchema_WithGroupKeepsSchemaSettings(t *testing.T) {
+	grouped := NewSchema(TestUserParams{}).StrictRequiredQuery().WithGroup("create")
+
+	if !grouped.presentReq {
+		t.Error("Expected WithGroup to keep StrictRequiredQuery")
+	}
+	if len(grouped.pathFields) != 1 || grouped.pathFields[0].param != "id" {
+		t.Errorf("Expected WithGroup to keep path fields, got %v", grouped.pathFields)
+	}
+}
+
+type TestContactRequest struct {
+	Email           string `json:"email" validate:"required_without=phone"`
+	Phone           string `json:"phone" validate:"required_without=email"`
+	Password        string `json:"password"`
+	ConfirmPassword string `json:"confirm_password" validate:"required_with=password"`
+	Street          string `json:"street"`
+	City            string `json:"city"`
+	PostalCode      string `json:"postal_code" validate:"required_with_all=street|city"`
+	Fax             string `json:"fax"`
+	Pager           string `json:"pager"`
+	Reason          string `json:"reason" validate:"required_without_all=fax|pager"`
+	Nickname        string `json:"nickname"`
+	Handle          string `json:"handle"`
+	DisplayName     string `json:"display_name" validate:"required_with_any=nickname|handle"`
+}
+
+func TestValidate_RequiredWithWithout(t *testing.T) {
+	schema := NewSchema(TestContactRequest{})
+
+	// Satisfies every unconditional requirement; cases change one thing at a time
+	base := func() TestContactRequest {
+		return TestContactRequest{Email: "ada@example.com", Fax: "555-0100"}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*TestContactRequest)
+		missing []string
+	}{
+		{"base is valid", func(r *TestContactRequest) {}, nil},
+		{"required_without: neither email nor phone", func(r *TestContactRequest) { r.Email = "" }, []string{"email", "phone"}},
+		{"required_without: phone alone", func(r *TestContactRequest) { r.Email, r.Phone = "", "555-0199" }, nil},
+		{"required_with: password without confirmation", func(r *TestContactRequest) { r.Password = "secret" }, []string{"confirm_password"}},
+		{"required_with: password confirmed", func(r *TestContactRequest) { r.Password, r.ConfirmPassword = "secret", "secret" }, nil},
+		{"required_with_all: only street", func(r *TestContactRequest) { r.Street = "1 Main St" }, nil},
+		{"required_with_all: street and city", func(r *TestContactRequest) { r.Street, r.City = "1 Main St", "Springfield" }, []string{"postal_code"}},
+		{"required_without_all: pager present", func(r *TestContactRequest) { r.Fax, r.Pager = "", "555-0101" }, nil},
+		{"required_without_all: fax and pager absent", func(r *TestContactRequest) { r.Fax = "" }, []string{"reason"}},
+		{"required_with_any: handle present", func(r *TestContactRequest) { r.Handle = "ada" }, []string{"display_name"}},
+		{"required_with_any: satisfied", func(r *TestContactRequest) { r.Nickname, r.DisplayName = "Ada", "Ada L." }, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := base()
+			tt.modify(&req)
+
+			errs := schema.Validate(req)
+
+			if len(errs) != len(tt.missing) {
+				t.Fatalf("Expected required errors for %v, got %v", tt.missing, errs)
+			}
+			for _, field := range tt.missing {
+				if fieldErrs := errs.ForField(field); len(fieldErrs) != 1 || fieldErrs[0].Tag != "required" {
+					t.Errorf("Expected required error for %s, got %v", field, errs)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateJSON_RequiredWithUsesBodyPresence(t *testing.T) {
+	schema := NewSchema(TestContactRequest{})
+
+	// password is present (as an empty string), so confirm_password is required
+	var req TestContactRequest
+	err := ValidateJSON([]byte(`{"email":"ada@example.com","fax":"555-0100","password":""}`), &req, schema)
+	if errs, ok := err.(ValidationErrors); !ok || !errs.Has("confirm_password") {
+		t.Errorf("Expected confirm_password to be required when password is sent, got %v", err)
+	}
+
+	// Omitting password entirely lifts the requirement
+	err = ValidateJSON([]byte(`{"email":"ada@example.com","fax":"555-0100"}`), &req, schema)
+	if err != nil {
+		t.Errorf("Expected no error without password, got %v", err)
+	}
+}
+
+func TestValidateMap_RequiredWith(t *testing.T) {
+	schema := NewSchema(TestContactRequest{})
+
+	errs := schema.ValidateMap(map[string]any{"email": "ada@example.com", "fax": "555-0100", "password": "secret"})
+	if !errs.Has("confirm_password") {
+		t.Errorf("Expected confirm_password to be required, got %v", errs)
+	}
+}
+
+type TestProfile struct {
+	Nickname string `json:"nickname" validate:"minlen=2,maxlen=4"`
+	Column   string `json:"column" validate:"maxlen=4,bytelen"`
+}
+
+func TestValidate_LengthCountsRunes(t *testing.T) {
+	schema := NewSchema(TestProfile{})
+
+	tests := []struct {
+		name     string
+		profile  TestProfile
+		expected []string
+	}{
+		{"ascii at max", TestProfile{Nickname: "cafe"}, nil},
+		{"multibyte at max runes", TestProfile{Nickname: "café"}, nil}, // 4 runes, 5 bytes
+		{"cjk at max runes", TestProfile{Nickname: "東京都庁"}, nil},       // 4 runes, 12 bytes
+		{"multibyte over max runes", TestProfile{Nickname: "cafés"}, []string{"maxlen"}},
+		{"single multibyte rune under min", TestProfile{Nickname: "é"}, []string{"minlen"}}, // 2 bytes
+		{"emoji pair at min", TestProfile{Nickname: "🙂🙂"}, nil},
+		{"bytelen counts bytes", TestProfile{Nickname: "ok", Column: "café"}, []string{"maxlen"}},
+		{"bytelen at max bytes", TestProfile{Nickname: "ok", Column: "cafe"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := schema.Validate(tt.profile)
+
+			var tags []string
+			for _, err := range errs {
+				tags = append(tags, err.Tag)
+			}
+			if !slices.Equal(tags, tt.expected) {
+				t.Errorf("Expected errors %v, got %v", tt.expected, errs)
+			}
+		})
+	}
+}
+
+func TestValidate_LengthMessageUnits(t *testing.T) {
+	errs := NewSchema(TestProfile{}).Validate(TestProfile{Nickname: "cafés", Column: "naïve"})
+
+	if got := errs.ForField("nickname"); len(got) != 1 || got[0].Message != "nickname must be at most 4 characters" {
+		t.Errorf("Expected a characters message for nickname, got %v", got)
+	}
+	if got := errs.ForField("column"); len(got) != 1 || got[0].Message != "column must be at most 4 bytes" {
+		t.Errorf("Expected a bytes message for column, got %v", got)
+	}
+}